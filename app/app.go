@@ -3,12 +3,67 @@ package app
 import (
 	"ProductService/config"
 	"ProductService/db/connector"
+	"ProductService/db/migrations"
+	"ProductService/money"
+	"ProductService/utils/logger"
+	"log/slog"
+	"os"
 )
 
 func Start() {
-	config.InitializeEnv() //reading env
-	config.InitDB()        //establishing db connection
-	config.InitRedis()     //establishing redis connection
-	connector.Connector()
-	runserver()
+	cfg := config.InitializeEnv() //reading env
+	if err := logger.Init(cfg.LogLevel, cfg.LogFormat); err != nil {
+		slog.Error("Failed to initialize logger", "error", err)
+		os.Exit(1)
+	}
+	lifecycle := NewLifecycle(cfg.ShutdownTimeout)
+
+	config.InitDB(cfg) //establishing db connection
+	lifecycle.OnShutdown("postgres", config.PostgresConn.Close)
+	migrate(cfg) //bringing the schema up to date
+
+	config.InitRedis(cfg) //establishing redis connection
+	lifecycle.OnShutdown("redis", config.RedisClient.Close)
+
+	connector.Connector(cfg)
+	runserver(cfg, lifecycle)
+}
+
+func migrate(cfg *config.Config) {
+	if err := migrations.Up(config.PostgresConn); err != nil {
+		slog.Error("Failed to run migrations", "error", err)
+		os.Exit(1)
+	}
+
+	// sample data is only inserted when explicitly asked for
+	if cfg.SeedData {
+		if err := migrations.Seed(config.PostgresConn); err != nil {
+			slog.Error("Failed to seed data", "error", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// exchangeRates builds the converter behind ?currency, the service exits if the rates file or the rounding rules are invalid.
+// Rates are cached in Redis, so a changed file is only picked up once the cached rates expire.
+func exchangeRates(cfg *config.Config) *money.Converter {
+	rounding, err := money.ParseRounding(cfg.CurrencyRounding)
+	if err != nil {
+		slog.Error("Invalid CURRENCY_ROUNDING", "error", err)
+		os.Exit(1)
+	}
+
+	source := money.NewStaticProvider(nil)
+	if cfg.ExchangeRatesFile != "" {
+		source, err = money.LoadStaticProvider(cfg.ExchangeRatesFile)
+		if err != nil {
+			slog.Error("Failed to load exchange rates", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Loaded exchange rates", "file", cfg.ExchangeRatesFile, "currencies", source.Currencies())
+	}
+
+	provider := money.NewCachedProvider(source, connector.RedisConnector)
+	provider.TTL = cfg.ExchangeRatesCacheTTL
+	return money.NewConverter(provider, rounding)
 }
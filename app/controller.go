@@ -1,16 +1,19 @@
 package app
 
 import (
+	"ProductService/metrics"
 	"ProductService/models"
 	"ProductService/services"
-	"ProductService/utils"
 	enum "ProductService/utils/enums"
+	"ProductService/utils/logger"
 	"encoding/json"
 	"errors"
 	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"time"
 )
 
 type Controller interface {
@@ -22,27 +25,60 @@ type ProductController struct {
 	HttpClient *http.Client
 }
 
-func ProductHandler(p services.ProductMsgProc) *ProductController {
-	httpCLient, e := utils.GetHttpClient()
-	if e != nil {
-		log.Println("Error in creating http client", e)
-		return nil
-	}
+func ProductHandler(p services.ProductMsgProc, httpClient *http.Client) *ProductController {
 	return &ProductController{
 		Proc:       p,
-		HttpClient: httpCLient,
+		HttpClient: httpClient,
 	}
 }
 
 func (c *ProductController) HandleProduct(w http.ResponseWriter, r *http.Request) {
-	log.Printf("Entered HandleProduct")
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	c.handleProduct(rec, r)
+
+	route := r.URL.Path
+	if current := mux.CurrentRoute(r); current != nil {
+		if template, err := current.GetPathTemplate(); err == nil {
+			route = template
+		}
+	}
+	metrics.ObserveRequest(route, r.Method, rec.status, time.Since(start))
+}
+
+// statusRecorder keeps the status code written by the handler for the metrics
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	if !s.wroteHeader {
+		s.status = statusCode
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (s *statusRecorder) Write(data []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(data)
+}
+
+func (c *ProductController) handleProduct(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered HandleProduct")
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-	log.Printf("END POINT: %v", r.RequestURI)
 
 	// Getting details from request body
 	jsonData, err := io.ReadAll(r.Body)
 	if err != nil {
-		log.Println("Error in reading body", err)
+		slog.WarnContext(ctx, "Error in reading body", "error", err)
+		if wantsProblem(r) {
+			writeProblem(w, r, http.StatusBadRequest, err.Error(), err)
+			return
+		}
 		msg := models.Result{
 			ResponseCode:        enum.FailureCode400,
 			ResponseStatus:      enum.FailureMessage400,
@@ -59,7 +95,11 @@ func (c *ProductController) HandleProduct(w http.ResponseWriter, r *http.Request
 		validate := validator.New()
 		res := validate.Var(string(jsonData), "json")
 		if res != nil {
-			log.Println("Error in parsing body", res)
+			slog.WarnContext(ctx, "Error in parsing body", "error", res)
+			if wantsProblem(r) {
+				writeProblem(w, r, http.StatusBadRequest, "The request body is not valid JSON", res)
+				return
+			}
 			msg := models.Result{
 				ResponseCode:        enum.FailureCode400,
 				ResponseStatus:      enum.FailureMessage400,
@@ -72,8 +112,12 @@ func (c *ProductController) HandleProduct(w http.ResponseWriter, r *http.Request
 		}
 	}
 
-	if r.Method == http.MethodPut && len(jsonData) == 0 {
+	if (r.Method == http.MethodPut || r.Method == http.MethodPatch) && len(jsonData) == 0 {
 		err := errors.New("unable to fetch details from req body")
+		if wantsProblem(r) {
+			writeProblem(w, r, http.StatusBadRequest, err.Error(), err)
+			return
+		}
 		w.WriteHeader(http.StatusBadRequest)
 		msg := models.Result{
 			ResponseCode:        enum.FailureCode400,
@@ -86,10 +130,14 @@ func (c *ProductController) HandleProduct(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	log.Println("Request received: ", string(jsonData))
+	slog.InfoContext(ctx, "Request received", "method", r.Method, "endpoint", r.URL.Path, logger.Body(ctx, "body", jsonData))
 	format, err := c.Proc.Decode(jsonData)
 	if err != nil {
-		log.Println("Json data decode failed", err)
+		slog.WarnContext(ctx, "Json data decode failed", "error", err)
+		if wantsProblem(r) {
+			writeProblem(w, r, http.StatusBadRequest, err.Error(), err)
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
 		msg := models.Result{
 			ResponseCode:        enum.FailureCode400,
@@ -104,7 +152,11 @@ func (c *ProductController) HandleProduct(w http.ResponseWriter, r *http.Request
 
 	_, err = json.Marshal(format)
 	if err != nil {
-		log.Println("Json marshal of request body failed", err)
+		slog.ErrorContext(ctx, "Json marshal of request body failed", "error", err)
+		if wantsProblem(r) {
+			writeProblem(w, r, http.StatusInternalServerError, "", nil)
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
 		msg := models.Result{
 			ResponseCode:        enum.FailureCode400,
@@ -119,7 +171,11 @@ func (c *ProductController) HandleProduct(w http.ResponseWriter, r *http.Request
 
 	e := c.Proc.Validate(format)
 	if e != nil {
-		log.Println("Json validation failed error in json structure, fields missing")
+		slog.WarnContext(ctx, "Json validation failed error in json structure, fields missing", "error", e)
+		if wantsProblem(r) {
+			writeProblem(w, r, http.StatusBadRequest, e.Error(), e)
+			return
+		}
 		w.WriteHeader(http.StatusBadRequest)
 		msg := models.Result{
 			ResponseCode:        enum.FailureCode400,
@@ -134,11 +190,15 @@ func (c *ProductController) HandleProduct(w http.ResponseWriter, r *http.Request
 
 	msg, err := c.Proc.ProcessMsg(format, r)
 	if err != nil {
-		log.Println("Error in ProcessMsg", err)
+		slog.ErrorContext(ctx, "Error in ProcessMsg", "error", err)
+		if wantsProblem(r) {
+			writeProblem(w, r, http.StatusInternalServerError, "", nil)
+			return
+		}
 		//w.WriteHeader(http.StatusInternalServerError)
 		data, statusCode, er := c.Proc.Encode(msg)
 		if er != nil {
-			log.Println("Error in Encode", er)
+			slog.ErrorContext(ctx, "Error in Encode", "error", er)
 			msg = models.Result{
 				ResponseCode:        enum.FailureCode400,
 				ResponseStatus:      enum.FailureMessage400,
@@ -156,7 +216,7 @@ func (c *ProductController) HandleProduct(w http.ResponseWriter, r *http.Request
 	//w.WriteHeader(http.StatusOK)
 	data, statusCode, err := c.Proc.Encode(msg)
 	if err != nil {
-		log.Println("Error in Encode", err)
+		slog.ErrorContext(ctx, "Error in Encode", "error", err)
 		msg = models.Result{
 			ResponseCode:        enum.FailureCode400,
 			ResponseStatus:      enum.FailureMessage400,
@@ -165,8 +225,30 @@ func (c *ProductController) HandleProduct(w http.ResponseWriter, r *http.Request
 		}
 		w.Write(data)
 	}
-	log.Printf("Response is: %v", string(data))
-	log.Printf("End Handle HandleProduct")
+	slog.InfoContext(ctx, "Response sent", "status", statusCode, logger.Body(ctx, "body", data))
+	setResponseHeader(w, msg)
+	if statusCode >= http.StatusBadRequest && wantsProblem(r) {
+		writeProblem(w, r, statusCode, responseDescription(msg), nil)
+		return
+	}
+	slog.DebugContext(ctx, "End Handle HandleProduct")
 	w.WriteHeader(statusCode)
+	if statusCode == http.StatusNotModified {
+		// a 304 must not carry a body
+		return
+	}
 	w.Write(data)
 }
+
+// setResponseHeader copies the extra headers a service attached to its response, such as the ETag
+func setResponseHeader(w http.ResponseWriter, msg interface{}) {
+	withHeader, ok := msg.(interface{ ResponseHeader() http.Header })
+	if !ok {
+		return
+	}
+	for key, values := range withHeader.ResponseHeader() {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+}
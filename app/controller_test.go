@@ -0,0 +1,142 @@
+package app_test
+
+import (
+	"ProductService/app"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleProduct_ProblemJSON_ValidationErrors(t *testing.T) {
+	controller := app.ProductHandler(services.NewCreateProduct(nil, nil), http.DefaultClient)
+
+	req := httptest.NewRequest("POST", "/products", strings.NewReader(`{"name":"","price":-5,"currency":"XYZ"}`))
+	req.Header.Set("Accept", "application/problem+json")
+	rec := httptest.NewRecorder()
+	controller.HandleProduct(rec, req)
+
+	var problem models.Problem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "/problems/validation-error", problem.Type)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "/products", problem.Instance)
+	assert.ElementsMatch(t, []models.FieldError{
+		{Field: "sku", Rule: "required", Message: "sku is required"},
+		{Field: "name", Rule: "required", Message: "name is required"},
+		{Field: "price", Rule: "gt", Message: "price must be greater than 0"},
+		{Field: "currency", Rule: "iso4217", Message: "currency must be an ISO 4217 currency code"},
+	}, problem.Errors)
+}
+
+func TestHandleProduct_ProblemJSON_PriceDecimals(t *testing.T) {
+	controller := app.ProductHandler(services.NewCreateProduct(nil, nil), http.DefaultClient)
+
+	// the price column keeps 4 decimals, more would be silently rounded
+	req := httptest.NewRequest("POST", "/products", strings.NewReader(`{"sku":"MOU-001","name":"Mouse","price":19.123456}`))
+	req.Header.Set("Accept", "application/problem+json")
+	rec := httptest.NewRecorder()
+	controller.HandleProduct(rec, req)
+
+	var problem models.Problem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, []models.FieldError{
+		{Field: "price", Rule: "price_decimals", Message: "price must have at most 4 decimal places"},
+	}, problem.Errors)
+}
+
+func TestHandleProduct_ProblemJSON_ServiceError(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	mockCache.On("GetProductByID", mock.Anything, "7").Return(nil, nil)
+	mockDB.On("GetProductByID", mock.Anything, 7).Return(nil, nil)
+	controller := app.ProductHandler(services.NewGetProdById(mockCache, mockDB), http.DefaultClient)
+
+	req := httptest.NewRequest("GET", "/products/7", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	req.Header.Set("Accept", "application/json, application/problem+json;q=0.9")
+	rec := httptest.NewRecorder()
+	controller.HandleProduct(rec, req)
+
+	var problem models.Problem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, "Not Found", problem.Title)
+	assert.Equal(t, "Product Not Found", problem.Detail)
+	assert.Empty(t, problem.Errors)
+}
+
+func TestHandleProduct_LegacyResultByDefault(t *testing.T) {
+	controller := app.ProductHandler(services.NewCreateProduct(nil, nil), http.DefaultClient)
+
+	for _, accept := range []string{"", "application/json", "application/problem+json;q=0"} {
+		req := httptest.NewRequest("POST", "/products", strings.NewReader(`{"name":"","price":10}`))
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		controller.HandleProduct(rec, req)
+
+		var result models.Result
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "400", result.ResponseCode)
+		assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+	}
+}
+
+func TestHandleProduct_NotModified(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockCache.On("GetProductByID", mock.Anything, "7").Return(&models.Product{ID: 7, Name: "Phone", Price: 100, Version: 2}, nil)
+	controller := app.ProductHandler(services.NewGetProdById(mockCache, new(mocks.MockDBOperations)), http.DefaultClient)
+
+	req := httptest.NewRequest("GET", "/products/7", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	req.Header.Set("If-None-Match", `"2"`)
+	rec := httptest.NewRecorder()
+	controller.HandleProduct(rec, req)
+
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestHandleProduct_Created(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	created := models.Product{
+		ID:        12,
+		SKU:       "PH-1",
+		Name:      "Phone",
+		Price:     100,
+		Currency:  "USD",
+		Status:    models.StatusDraft,
+		Version:   1,
+		CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	mockDB.On("CreateProduct", mock.Anything, &models.CreateProductRequest{SKU: "PH-1", Name: "Phone", Price: 100, Currency: "USD", Status: models.StatusDraft}).
+		Return(&created, nil)
+	controller := app.ProductHandler(services.NewCreateProduct(nil, mockDB), http.DefaultClient)
+
+	req := httptest.NewRequest("POST", "/products", strings.NewReader(`{"sku":"PH-1","name":"Phone","price":100}`))
+	rec := httptest.NewRecorder()
+	controller.HandleProduct(rec, req)
+
+	var result struct {
+		ResponseBody models.Product `json:"response_body"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/products/12", rec.Header().Get("Location"))
+	assert.Equal(t, created, result.ResponseBody)
+}
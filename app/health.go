@@ -0,0 +1,87 @@
+package app
+
+import (
+	"ProductService/models"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// HealthHandler serves the liveness and readiness probes.
+// Postgres is required for readiness, Redis is not since GetProdById falls back to the DB,
+// so a Redis outage only reports the service as degraded.
+type HealthHandler struct {
+	PingPostgres func(ctx context.Context) error
+	PingRedis    func(ctx context.Context) error
+	Timeout      time.Duration
+}
+
+func NewHealthHandler(pingPostgres func(ctx context.Context) error, pingRedis func(ctx context.Context) error, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{
+		PingPostgres: pingPostgres,
+		PingRedis:    pingRedis,
+		Timeout:      timeout,
+	}
+}
+
+// Liveness only reports that the process is up and serving requests
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, models.HealthResponse{Status: "ok"})
+}
+
+// Readiness pings every dependency concurrently and reports their status and latency
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	postgres := make(chan models.DependencyStatus, 1)
+	redis := make(chan models.DependencyStatus, 1)
+	go func() { postgres <- h.check(r.Context(), h.PingPostgres) }()
+	go func() { redis <- h.check(r.Context(), h.PingRedis) }()
+
+	resp := models.HealthResponse{
+		Status: "ok",
+		Checks: map[string]models.DependencyStatus{
+			"postgres": <-postgres,
+			"redis":    <-redis,
+		},
+	}
+
+	statusCode := http.StatusOK
+	if resp.Checks["postgres"].Status != "up" {
+		resp.Status = "unavailable"
+		statusCode = http.StatusServiceUnavailable
+	} else if resp.Checks["redis"].Status != "up" {
+		resp.Status = "degraded"
+	}
+	writeHealth(w, statusCode, resp)
+}
+
+func (h *HealthHandler) check(ctx context.Context, ping func(ctx context.Context) error) models.DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ping(ctx)
+	status := models.DependencyStatus{
+		Status:    "up",
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Status = "down"
+		status.Error = err.Error()
+	}
+	return status
+}
+
+func writeHealth(w http.ResponseWriter, statusCode int, resp models.HealthResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	w.Write(data)
+}
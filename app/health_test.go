@@ -0,0 +1,84 @@
+package app_test
+
+import (
+	"ProductService/app"
+	"ProductService/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func up(ctx context.Context) error {
+	return nil
+}
+
+func down(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func hang(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func readiness(t *testing.T, handler *app.HealthHandler) (int, models.HealthResponse) {
+	rec := httptest.NewRecorder()
+	handler.Readiness(rec, httptest.NewRequest("GET", "/readyz", nil))
+
+	var resp models.HealthResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return rec.Code, resp
+}
+
+func TestHealthHandler_Liveness(t *testing.T) {
+	handler := app.NewHealthHandler(down, down, time.Second)
+
+	rec := httptest.NewRecorder()
+	handler.Liveness(rec, httptest.NewRequest("GET", "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		postgres   func(ctx context.Context) error
+		redis      func(ctx context.Context) error
+		statusCode int
+		status     string
+	}{
+		{"all up", up, up, http.StatusOK, "ok"},
+		{"redis down", up, down, http.StatusOK, "degraded"},
+		{"postgres down", down, up, http.StatusServiceUnavailable, "unavailable"},
+		{"postgres times out", hang, up, http.StatusServiceUnavailable, "unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := app.NewHealthHandler(tt.postgres, tt.redis, 50*time.Millisecond)
+
+			statusCode, resp := readiness(t, handler)
+
+			assert.Equal(t, tt.statusCode, statusCode)
+			assert.Equal(t, tt.status, resp.Status)
+			assert.Contains(t, resp.Checks, "postgres")
+			assert.Contains(t, resp.Checks, "redis")
+		})
+	}
+}
+
+func TestHealthHandler_Readiness_ReportsError(t *testing.T) {
+	handler := app.NewHealthHandler(up, down, time.Second)
+
+	_, resp := readiness(t, handler)
+
+	assert.Equal(t, "up", resp.Checks["postgres"].Status)
+	assert.Equal(t, "down", resp.Checks["redis"].Status)
+	assert.Equal(t, "connection refused", resp.Checks["redis"].Error)
+}
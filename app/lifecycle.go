@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Lifecycle runs the HTTP server until SIGINT/SIGTERM, drains it, stops the background
+// jobs and then releases the registered resources in the reverse order they were opened
+type Lifecycle struct {
+	ShutdownTimeout time.Duration
+	closers         []closer
+
+	jobsCtx  context.Context
+	stopJobs context.CancelFunc
+	jobsDone sync.WaitGroup
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+func NewLifecycle(shutdownTimeout time.Duration) *Lifecycle {
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	return &Lifecycle{ShutdownTimeout: shutdownTimeout, jobsCtx: jobsCtx, stopJobs: stopJobs}
+}
+
+// Go starts a background job, its context is canceled once the server has stopped
+// and the resources are only closed after the job has returned
+func (l *Lifecycle) Go(name string, run func(ctx context.Context)) {
+	l.jobsDone.Add(1)
+	go func() {
+		defer l.jobsDone.Done()
+		run(l.jobsCtx)
+		slog.Info("Background job finished", "job", name)
+	}()
+}
+
+// OnShutdown registers a resource to close once the server has stopped
+func (l *Lifecycle) OnShutdown(name string, close func() error) {
+	l.closers = append(l.closers, closer{name: name, close: close})
+}
+
+// Serve blocks until the server fails or a stop signal arrives, then shuts everything down
+func (l *Lifecycle) Serve(server *http.Server) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Error listening", "addr", server.Addr, "error", err)
+		}
+	case <-ctx.Done():
+		slog.Info("Shutdown signal received, draining in-flight requests")
+		l.shutdownServer(server)
+	}
+
+	l.stopJobs()
+	l.jobsDone.Wait()
+	l.closeAll()
+	slog.Info("Product Service stopped")
+}
+
+func (l *Lifecycle) shutdownServer(server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), l.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Warn("Server did not drain in time, forcing close", "timeout", l.ShutdownTimeout, "error", err)
+		server.Close()
+		return
+	}
+	slog.Info("HTTP server drained")
+}
+
+func (l *Lifecycle) closeAll() {
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		c := l.closers[i]
+		if err := c.close(); err != nil {
+			slog.Error("Error closing resource", "resource", c.name, "error", err)
+			continue
+		}
+		slog.Info("Closed resource", "resource", c.name)
+	}
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"ProductService/models"
+	"ProductService/utils/logger"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const problemContentType = "application/problem+json"
+
+// problem types, relative URIs as allowed by RFC 7807
+const (
+	problemTypeValidation = "/problems/validation-error"
+	problemTypeGeneric    = "about:blank"
+)
+
+// wantsProblem reports whether the client accepts application/problem+json, clients that
+// don't ask for it keep getting models.Result
+func wantsProblem(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil || mediaType != problemContentType {
+			continue
+		}
+		if q, ok := params["q"]; ok && strings.Trim(q, "0.") == "" {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// writeProblem writes an RFC 7807 document, validator errors in err are listed per field
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string, err error) {
+	problem := models.Problem{
+		Type:      problemTypeGeneric,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.RequestURI(),
+		RequestID: logger.RequestID(r.Context()),
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		problem.Type = problemTypeValidation
+		problem.Detail = "The request body has invalid fields"
+		problem.Errors = fieldErrors(validationErrors)
+	}
+
+	data, e := json.Marshal(problem)
+	if e != nil {
+		slog.ErrorContext(r.Context(), "Error in Marshal", "error", e)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+func fieldErrors(validationErrors validator.ValidationErrors) []models.FieldError {
+	fields := make([]models.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fields = append(fields, models.FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Message: fieldMessage(fe),
+		})
+	}
+	return fields
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%v is required", fe.Field())
+	case "gt":
+		return fmt.Sprintf("%v must be greater than %v", fe.Field(), fe.Param())
+	case "gte", "min":
+		return fmt.Sprintf("%v must be at least %v", fe.Field(), fe.Param())
+	case "lt":
+		return fmt.Sprintf("%v must be less than %v", fe.Field(), fe.Param())
+	case "lte", "max":
+		return fmt.Sprintf("%v must be at most %v", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%v must be one of %v", fe.Field(), fe.Param())
+	case "len":
+		return fmt.Sprintf("%v must have length %v", fe.Field(), fe.Param())
+	case "iso4217":
+		return fmt.Sprintf("%v must be an ISO 4217 currency code", fe.Field())
+	case "printascii":
+		return fmt.Sprintf("%v must only contain printable ASCII characters", fe.Field())
+	case "unique":
+		return fmt.Sprintf("%v must not contain duplicates", fe.Field())
+	case "attribute_key":
+		return fmt.Sprintf("%v must only contain letters, digits, '_' and '-'", fe.Field())
+	case "attribute_value":
+		return fmt.Sprintf("%v must be a string of at most 500 characters, a number or a boolean", fe.Field())
+	case "price_decimals":
+		return fmt.Sprintf("%v must have at most %v decimal places", fe.Field(), models.MaxPriceDecimals)
+	case "warehouse":
+		return fmt.Sprintf("%v must be at most 64 letters, digits, '_' and '-'", fe.Field())
+	}
+	return fmt.Sprintf("%v failed the %v rule", fe.Field(), fe.Tag())
+}
+
+// responseDescription reads the ResponseDescription of any of the service response types
+func responseDescription(msg interface{}) string {
+	if d, ok := msg.(interface{ Description() string }); ok {
+		return d.Description()
+	}
+	return ""
+}
@@ -1,51 +1,191 @@
 package app
 
 import (
+	"ProductService/config"
 	"ProductService/db/connector"
+	"ProductService/metrics"
 	"ProductService/services"
 	"ProductService/utils"
+	"context"
 	"github.com/gorilla/mux"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
 )
 
-func runserver() {
-	log.Println("Product Service - Backend Service")
+func runserver(cfg *config.Config, lifecycle *Lifecycle) {
+	slog.Info("Product Service - Backend Service")
 	router := mux.NewRouter()
+	router.Use(utils.RequestIDFilter)
+	router.Use(utils.ActorFilter)
 	router.Use(utils.CorsFilter)
+	httpClient := utils.GetHttpClient(cfg.HTTPClientTimeout)
+
+	healthHandler := NewHealthHandler(config.PostgresConn.PingContext, func(ctx context.Context) error {
+		return config.RedisClient.Ping(ctx).Err()
+	}, cfg.HealthCheckTimeout)
+	router.HandleFunc("/healthz", healthHandler.Liveness).Methods("GET")
+	router.HandleFunc("/readyz", healthHandler.Readiness).Methods("GET")
+
+	metrics.RegisterDBStats(config.PostgresConn, cfg.PGDBName)
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+
+	converter := exchangeRates(cfg)
 
 	getProdByIdProc := services.NewGetProdById(connector.RedisConnector, connector.PGDBConnector)
-	getProductHandler := ProductHandler(getProdByIdProc)
+	getProdByIdProc.Converter = converter
+	getProductHandler := ProductHandler(getProdByIdProc, httpClient)
 	router.HandleFunc("/products/{id}", getProductHandler.HandleProduct).Methods("GET", "OPTIONS")
 
 	getAllProdProc := services.NewGetAllProd(connector.RedisConnector, connector.PGDBConnector)
-	getAllProductHandler := ProductHandler(getAllProdProc)
+	getAllProdProc.Converter = converter
+	getAllProdProc.AdminToken = cfg.AdminToken
+	getAllProductHandler := ProductHandler(getAllProdProc, httpClient)
 	router.HandleFunc("/products", getAllProductHandler.HandleProduct).Methods("GET", "OPTIONS")
 
 	createProduct := services.NewCreateProduct(connector.RedisConnector, connector.PGDBConnector)
-	createProductHandler := ProductHandler(createProduct)
+	createProduct.IdempotencyTTL = cfg.IdempotencyTTL
+	createProductHandler := ProductHandler(createProduct, httpClient)
 	router.HandleFunc("/products", createProductHandler.HandleProduct).Methods("POST", "OPTIONS")
 
+	batchProducts := services.NewBatchProducts(connector.RedisConnector, connector.PGDBConnector)
+	batchProducts.MaxBatchSize = cfg.BatchMaxSize
+	batchProductsHandler := ProductHandler(batchProducts, httpClient)
+	router.HandleFunc("/products:batch", batchProductsHandler.HandleProduct).Methods("POST", "OPTIONS")
+
 	updateProduct := services.NewUpdateProduct(connector.RedisConnector, connector.PGDBConnector)
-	updateProductHandler := ProductHandler(updateProduct)
+	updateProduct.RequireIfMatch = cfg.RequireIfMatch
+	updateProductHandler := ProductHandler(updateProduct, httpClient)
 	router.HandleFunc("/products/{id}", updateProductHandler.HandleProduct).Methods("PUT", "OPTIONS")
 
+	patchProduct := services.NewPatchProduct(connector.RedisConnector, connector.PGDBConnector)
+	patchProduct.RequireIfMatch = cfg.RequireIfMatch
+	patchProductHandler := ProductHandler(patchProduct, httpClient)
+	router.HandleFunc("/products/{id}", patchProductHandler.HandleProduct).Methods("PATCH", "OPTIONS")
+
 	deleteProduct := services.NewDeleteProd(connector.RedisConnector, connector.PGDBConnector)
-	deleteProductHandler := ProductHandler(deleteProduct)
+	deleteProduct.RequireIfMatch = cfg.RequireIfMatch
+	deleteProductHandler := ProductHandler(deleteProduct, httpClient)
 	router.HandleFunc("/products/{id}", deleteProductHandler.HandleProduct).Methods("DELETE", "OPTIONS")
 
-	PORT := os.Getenv("PORT")
+	restoreProduct := services.NewRestoreProduct(connector.RedisConnector, connector.PGDBConnector)
+	restoreProduct.RequireIfMatch = cfg.RequireIfMatch
+	restoreProductHandler := ProductHandler(restoreProduct, httpClient)
+	router.HandleFunc("/products/{id}:restore", restoreProductHandler.HandleProduct).Methods("POST", "OPTIONS")
 
-	server := &http.Server{
-		Addr:    ":" + PORT,
-		Handler: router,
-	}
+	getProductCategories := services.NewGetProductCategories(connector.RedisConnector, connector.PGDBConnector)
+	getProductCategoriesHandler := ProductHandler(getProductCategories, httpClient)
+	router.HandleFunc("/products/{id}/categories", getProductCategoriesHandler.HandleProduct).Methods("GET", "OPTIONS")
+
+	setProductCategories := services.NewSetProductCategories(connector.RedisConnector, connector.PGDBConnector)
+	setProductCategoriesHandler := ProductHandler(setProductCategories, httpClient)
+	router.HandleFunc("/products/{id}/categories", setProductCategoriesHandler.HandleProduct).Methods("PUT", "OPTIONS")
+
+	getPriceHistory := services.NewGetPriceHistory(connector.RedisConnector, connector.PGDBConnector)
+	getPriceHistoryHandler := ProductHandler(getPriceHistory, httpClient)
+	router.HandleFunc("/products/{id}/prices", getPriceHistoryHandler.HandleProduct).Methods("GET", "OPTIONS")
+
+	getScheduledPrices := services.NewGetScheduledPrices(connector.RedisConnector, connector.PGDBConnector)
+	getScheduledPricesHandler := ProductHandler(getScheduledPrices, httpClient)
+	router.HandleFunc("/products/{id}/scheduled-prices", getScheduledPricesHandler.HandleProduct).Methods("GET", "OPTIONS")
+	router.HandleFunc("/products/{id}/scheduled-prices/{scheduleId}", getScheduledPricesHandler.HandleProduct).Methods("GET", "OPTIONS")
+
+	createScheduledPrice := services.NewCreateScheduledPrice(connector.RedisConnector, connector.PGDBConnector)
+	createScheduledPriceHandler := ProductHandler(createScheduledPrice, httpClient)
+	router.HandleFunc("/products/{id}/scheduled-prices", createScheduledPriceHandler.HandleProduct).Methods("POST", "OPTIONS")
+
+	cancelScheduledPrice := services.NewCancelScheduledPrice(connector.RedisConnector, connector.PGDBConnector)
+	cancelScheduledPriceHandler := ProductHandler(cancelScheduledPrice, httpClient)
+	router.HandleFunc("/products/{id}/scheduled-prices/{scheduleId}", cancelScheduledPriceHandler.HandleProduct).Methods("DELETE", "OPTIONS")
+
+	getProductVariants := services.NewGetProductVariants(connector.RedisConnector, connector.PGDBConnector)
+	getProductVariantsHandler := ProductHandler(getProductVariants, httpClient)
+	router.HandleFunc("/products/{id}/variants", getProductVariantsHandler.HandleProduct).Methods("GET", "OPTIONS")
+	router.HandleFunc("/products/{id}/variants/{variantId}", getProductVariantsHandler.HandleProduct).Methods("GET", "OPTIONS")
+
+	createProductVariant := services.NewCreateProductVariant(connector.RedisConnector, connector.PGDBConnector)
+	createProductVariantHandler := ProductHandler(createProductVariant, httpClient)
+	router.HandleFunc("/products/{id}/variants", createProductVariantHandler.HandleProduct).Methods("POST", "OPTIONS")
+
+	updateProductVariant := services.NewUpdateProductVariant(connector.RedisConnector, connector.PGDBConnector)
+	updateProductVariantHandler := ProductHandler(updateProductVariant, httpClient)
+	router.HandleFunc("/products/{id}/variants/{variantId}", updateProductVariantHandler.HandleProduct).Methods("PUT", "OPTIONS")
+
+	deleteProductVariant := services.NewDeleteProductVariant(connector.RedisConnector, connector.PGDBConnector)
+	deleteProductVariantHandler := ProductHandler(deleteProductVariant, httpClient)
+	router.HandleFunc("/products/{id}/variants/{variantId}", deleteProductVariantHandler.HandleProduct).Methods("DELETE", "OPTIONS")
 
-	log.Printf("Started HTTPS Server on port %v", PORT)
-	log.Printf("-------------------------")
-	if err := server.ListenAndServe(); err != nil {
-		log.Printf("Error listening on port: %v, error: %v", PORT, err)
+	getCategories := services.NewGetCategories(connector.RedisConnector, connector.PGDBConnector)
+	getCategoriesHandler := ProductHandler(getCategories, httpClient)
+	router.HandleFunc("/categories", getCategoriesHandler.HandleProduct).Methods("GET", "OPTIONS")
+	router.HandleFunc("/categories/{id}", getCategoriesHandler.HandleProduct).Methods("GET", "OPTIONS")
+
+	createCategory := services.NewCreateCategory(connector.RedisConnector, connector.PGDBConnector)
+	createCategoryHandler := ProductHandler(createCategory, httpClient)
+	router.HandleFunc("/categories", createCategoryHandler.HandleProduct).Methods("POST", "OPTIONS")
+
+	updateCategory := services.NewUpdateCategory(connector.RedisConnector, connector.PGDBConnector)
+	updateCategoryHandler := ProductHandler(updateCategory, httpClient)
+	router.HandleFunc("/categories/{id}", updateCategoryHandler.HandleProduct).Methods("PUT", "OPTIONS")
+
+	deleteCategory := services.NewDeleteCategory(connector.RedisConnector, connector.PGDBConnector)
+	deleteCategoryHandler := ProductHandler(deleteCategory, httpClient)
+	router.HandleFunc("/categories/{id}", deleteCategoryHandler.HandleProduct).Methods("DELETE", "OPTIONS")
+
+	getCategoryProducts := services.NewGetCategoryProducts(connector.RedisConnector, connector.PGDBConnector)
+	getCategoryProducts.Converter = converter
+	getCategoryProducts.AdminToken = cfg.AdminToken
+	getCategoryProductsHandler := ProductHandler(getCategoryProducts, httpClient)
+	router.HandleFunc("/categories/{id}/products", getCategoryProductsHandler.HandleProduct).Methods("GET", "OPTIONS")
+
+	getInventory := services.NewGetInventory(connector.RedisConnector, connector.PGDBConnector)
+	getInventoryHandler := ProductHandler(getInventory, httpClient)
+	router.HandleFunc("/products/{id}/inventory", getInventoryHandler.HandleProduct).Methods("GET", "OPTIONS")
+
+	adjustStock := services.NewAdjustStock(connector.RedisConnector, connector.PGDBConnector)
+	adjustStockHandler := ProductHandler(adjustStock, httpClient)
+	router.HandleFunc("/products/{id}/inventory/adjustments", adjustStockHandler.HandleProduct).Methods("POST", "OPTIONS")
+
+	setStockLevel := services.NewSetStockLevel(connector.RedisConnector, connector.PGDBConnector)
+	setStockLevelHandler := ProductHandler(setStockLevel, httpClient)
+	router.HandleFunc("/products/{id}/inventory/{warehouse}", setStockLevelHandler.HandleProduct).Methods("PUT", "OPTIONS")
+
+	createReservation := services.NewCreateReservation(connector.RedisConnector, connector.PGDBConnector)
+	createReservation.TTL = cfg.ReservationTTL
+	createReservationHandler := ProductHandler(createReservation, httpClient)
+	router.HandleFunc("/products/{id}/reservations", createReservationHandler.HandleProduct).Methods("POST", "OPTIONS")
+
+	commitReservation := services.NewCommitReservation(connector.RedisConnector, connector.PGDBConnector)
+	commitReservationHandler := ProductHandler(commitReservation, httpClient)
+	router.HandleFunc("/products/{id}/reservations/{reservationId}:commit", commitReservationHandler.HandleProduct).Methods("POST", "OPTIONS")
+
+	releaseReservation := services.NewReleaseReservation(connector.RedisConnector, connector.PGDBConnector)
+	releaseReservationHandler := ProductHandler(releaseReservation, httpClient)
+	router.HandleFunc("/products/{id}/reservations/{reservationId}", releaseReservationHandler.HandleProduct).Methods("DELETE", "OPTIONS")
+
+	purgeDeleted := services.NewPurgeDeleted(connector.PGDBConnector)
+	purgeDeleted.Retention = cfg.PurgeRetention
+	purgeDeleted.Interval = cfg.PurgeInterval
+	lifecycle.Go("purge deleted products", purgeDeleted.Run)
+
+	expireReservations := services.NewExpireReservations(connector.PGDBConnector)
+	expireReservations.Interval = cfg.ReservationSweepInterval
+	lifecycle.Go("expire reservations", expireReservations.Run)
+
+	priceScheduler := services.NewPriceScheduler(connector.RedisConnector, connector.PGDBConnector)
+	priceScheduler.Interval = cfg.PriceScheduleInterval
+	lifecycle.Go("price scheduler", priceScheduler.Run)
+
+	PORT := cfg.Port
+
+	server := &http.Server{
+		Addr:              ":" + PORT,
+		Handler:           router,
+		ReadHeaderTimeout: cfg.ServerReadTimeout,
+		ReadTimeout:       cfg.ServerReadTimeout,
+		WriteTimeout:      cfg.ServerWriteTimeout,
+		IdleTimeout:       cfg.ServerIdleTimeout,
 	}
 
+	slog.Info("Started HTTP Server", "port", PORT)
+	lifecycle.Serve(server)
 }
@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"github.com/go-redis/redis/v8"
 	_ "github.com/lib/pq"
-	"log"
+	"log/slog"
 	"os"
 	"time"
 )
@@ -21,13 +21,13 @@ type connection struct {
 
 var PostgresConn *sql.DB
 
-func InitDB() {
+func InitDB(cfg *Config) {
 	connInfo := connection{
-		Host:     os.Getenv("PG_HOST"),
-		Port:     os.Getenv("PG_PORT"),
-		User:     os.Getenv("PG_USER"),
-		Password: os.Getenv("PG_PASS"),
-		DBName:   os.Getenv("PG_DBNAME"),
+		Host:     cfg.PGHost,
+		Port:     cfg.PGPort,
+		User:     cfg.PGUser,
+		Password: cfg.PGPass,
+		DBName:   cfg.PGDBName,
 	}
 
 	connString := fmt.Sprintf(
@@ -39,7 +39,7 @@ func InitDB() {
 		connInfo.DBName,
 	)
 
-	log.Println("DB connection string: ", connString)
+	slog.Info("Connecting to PostgreSQL", "host", connInfo.Host, "port", connInfo.Port, "user", connInfo.User, "dbname", connInfo.DBName)
 
 	var db *sql.DB
 	var err error
@@ -49,26 +49,27 @@ func InitDB() {
 	for attempts := 1; attempts <= maxRetries; attempts++ {
 		db, err = sql.Open("postgres", connString)
 		if err != nil {
-			log.Printf("Attempt %d: failed to open PostgreSQL connection: %v", attempts, err)
+			slog.Warn("Failed to open PostgreSQL connection", "attempt", attempts, "error", err)
 		} else {
 			// Check the actual connection with Ping
 			err = db.Ping()
 			if err != nil {
-				log.Printf("Attempt %d: failed to ping PostgreSQL: %v", attempts, err)
+				slog.Warn("Failed to ping PostgreSQL", "attempt", attempts, "error", err)
 			} else {
-				log.Println("Connected to PostgreSQL")
+				slog.Info("Connected to PostgreSQL")
 				break
 			}
 		}
 
 		if attempts < maxRetries {
-			log.Println("Retrying in 2 seconds...")
+			slog.Info("Retrying in 2 seconds...")
 			time.Sleep(2 * time.Second)
 		}
 	}
 
 	if err != nil {
-		log.Fatalf("failed to connect to PostgreSQL after %d attempts: %v", maxRetries, err)
+		slog.Error("Failed to connect to PostgreSQL", "attempts", maxRetries, "error", err)
+		os.Exit(1)
 	}
 
 	db.SetMaxOpenConns(10)
@@ -76,63 +77,19 @@ func InitDB() {
 	db.SetConnMaxLifetime(time.Hour)
 
 	if err := db.Ping(); err != nil {
-		log.Fatalf("failed to ping PostgreSQL: %v", err)
-		return
+		slog.Error("Failed to ping PostgreSQL", "error", err)
+		os.Exit(1)
 	}
 
 	PostgresConn = db
-
-	//creating table and inserting values if not created
-	SetupSchemaAndSeedData()
-}
-
-func SetupSchemaAndSeedData() {
-	createTableQuery := `
-	CREATE TABLE IF NOT EXISTS products (
-		id SERIAL PRIMARY KEY,
-		name TEXT NOT NULL,
-		price REAL NOT NULL
-	);`
-
-	_, err := PostgresConn.Exec(createTableQuery)
-	if err != nil {
-		log.Fatalf("failed to create products table: %v", err)
-	}
-	log.Println("Products table created or already exists.")
-
-	// Check if table already has data
-	var count int
-	err = PostgresConn.QueryRow("SELECT COUNT(*) FROM products").Scan(&count)
-	if err != nil {
-		log.Fatalf("failed to count products: %v", err)
-	}
-
-	if count == 0 {
-		// Seed initial products
-		seedQuery := `
-		INSERT INTO products (name, price) VALUES
-		('Wireless Mouse', 25.99),
-		('Mechanical Keyboard', 89.50),
-		('HD Monitor', 199.99),
-		('USB-C Hub', 39.95),
-		('Noise Cancelling Headphones', 129.00);
-		`
-		_, err = PostgresConn.Exec(seedQuery)
-		if err != nil {
-			log.Fatalf("failed to seed products: %v", err)
-		}
-		log.Println("Seeded 5 sample products.")
-	} else {
-		log.Printf("Products table already contains %d entries. Skipping seeding.\n", count)
-	}
 }
 
 var RedisClient *redis.Client
 
-func InitRedis() {
-	host := os.Getenv("REDIS_HOST")     // e.g., localhost
-	port := os.Getenv("REDIS_PORT")     // e.g., 6379
-	password := os.Getenv("REDIS_PASS") // leave empty "" if no password
+func InitRedis(cfg *Config) {
+	host := cfg.RedisHost     // e.g., localhost
+	port := cfg.RedisPort     // e.g., 6379
+	password := cfg.RedisPass // leave empty "" if no password
 
 	addr := fmt.Sprintf("%s:%s", host, port)
 
@@ -149,20 +106,21 @@ func InitRedis() {
 	for attempts := 1; attempts <= maxRetries; attempts++ {
 		_, err = rdb.Ping(ctx).Result()
 		if err == nil {
-			log.Println("Connected to Redis")
+			slog.Info("Connected to Redis")
 			break
 		}
 
-		log.Printf("Attempt %d: failed to connect to Redis: %v", attempts, err)
+		slog.Warn("Failed to connect to Redis", "attempt", attempts, "error", err)
 
 		if attempts < maxRetries {
-			log.Println("Retrying Redis connection in 2 seconds...")
+			slog.Info("Retrying Redis connection in 2 seconds...")
 			time.Sleep(2 * time.Second)
 		}
 	}
 
 	if err != nil {
-		log.Fatalf("failed to connect to Redis after %d attempts: %v", maxRetries, err)
+		slog.Error("Failed to connect to Redis", "attempts", maxRetries, "error", err)
+		os.Exit(1)
 	}
 
 	RedisClient = rdb
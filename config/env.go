@@ -1,21 +1,238 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"github.com/joho/godotenv"
 	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
-type envData struct {
+// Config holds every setting the service reads from the environment
+type Config struct {
+	PGHost   string
+	PGPort   string
+	PGUser   string
+	PGPass   string
+	PGDBName string
+
+	RedisHost string
+	RedisPort string
+	RedisPass string
+
+	Port              string
+	HTTPClientTimeout time.Duration
+
+	ServerReadTimeout  time.Duration
+	ServerWriteTimeout time.Duration
+	ServerIdleTimeout  time.Duration
+	ShutdownTimeout    time.Duration
+
+	HealthCheckTimeout time.Duration
+
+	DBQueryTimeout time.Duration
+	CacheTimeout   time.Duration
+
+	LogLevel  string
+	LogFormat string
+
+	SeedData bool
+
+	// RequireIfMatch makes PUT, PATCH and DELETE on a product fail with 428 without an If-Match header
+	RequireIfMatch bool
+
+	// AdminToken is the X-Admin-Token value admin-only query flags such as include_deleted require,
+	// without it nobody can use them
+	AdminToken string
+
+	// IdempotencyTTL is how long responses to POST requests with an Idempotency-Key are kept for replay
+	IdempotencyTTL time.Duration
+
+	// BatchMaxSize is the largest number of operations accepted by POST /products:batch
+	BatchMaxSize int
+
+	// PurgeRetention is how long soft deleted products are kept before the purge job removes them for good
+	PurgeRetention time.Duration
+	// PurgeInterval is how often the purge job runs
+	PurgeInterval time.Duration
+
+	// ReservationTTL is how long a stock reservation holds the stock when the request doesn't say
+	ReservationTTL time.Duration
+	// ReservationSweepInterval is how often expired reservations give their stock back
+	ReservationSweepInterval time.Duration
+
+	// PriceScheduleInterval is how often scheduled prices are applied and reverted
+	PriceScheduleInterval time.Duration
+
+	// ExchangeRatesFile is the .json or .csv file ?currency conversions take their rates from, without it
+	// prices can't be converted
+	ExchangeRatesFile string
+	// ExchangeRatesCacheTTL is how long a rate is kept in Redis
+	ExchangeRatesCacheTTL time.Duration
+	// CurrencyRounding overrides how converted prices are rounded, e.g. "CHF:2:half_even,*:2:half_up"
+	CurrencyRounding string
 }
 
-var Env *envData
+// InitializeEnv loads the optional .env file and builds the Config, the service exits if any key is missing or invalid.
+// The .env path is taken from CONFIG_PATH, otherwise .env in the working directory is used when present.
+// Variables already set in the environment take precedence over the file.
+func InitializeEnv() *Config {
+	path := os.Getenv("CONFIG_PATH")
+	if path != "" {
+		if err := godotenv.Load(path); err != nil {
+			log.Fatalf("Error loading .env file %v: %v", path, err)
+		}
+		log.Printf(".env file loaded from %v", path)
+	} else if err := godotenv.Load(); err == nil {
+		log.Println(".env file loaded")
+	} else if !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("Error loading .env file: %v", err)
+	}
 
-func InitializeEnv() {
-	//TODO: when setting up in docker have to change env
-	err := godotenv.Load("C:\\Users\\Aritr\\GolandProjects\\ProductService\\.env")
+	cfg, err := Load(os.LookupEnv)
 	if err != nil {
-		log.Fatalf("Error loading .env file: %v", err)
-	} else {
-		log.Println(".env file loaded")
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+	return cfg
+}
+
+// Load builds the Config from lookup, applying defaults.
+// All missing or invalid keys are reported together in the returned error.
+func Load(lookup func(key string) (string, bool)) (*Config, error) {
+	r := &envReader{lookup: lookup}
+
+	cfg := &Config{
+		PGHost:   r.required("PG_HOST"),
+		PGPort:   r.port("PG_PORT", "5432"),
+		PGUser:   r.required("PG_USER"),
+		PGPass:   r.str("PG_PASS", ""),
+		PGDBName: r.required("PG_DBNAME"),
+
+		RedisHost: r.required("REDIS_HOST"),
+		RedisPort: r.port("REDIS_PORT", "6379"),
+		RedisPass: r.str("REDIS_PASS", ""),
+
+		Port:              r.port("PORT", "8000"),
+		HTTPClientTimeout: r.seconds("HTTP_CLIENT_TIMEOUT", 60),
+
+		ServerReadTimeout:  r.seconds("SERVER_READ_TIMEOUT", 15),
+		ServerWriteTimeout: r.seconds("SERVER_WRITE_TIMEOUT", 30),
+		ServerIdleTimeout:  r.seconds("SERVER_IDLE_TIMEOUT", 120),
+		ShutdownTimeout:    r.seconds("SHUTDOWN_TIMEOUT", 20),
+
+		HealthCheckTimeout: r.seconds("HEALTH_CHECK_TIMEOUT", 2),
+
+		DBQueryTimeout: r.millis("DB_QUERY_TIMEOUT_MS", 5000),
+		CacheTimeout:   r.millis("CACHE_TIMEOUT_MS", 500),
+
+		LogLevel:  r.oneOf("LOG_LEVEL", "info", "debug", "info", "warn", "error"),
+		LogFormat: r.oneOf("LOG_FORMAT", "text", "text", "json"),
+
+		SeedData: r.boolean("SEED_DATA", false),
+
+		RequireIfMatch: r.boolean("REQUIRE_IF_MATCH", false),
+		AdminToken:     r.str("ADMIN_TOKEN", ""),
+
+		IdempotencyTTL: r.seconds("IDEMPOTENCY_TTL", 86400),
+
+		BatchMaxSize: r.integer("BATCH_MAX_SIZE", 1000, 1),
+
+		PurgeRetention: r.days("PURGE_RETENTION_DAYS", 30),
+		PurgeInterval:  r.seconds("PURGE_INTERVAL", 3600),
+
+		ReservationTTL:           r.seconds("RESERVATION_TTL", 900),
+		ReservationSweepInterval: r.seconds("RESERVATION_SWEEP_INTERVAL", 60),
+
+		PriceScheduleInterval: r.seconds("PRICE_SCHEDULE_INTERVAL", 60),
+
+		ExchangeRatesFile:     r.str("EXCHANGE_RATES_FILE", ""),
+		ExchangeRatesCacheTTL: r.seconds("EXCHANGE_RATES_CACHE_TTL", 3600),
+		CurrencyRounding:      r.str("CURRENCY_ROUNDING", ""),
+	}
+
+	if len(r.errs) > 0 {
+		return nil, errors.Join(r.errs...)
+	}
+	return cfg, nil
+}
+
+// envReader reads typed values and collects every problem instead of stopping at the first one
+type envReader struct {
+	lookup func(key string) (string, bool)
+	errs   []error
+}
+
+func (r *envReader) str(key string, def string) string {
+	value, ok := r.lookup(key)
+	if !ok || value == "" {
+		return def
+	}
+	return value
+}
+
+func (r *envReader) required(key string) string {
+	value := r.str(key, "")
+	if value == "" {
+		r.errs = append(r.errs, fmt.Errorf("%v is required", key))
+	}
+	return value
+}
+
+func (r *envReader) integer(key string, def int, min int) int {
+	value := r.str(key, "")
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min {
+		r.errs = append(r.errs, fmt.Errorf("%v must be an integer of at least %d, got %q", key, min, value))
+		return def
+	}
+	return n
+}
+
+func (r *envReader) port(key string, def string) string {
+	value := r.str(key, def)
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 65535 {
+		r.errs = append(r.errs, fmt.Errorf("%v must be a port number, got %q", key, value))
+	}
+	return value
+}
+
+func (r *envReader) seconds(key string, def int) time.Duration {
+	return time.Duration(r.integer(key, def, 1)) * time.Second
+}
+
+func (r *envReader) oneOf(key string, def string, allowed ...string) string {
+	value := strings.ToLower(r.str(key, def))
+	if !slices.Contains(allowed, value) {
+		r.errs = append(r.errs, fmt.Errorf("%v must be one of %v, got %q", key, strings.Join(allowed, ", "), value))
+	}
+	return value
+}
+
+func (r *envReader) millis(key string, def int) time.Duration {
+	return time.Duration(r.integer(key, def, 1)) * time.Millisecond
+}
+
+func (r *envReader) days(key string, def int) time.Duration {
+	return time.Duration(r.integer(key, def, 1)) * 24 * time.Hour
+}
+
+func (r *envReader) boolean(key string, def bool) bool {
+	value := r.str(key, "")
+	if value == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%v must be true or false, got %q", key, value))
+		return def
 	}
+	return b
 }
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func lookupFrom(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(lookupFrom(map[string]string{
+		"PG_HOST":    "localhost",
+		"PG_USER":    "postgres",
+		"PG_DBNAME":  "postgres",
+		"REDIS_HOST": "localhost",
+	}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "5432", cfg.PGPort)
+	assert.Equal(t, "6379", cfg.RedisPort)
+	assert.Equal(t, "8000", cfg.Port)
+	assert.Equal(t, 60*time.Second, cfg.HTTPClientTimeout)
+	assert.Equal(t, 20*time.Second, cfg.ShutdownTimeout)
+	assert.False(t, cfg.SeedData)
+	assert.False(t, cfg.RequireIfMatch)
+	assert.Empty(t, cfg.AdminToken)
+	assert.Equal(t, 24*time.Hour, cfg.IdempotencyTTL)
+	assert.Equal(t, 1000, cfg.BatchMaxSize)
+	assert.Equal(t, 30*24*time.Hour, cfg.PurgeRetention)
+	assert.Equal(t, time.Hour, cfg.PurgeInterval)
+	assert.Equal(t, 15*time.Minute, cfg.ReservationTTL)
+	assert.Equal(t, time.Minute, cfg.ReservationSweepInterval)
+	assert.Equal(t, time.Minute, cfg.PriceScheduleInterval)
+	assert.Equal(t, "", cfg.ExchangeRatesFile)
+	assert.Equal(t, time.Hour, cfg.ExchangeRatesCacheTTL)
+	assert.Equal(t, "", cfg.CurrencyRounding)
+	assert.Equal(t, "info", cfg.LogLevel)
+	assert.Equal(t, "text", cfg.LogFormat)
+	assert.Equal(t, 5*time.Second, cfg.DBQueryTimeout)
+	assert.Equal(t, 500*time.Millisecond, cfg.CacheTimeout)
+}
+
+func TestLoad_ReadsValues(t *testing.T) {
+	cfg, err := Load(lookupFrom(map[string]string{
+		"PG_HOST":                    "db",
+		"PG_PORT":                    "5433",
+		"PG_USER":                    "user",
+		"PG_PASS":                    "secret",
+		"PG_DBNAME":                  "products",
+		"REDIS_HOST":                 "cache",
+		"REDIS_PORT":                 "6380",
+		"PORT":                       "9000",
+		"HTTP_CLIENT_TIMEOUT":        "5",
+		"SEED_DATA":                  "true",
+		"SERVER_READ_TIMEOUT":        "1",
+		"SERVER_WRITE_TIMEOUT":       "2",
+		"SERVER_IDLE_TIMEOUT":        "3",
+		"SHUTDOWN_TIMEOUT":           "4",
+		"HEALTH_CHECK_TIMEOUT":       "5",
+		"LOG_LEVEL":                  "DEBUG",
+		"LOG_FORMAT":                 "json",
+		"DB_QUERY_TIMEOUT_MS":        "250",
+		"CACHE_TIMEOUT_MS":           "50",
+		"REQUIRE_IF_MATCH":           "true",
+		"ADMIN_TOKEN":                "s3cret",
+		"IDEMPOTENCY_TTL":            "3600",
+		"BATCH_MAX_SIZE":             "50",
+		"PURGE_RETENTION_DAYS":       "7",
+		"PURGE_INTERVAL":             "600",
+		"RESERVATION_TTL":            "300",
+		"RESERVATION_SWEEP_INTERVAL": "30",
+		"PRICE_SCHEDULE_INTERVAL":    "15",
+		"EXCHANGE_RATES_FILE":        "/etc/rates.json",
+		"EXCHANGE_RATES_CACHE_TTL":   "600",
+		"CURRENCY_ROUNDING":          "CHF:2:half_even",
+	}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Config{
+		PGHost:                   "db",
+		PGPort:                   "5433",
+		PGUser:                   "user",
+		PGPass:                   "secret",
+		PGDBName:                 "products",
+		RedisHost:                "cache",
+		RedisPort:                "6380",
+		Port:                     "9000",
+		HTTPClientTimeout:        5 * time.Second,
+		ServerReadTimeout:        1 * time.Second,
+		ServerWriteTimeout:       2 * time.Second,
+		ServerIdleTimeout:        3 * time.Second,
+		ShutdownTimeout:          4 * time.Second,
+		HealthCheckTimeout:       5 * time.Second,
+		LogLevel:                 "debug",
+		LogFormat:                "json",
+		DBQueryTimeout:           250 * time.Millisecond,
+		CacheTimeout:             50 * time.Millisecond,
+		SeedData:                 true,
+		RequireIfMatch:           true,
+		AdminToken:               "s3cret",
+		IdempotencyTTL:           time.Hour,
+		BatchMaxSize:             50,
+		PurgeRetention:           7 * 24 * time.Hour,
+		PurgeInterval:            10 * time.Minute,
+		ReservationTTL:           5 * time.Minute,
+		ReservationSweepInterval: 30 * time.Second,
+		PriceScheduleInterval:    15 * time.Second,
+		ExchangeRatesFile:        "/etc/rates.json",
+		ExchangeRatesCacheTTL:    10 * time.Minute,
+		CurrencyRounding:         "CHF:2:half_even",
+	}, cfg)
+}
+
+func TestLoad_ReportsAllErrors(t *testing.T) {
+	_, err := Load(lookupFrom(map[string]string{
+		"PG_PORT":             "abc",
+		"HTTP_CLIENT_TIMEOUT": "0",
+		"SEED_DATA":           "maybe",
+		"LOG_FORMAT":          "xml",
+	}))
+
+	assert.Error(t, err)
+	for _, key := range []string{"PG_HOST", "PG_USER", "PG_DBNAME", "REDIS_HOST", "PG_PORT", "HTTP_CLIENT_TIMEOUT", "SEED_DATA", "LOG_FORMAT"} {
+		assert.Contains(t, err.Error(), key)
+	}
+}
@@ -2,11 +2,32 @@ package db
 
 import (
 	"ProductService/models"
+	"context"
 	"time"
 )
 
 type CacheInterface interface {
-	GetProductByID(id string) (*models.Product, error)
-	SetProductByID(id string, product *models.Product, ttl time.Duration) error
-	DeleteProductFromCache(id string) error
+	GetProductByID(ctx context.Context, id string) (*models.Product, error)
+	SetProductByID(ctx context.Context, id string, product *models.Product, ttl time.Duration) error
+	DeleteProductFromCache(ctx context.Context, id string) error
+	// DeleteProductsFromCache removes several products in one round trip
+	DeleteProductsFromCache(ctx context.Context, ids []string) error
+
+	// ReserveIdempotencyKey stores record under key unless the key is already taken, in which case
+	// the stored record is returned and reserved is false
+	ReserveIdempotencyKey(ctx context.Context, key string, record *models.IdempotencyRecord, ttl time.Duration) (existing *models.IdempotencyRecord, reserved bool, err error)
+	// SaveIdempotencyKey overwrites the record of a reserved key, e.g. with the final response
+	SaveIdempotencyKey(ctx context.Context, key string, record *models.IdempotencyRecord, ttl time.Duration) error
+	// DeleteIdempotencyKey releases a key so the request can be retried
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+
+	// GetCategoryTree returns the cached category tree, nil without an error on a miss
+	GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error)
+	SetCategoryTree(ctx context.Context, tree []*models.CategoryNode, ttl time.Duration) error
+	DeleteCategoryTree(ctx context.Context) error
+
+	// GetExchangeRate returns the cached rate between two currencies as an exact fraction such as "23/25",
+	// empty without an error on a miss
+	GetExchangeRate(ctx context.Context, from string, to string) (string, error)
+	SetExchangeRate(ctx context.Context, from string, to string, rate string, ttl time.Duration) error
 }
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"ProductService/models"
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// ProductCacheTTL is how long a product stays in the cache once it has been read or written
+const ProductCacheTTL = time.Minute
+
+// CacheSync keeps the product cache consistent with postgres.
+// Every mutation goes through it so the cached entry is refreshed or evicted
+// right after the DB write succeeds, instead of waiting for the TTL to expire.
+type CacheSync struct {
+	Cache CacheInterface
+	TTL   time.Duration
+}
+
+func NewCacheSync(cache CacheInterface) *CacheSync {
+	return &CacheSync{
+		Cache: cache,
+		TTL:   ProductCacheTTL,
+	}
+}
+
+// WriteThrough runs the DB write and, if it succeeds, stores the new product in the cache.
+// Only the DB error is returned, cache failures are handled inside Refresh.
+func (c *CacheSync) WriteThrough(ctx context.Context, product *models.Product, write func() error) error {
+	if err := write(); err != nil {
+		return err
+	}
+	// once the DB write is done the cache has to follow, even if the client has gone away
+	c.Refresh(context.WithoutCancel(ctx), product)
+	return nil
+}
+
+// WriteAndEvict runs the DB write and, if it succeeds, removes the given ids from the cache
+func (c *CacheSync) WriteAndEvict(ctx context.Context, write func() error, ids ...string) error {
+	if err := write(); err != nil {
+		return err
+	}
+	c.Evict(context.WithoutCancel(ctx), ids...)
+	return nil
+}
+
+// Refresh overwrites the cached entry with the given product.
+// If the set fails the entry is evicted instead so the next read falls back to the DB.
+func (c *CacheSync) Refresh(ctx context.Context, product *models.Product) bool {
+	id := strconv.Itoa(product.ID)
+	err := c.Cache.SetProductByID(ctx, id, product, c.TTL)
+	if err == nil {
+		return true
+	}
+	slog.WarnContext(ctx, "Failed to refresh product in cache, evicting", "id", id, "error", err)
+	return c.Evict(ctx, id)
+}
+
+// Evict removes every given id from the cache, it reports false if any of them could not be removed
+func (c *CacheSync) Evict(ctx context.Context, ids ...string) bool {
+	ok := true
+	for _, id := range ids {
+		if err := c.Cache.DeleteProductFromCache(ctx, id); err != nil {
+			slog.ErrorContext(ctx, "Failed to delete product from cache", "id", id, "error", err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// EvictAll removes the given ids from the cache in a single call, for writes touching many products
+func (c *CacheSync) EvictAll(ctx context.Context, ids []string) bool {
+	if len(ids) == 0 {
+		return true
+	}
+	if err := c.Cache.DeleteProductsFromCache(ctx, ids); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete products from cache", "count", len(ids), "error", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,248 @@
+package db
+
+import (
+	"ProductService/metrics"
+	"ProductService/models"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+// categoryColumns is the column list every category query selects, in the order scanCategory reads them
+const categoryColumns = "id, parent_id, name, created_at, updated_at"
+
+// CategoryNameConstraint is the unique index keeping the names of sibling categories distinct
+const CategoryNameConstraint = "categories_name_key"
+
+var (
+	// ErrCategoryHasChildren is returned when deleting a category that still has subcategories
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+	// ErrCategoryCycle is returned when a category would be moved below itself or one of its descendants
+	ErrCategoryCycle = errors.New("category cannot be moved below itself")
+	// ErrCategoryNotFound is returned when a parent or an assigned category doesn't exist
+	ErrCategoryNotFound = errors.New("category not found")
+)
+
+func scanCategory(row interface{ Scan(dest ...any) error }, category *models.Category) error {
+	return row.Scan(&category.ID, &category.ParentID, &category.Name, &category.CreatedAt, &category.UpdatedAt)
+}
+
+// foreignKeyViolation reports whether err comes from a foreign key constraint
+func foreignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// ListCategories returns every category ordered by name, the tree is built from this flat list
+func (d *PGConnector) ListCategories(ctx context.Context) (categories []*models.Category, err error) {
+	slog.DebugContext(ctx, "Entering ListCategories DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery("ListCategories", start, err)
+	}()
+
+	rows, err := d.Conn.QueryContext(ctx, "SELECT "+categoryColumns+" FROM categories ORDER BY lower(name), id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category models.Category
+		if err = scanCategory(rows, &category); err != nil {
+			return nil, err
+		}
+		categories = append(categories, &category)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting ListCategories DB Function")
+	return categories, nil
+}
+
+// CreateCategory inserts the category, ErrCategoryNotFound means the parent doesn't exist
+func (d *PGConnector) CreateCategory(ctx context.Context, request *models.CategoryRequest) (*models.Category, error) {
+	slog.DebugContext(ctx, "Entering CreateCategory DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	query := "INSERT INTO categories (name, parent_id) VALUES ($1, $2) RETURNING " + categoryColumns
+	var category models.Category
+	start := time.Now()
+	err := scanCategory(d.Conn.QueryRowContext(ctx, query, request.Name, request.ParentID), &category)
+	metrics.ObserveQuery("CreateCategory", start, err)
+	if foreignKeyViolation(err) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting CreateCategory DB Function")
+	return &category, nil
+}
+
+// UpdateCategory renames and moves the category. It returns sql.ErrNoRows when the category doesn't exist,
+// ErrCategoryNotFound when the new parent doesn't and ErrCategoryCycle when the parent is one of its descendants.
+func (d *PGConnector) UpdateCategory(ctx context.Context, id int, request *models.CategoryRequest) (*models.Category, error) {
+	slog.DebugContext(ctx, "Entering UpdateCategory DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+
+	// walking up from the new parent must not reach the category itself
+	query := `UPDATE categories SET name = $2, parent_id = $3, updated_at = now() WHERE id = $1 AND NOT EXISTS (
+		WITH RECURSIVE ancestors (id) AS (
+			SELECT $3::integer
+			UNION
+			SELECT c.parent_id FROM categories c JOIN ancestors a ON c.id = a.id WHERE c.parent_id IS NOT NULL
+		)
+		SELECT 1 FROM ancestors WHERE id = $1
+	) RETURNING ` + categoryColumns
+	var category models.Category
+	start := time.Now()
+	err := scanCategory(d.Conn.QueryRowContext(ctx, query, id, request.Name, request.ParentID), &category)
+	metrics.ObserveQuery("UpdateCategory", start, queryError(err))
+	if foreignKeyViolation(err) {
+		return nil, ErrCategoryNotFound
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		exists, err := d.categoryExists(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, sql.ErrNoRows
+		}
+		return nil, ErrCategoryCycle
+	}
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting UpdateCategory DB Function")
+	return &category, nil
+}
+
+// DeleteCategory removes a category without subcategories, its product assignments go with it.
+// It returns sql.ErrNoRows when the category doesn't exist and ErrCategoryHasChildren when it has subcategories.
+func (d *PGConnector) DeleteCategory(ctx context.Context, id int) error {
+	slog.DebugContext(ctx, "Entering DeleteCategory DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	result, err := d.Conn.ExecContext(ctx, "DELETE FROM categories WHERE id = $1", id)
+	metrics.ObserveQuery("DeleteCategory", start, err)
+	if foreignKeyViolation(err) {
+		return ErrCategoryHasChildren
+	}
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	slog.DebugContext(ctx, "Exiting DeleteCategory DB Function")
+	return nil
+}
+
+func (d *PGConnector) categoryExists(ctx context.Context, id int) (bool, error) {
+	var exists bool
+	start := time.Now()
+	err := d.Conn.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1)", id).Scan(&exists)
+	metrics.ObserveQuery("CategoryExists", start, err)
+	return exists, err
+}
+
+// GetProductCategories returns the categories the product is assigned to,
+// sql.ErrNoRows when the product doesn't exist or is deleted
+func (d *PGConnector) GetProductCategories(ctx context.Context, productID int) (categories []*models.Category, err error) {
+	slog.DebugContext(ctx, "Entering GetProductCategories DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery("GetProductCategories", start, queryError(err))
+	}()
+
+	query := "SELECT c.id, c.parent_id, c.name, c.created_at, c.updated_at FROM categories c " +
+		"JOIN product_categories pc ON pc.category_id = c.id WHERE pc.product_id = $1 ORDER BY lower(c.name), c.id"
+	rows, err := d.Conn.QueryContext(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories = []*models.Category{}
+	for rows.Next() {
+		var category models.Category
+		if err = scanCategory(rows, &category); err != nil {
+			return nil, err
+		}
+		categories = append(categories, &category)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		// tell a product without categories apart from a missing one
+		var exists bool
+		if exists, err = productExists(ctx, d.Conn, productID); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, sql.ErrNoRows
+		}
+	}
+	slog.DebugContext(ctx, "Exiting GetProductCategories DB Function")
+	return categories, nil
+}
+
+// SetProductCategories replaces the categories of a product in one transaction. It returns sql.ErrNoRows
+// when the product doesn't exist or is deleted and ErrCategoryNotFound when one of the categories doesn't exist.
+func (d *PGConnector) SetProductCategories(ctx context.Context, productID int, categoryIDs []int) (err error) {
+	slog.DebugContext(ctx, "Entering SetProductCategories DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery("SetProductCategories", start, queryError(err))
+	}()
+
+	tx, err := d.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// locking the product row keeps a concurrent delete from interleaving with the new assignments
+	var id int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", productID).Scan(&id)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM product_categories WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+	if len(categoryIDs) > 0 {
+		_, err = tx.ExecContext(ctx, "INSERT INTO product_categories (product_id, category_id) SELECT $1, unnest($2::integer[])",
+			productID, pq.Array(categoryIDs))
+		if foreignKeyViolation(err) {
+			return ErrCategoryNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "Exiting SetProductCategories DB Function")
+	return nil
+}
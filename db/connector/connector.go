@@ -10,7 +10,7 @@ var (
 	RedisConnector db.CacheInterface
 )
 
-func Connector() {
-	PGDBConnector = db.NewPGConnector(config.PostgresConn)
-	RedisConnector = db.NewRedisConnector(config.RedisClient)
+func Connector(cfg *config.Config) {
+	PGDBConnector = db.NewPGConnector(config.PostgresConn, cfg.DBQueryTimeout)
+	RedisConnector = db.NewRedisConnector(config.RedisClient, cfg.CacheTimeout)
 }
@@ -1,14 +1,78 @@
 package db
 
-import "ProductService/models"
+import (
+	"ProductService/models"
+	"context"
+	"time"
+)
 
 type DBOperations interface {
 	// Should contain all the postgres operations
 	Try()
-	GetProductByID(id int) (*models.Product, error)
-	GetAllProducts(offset int, pageSize int) ([]*models.Product, error)
-	CreateProduct(product *models.CreateProductRequest) (int, error)
-	UpdateProduct(product *models.Product) error
-	DeleteProduct(id int) error
-	GetProductCount() (int, error)
+	GetProductByID(ctx context.Context, id int) (*models.Product, error)
+	GetAllProducts(ctx context.Context, filter models.ProductFilter, offset int, pageSize int) ([]*models.Product, error)
+	CreateProduct(ctx context.Context, product *models.CreateProductRequest) (*models.Product, error)
+	// UpdateProduct, DeleteProduct and RestoreProduct only write when the stored version is one of versions,
+	// an empty list skips the check. PatchProduct does the same for a single version, 0 skips the check.
+	// A mismatch is reported as ErrVersionMismatch.
+	// PatchProduct updates only the given columns, keys must be in productPatchColumns.
+	// DeleteProduct is a soft delete, deleted products are hidden from every read until restored.
+	UpdateProduct(ctx context.Context, product *models.Product, versions []int) error
+	PatchProduct(ctx context.Context, id int, version int, changes map[string]interface{}) (*models.Product, error)
+	DeleteProduct(ctx context.Context, id int, versions []int) error
+	RestoreProduct(ctx context.Context, id int, versions []int) (*models.Product, error)
+	// PurgeDeletedProducts permanently removes up to limit products deleted before the given time
+	PurgeDeletedProducts(ctx context.Context, before time.Time, limit int) (int64, error)
+	GetProductCount(ctx context.Context, filter models.ProductFilter) (int, error)
+	GetProductsAfter(ctx context.Context, filter models.ProductFilter, cursor *models.ProductCursor, limit int) ([]*models.Product, error)
+	// ExecBatch runs the operations in order, see PGConnector.ExecBatch
+	ExecBatch(ctx context.Context, operations []models.BatchOperation, atomic bool) ([]BatchOutcome, error)
+
+	// Category operations, see db/categories.go for the errors each one reports
+	ListCategories(ctx context.Context) ([]*models.Category, error)
+	CreateCategory(ctx context.Context, request *models.CategoryRequest) (*models.Category, error)
+	UpdateCategory(ctx context.Context, id int, request *models.CategoryRequest) (*models.Category, error)
+	DeleteCategory(ctx context.Context, id int) error
+	GetProductCategories(ctx context.Context, productID int) ([]*models.Category, error)
+	// SetProductCategories replaces every category assignment of the product
+	SetProductCategories(ctx context.Context, productID int, categoryIDs []int) error
+
+	// Variant operations, see db/variants.go. They report sql.ErrNoRows for a missing or deleted product
+	// and ErrVariantNotFound for a missing variant, writes also bump the product's version.
+	ListProductVariants(ctx context.Context, productID int) ([]*models.ProductVariant, error)
+	GetProductVariant(ctx context.Context, productID int, variantID int) (*models.ProductVariant, error)
+	CreateProductVariant(ctx context.Context, productID int, request *models.VariantRequest) (*models.ProductVariant, error)
+	UpdateProductVariant(ctx context.Context, productID int, variantID int, request *models.VariantRequest) (*models.ProductVariant, error)
+	DeleteProductVariant(ctx context.Context, productID int, variantID int) error
+
+	// Inventory operations, see db/inventory.go. They report sql.ErrNoRows for a missing or deleted product,
+	// ErrInsufficientStock when stock would be oversold and ErrReservationNotFound for a missing reservation.
+	GetInventory(ctx context.Context, productID int) (*models.Inventory, error)
+	SetStockLevel(ctx context.Context, productID int, warehouse string, onHand int) (*models.StockLevel, error)
+	AdjustStockLevel(ctx context.Context, productID int, warehouse string, delta int) (*models.StockLevel, error)
+	// ReserveStock uses the warehouse with the most available stock when warehouse is empty
+	ReserveStock(ctx context.Context, productID int, warehouse string, quantity int, expiresAt time.Time) (*models.Reservation, error)
+	CommitReservation(ctx context.Context, productID int, reservationID int) error
+	ReleaseReservation(ctx context.Context, productID int, reservationID int) error
+	// ExpireReservations releases up to limit reservations that expired before now
+	ExpireReservations(ctx context.Context, now time.Time, limit int) (int64, error)
+
+	// Price history, see db/prices.go. Changes are recorded by UpdateProduct, PatchProduct and batch updates.
+	GetPriceHistoryCount(ctx context.Context, productID int) (int, error)
+	GetPriceHistory(ctx context.Context, productID int, offset int, pageSize int) ([]*models.PriceChange, error)
+	GetPriceAt(ctx context.Context, productID int, at time.Time) (*models.EffectivePrice, error)
+
+	// Scheduled prices, see db/scheduledprices.go
+	ListScheduledPrices(ctx context.Context, productID int) ([]*models.ScheduledPrice, error)
+	GetScheduledPrice(ctx context.Context, productID int, scheduleID int) (*models.ScheduledPrice, error)
+	CreateScheduledPrice(ctx context.Context, productID int, request *models.ScheduledPriceRequest) (*models.ScheduledPrice, error)
+	CancelScheduledPrice(ctx context.Context, productID int, scheduleID int) error
+	// RunPriceSchedule applies and reverts up to limit scheduled prices due by now and returns their product ids
+	RunPriceSchedule(ctx context.Context, now time.Time, limit int) ([]int, error)
+}
+
+// BatchOutcome is the result of one batch operation, Product is nil for deletes and failed operations
+type BatchOutcome struct {
+	Product *models.Product
+	Err     error
 }
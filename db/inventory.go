@@ -0,0 +1,267 @@
+package db
+
+import (
+	"ProductService/metrics"
+	"ProductService/models"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log/slog"
+	"time"
+)
+
+// stockLevelColumns is the column list every stock level query selects, in the order scanStockLevel reads them
+const stockLevelColumns = "warehouse, on_hand, reserved, updated_at"
+
+// reservationColumns is the column list every reservation query selects, in the order scanReservation reads them
+const reservationColumns = "id, product_id, warehouse, quantity, expires_at, created_at"
+
+var (
+	// ErrInsufficientStock is returned when a reservation asks for more than is available
+	// or an adjustment would take the stock below zero or below what is reserved
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrReservationNotFound is returned when the reservation doesn't exist, was already committed or released, or expired
+	ErrReservationNotFound = errors.New("reservation not found")
+)
+
+func scanStockLevel(row interface{ Scan(dest ...any) error }, level *models.StockLevel) error {
+	err := row.Scan(&level.Warehouse, &level.OnHand, &level.Reserved, &level.UpdatedAt)
+	level.Available = level.OnHand - level.Reserved
+	return err
+}
+
+func scanReservation(row interface{ Scan(dest ...any) error }, reservation *models.Reservation) error {
+	return row.Scan(&reservation.ID, &reservation.ProductID, &reservation.Warehouse, &reservation.Quantity,
+		&reservation.ExpiresAt, &reservation.CreatedAt)
+}
+
+// checkViolation reports whether err comes from a check constraint
+func checkViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23514"
+}
+
+// GetInventory returns the stock of the product in every warehouse and the totals,
+// sql.ErrNoRows when the product doesn't exist or is deleted
+func (d *PGConnector) GetInventory(ctx context.Context, productID int) (inventory *models.Inventory, err error) {
+	slog.DebugContext(ctx, "Entering GetInventory DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery("GetInventory", start, queryError(err))
+	}()
+
+	query := "SELECT i.warehouse, i.on_hand, i.reserved, i.updated_at FROM inventory i " +
+		"JOIN products p ON p.id = i.product_id AND p.deleted_at IS NULL WHERE i.product_id = $1 ORDER BY i.warehouse"
+	rows, err := d.Conn.QueryContext(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inventory = &models.Inventory{ProductID: productID, Warehouses: []*models.StockLevel{}}
+	for rows.Next() {
+		var level models.StockLevel
+		if err = scanStockLevel(rows, &level); err != nil {
+			return nil, err
+		}
+		inventory.OnHand += level.OnHand
+		inventory.Reserved += level.Reserved
+		inventory.Available += level.Available
+		inventory.Warehouses = append(inventory.Warehouses, &level)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(inventory.Warehouses) == 0 {
+		// tell a product without stock apart from a missing one
+		var exists bool
+		if exists, err = productExists(ctx, d.Conn, productID); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, sql.ErrNoRows
+		}
+	}
+	slog.DebugContext(ctx, "Exiting GetInventory DB Function")
+	return inventory, nil
+}
+
+// SetStockLevel sets the on hand stock of the product in the warehouse, e.g. after a count. It returns
+// sql.ErrNoRows when the product doesn't exist or is deleted and ErrInsufficientStock when the stock
+// would drop below what is reserved.
+func (d *PGConnector) SetStockLevel(ctx context.Context, productID int, warehouse string, onHand int) (*models.StockLevel, error) {
+	slog.DebugContext(ctx, "Entering SetStockLevel DB Function")
+	level, err := d.writeStockLevel(ctx, "SetStockLevel", "EXCLUDED.on_hand", productID, warehouse, onHand)
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting SetStockLevel DB Function")
+	return level, nil
+}
+
+// AdjustStockLevel adds delta to the on hand stock of the product in the warehouse, a negative delta writes
+// stock off. It reports the same errors as SetStockLevel, a negative stock is ErrInsufficientStock too.
+func (d *PGConnector) AdjustStockLevel(ctx context.Context, productID int, warehouse string, delta int) (*models.StockLevel, error) {
+	slog.DebugContext(ctx, "Entering AdjustStockLevel DB Function")
+	level, err := d.writeStockLevel(ctx, "AdjustStockLevel", "inventory.on_hand + EXCLUDED.on_hand", productID, warehouse, delta)
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting AdjustStockLevel DB Function")
+	return level, nil
+}
+
+// writeStockLevel creates the stock level with onHand or sets an existing one to the onHand expression,
+// which reads the value passed in as EXCLUDED.on_hand
+func (d *PGConnector) writeStockLevel(ctx context.Context, operation string, onHand string, productID int, warehouse string, value int) (*models.StockLevel, error) {
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	// selecting from products skips the insert for a missing or deleted product, so no row is returned
+	query := "INSERT INTO inventory (product_id, warehouse, on_hand) SELECT id, $2, $3 FROM products WHERE id = $1 AND deleted_at IS NULL " +
+		"ON CONFLICT (product_id, warehouse) DO UPDATE SET on_hand = " + onHand + ", updated_at = now() RETURNING " + stockLevelColumns
+	var level models.StockLevel
+	start := time.Now()
+	err := scanStockLevel(d.Conn.QueryRowContext(ctx, query, productID, warehouse, value), &level)
+	metrics.ObserveQuery(operation, start, queryError(err))
+	if checkViolation(err) {
+		return nil, ErrInsufficientStock
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &level, nil
+}
+
+// ReserveStock holds quantity of the product's available stock until expiresAt, in the given warehouse or,
+// when it is empty, in the warehouse with the most available stock that covers the whole quantity.
+// The stock is taken with a conditional update, so concurrent reservations can never oversell.
+// It returns sql.ErrNoRows when the product doesn't exist or is deleted and ErrInsufficientStock
+// when no warehouse has enough available stock.
+func (d *PGConnector) ReserveStock(ctx context.Context, productID int, warehouse string, quantity int, expiresAt time.Time) (reservation *models.Reservation, err error) {
+	slog.DebugContext(ctx, "Entering ReserveStock DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery("ReserveStock", start, queryError(err))
+	}()
+
+	tx, err := d.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the availability check is repeated on the locked row, a concurrent reservation that took the stock first makes it fail
+	query := "UPDATE inventory SET reserved = reserved + $3, updated_at = now() " +
+		"WHERE product_id = $1 AND warehouse = $2 AND on_hand - reserved >= $3 " +
+		"AND EXISTS (SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL) RETURNING warehouse"
+	args := []any{productID, warehouse, quantity}
+	if warehouse == "" {
+		// SKIP LOCKED is not used, a warehouse locked by another reservation may still have enough stock afterwards
+		query = "UPDATE inventory SET reserved = reserved + $2, updated_at = now() " +
+			"WHERE (product_id, warehouse) = (SELECT product_id, warehouse FROM inventory WHERE product_id = $1 AND on_hand - reserved >= $2 " +
+			"ORDER BY on_hand - reserved DESC, warehouse LIMIT 1 FOR UPDATE) " +
+			"AND on_hand - reserved >= $2 AND EXISTS (SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL) RETURNING warehouse"
+		args = []any{productID, quantity}
+	}
+	var reserved string
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&reserved)
+	if errors.Is(err, sql.ErrNoRows) {
+		exists, existsErr := productExists(ctx, tx, productID)
+		if existsErr != nil {
+			return nil, existsErr
+		}
+		if exists {
+			return nil, ErrInsufficientStock
+		}
+		return nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reservation = &models.Reservation{}
+	query = "INSERT INTO reservations (product_id, warehouse, quantity, expires_at) VALUES ($1, $2, $3, $4) RETURNING " + reservationColumns
+	if err = scanReservation(tx.QueryRowContext(ctx, query, productID, reserved, quantity, expiresAt), reservation); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting ReserveStock DB Function")
+	return reservation, nil
+}
+
+// CommitReservation takes the reserved stock out of the warehouse for good, e.g. once the order is paid
+func (d *PGConnector) CommitReservation(ctx context.Context, productID int, reservationID int) error {
+	slog.DebugContext(ctx, "Entering CommitReservation DB Function")
+	err := d.endReservation(ctx, "CommitReservation", "on_hand = i.on_hand - r.quantity, reserved = i.reserved - r.quantity", productID, reservationID)
+	if err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "Exiting CommitReservation DB Function")
+	return nil
+}
+
+// ReleaseReservation gives the reserved stock back, e.g. when the checkout is abandoned
+func (d *PGConnector) ReleaseReservation(ctx context.Context, productID int, reservationID int) error {
+	slog.DebugContext(ctx, "Entering ReleaseReservation DB Function")
+	err := d.endReservation(ctx, "ReleaseReservation", "reserved = i.reserved - r.quantity", productID, reservationID)
+	if err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "Exiting ReleaseReservation DB Function")
+	return nil
+}
+
+// endReservation removes a live reservation and applies set to its stock level in one statement.
+// Expired reservations are left to ExpireReservations and reported as ErrReservationNotFound.
+func (d *PGConnector) endReservation(ctx context.Context, operation string, set string, productID int, reservationID int) error {
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	query := "WITH r AS (DELETE FROM reservations WHERE id = $1 AND product_id = $2 AND expires_at > now() " +
+		"RETURNING product_id, warehouse, quantity) " +
+		"UPDATE inventory i SET " + set + ", updated_at = now() FROM r WHERE i.product_id = r.product_id AND i.warehouse = r.warehouse"
+	start := time.Now()
+	result, err := d.Conn.ExecContext(ctx, query, reservationID, productID)
+	metrics.ObserveQuery(operation, start, err)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrReservationNotFound
+	}
+	return nil
+}
+
+// ExpireReservations removes up to limit reservations that expired before now, gives their stock back
+// and returns how many were removed. Reservations being committed or released concurrently are skipped,
+// so several instances can run it at once.
+func (d *PGConnector) ExpireReservations(ctx context.Context, now time.Time, limit int) (int64, error) {
+	slog.DebugContext(ctx, "Entering ExpireReservations DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	query := "WITH expired AS (DELETE FROM reservations WHERE id IN (SELECT id FROM reservations WHERE expires_at <= $1 " +
+		"ORDER BY expires_at LIMIT $2 FOR UPDATE SKIP LOCKED) RETURNING product_id, warehouse, quantity), " +
+		"totals AS (SELECT product_id, warehouse, SUM(quantity) AS quantity FROM expired GROUP BY product_id, warehouse), " +
+		"released AS (UPDATE inventory i SET reserved = i.reserved - totals.quantity, updated_at = now() FROM totals " +
+		"WHERE i.product_id = totals.product_id AND i.warehouse = totals.warehouse) " +
+		"SELECT COUNT(*) FROM expired"
+	var expired int64
+	start := time.Now()
+	err := d.Conn.QueryRowContext(ctx, query, now, limit).Scan(&expired)
+	metrics.ObserveQuery("ExpireReservations", start, err)
+	if err != nil {
+		return 0, err
+	}
+	slog.DebugContext(ctx, "Exiting ExpireReservations DB Function")
+	return expired, nil
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad_EmbeddedMigrationsAreOrdered(t *testing.T) {
+	migrations, err := Load()
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		assert.NotEmpty(t, m.Up)
+		assert.NotEmpty(t, m.Down)
+		if i > 0 {
+			assert.Greater(t, m.Version, migrations[i-1].Version)
+		}
+	}
+}
+
+func TestLoad_InvalidFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{"missing up file", fstest.MapFS{"sql/0001_a.down.sql": {Data: []byte("x")}}},
+		{"bad version", fstest.MapFS{"sql/abc_a.up.sql": {Data: []byte("x")}}},
+		{"duplicate version", fstest.MapFS{
+			"sql/0001_a.up.sql": {Data: []byte("x")},
+			"sql/0001_b.up.sql": {Data: []byte("x")},
+		}},
+		{"unknown suffix", fstest.MapFS{"sql/0001_a.sql": {Data: []byte("x")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(tt.files, "sql")
+			assert.Error(t, err)
+		})
+	}
+}
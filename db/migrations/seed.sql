@@ -0,0 +1,6 @@
+INSERT INTO products (sku, name, description, price, currency, status, attributes, tags) VALUES
+('MOU-001', 'Wireless Mouse', '2.4 GHz optical mouse', 25.99, 'USD', 'active', '{"color": "black", "wireless": true}', '{peripherals}'),
+('KEY-001', 'Mechanical Keyboard', 'Full size keyboard with brown switches', 89.50, 'USD', 'active', '{"color": "white", "switch": "brown"}', '{peripherals}'),
+('MON-001', 'HD Monitor', '24 inch 1080p monitor', 199.99, 'USD', 'active', '{"size_in": 24}', '{displays}'),
+('HUB-001', 'USB-C Hub', '7 in 1 USB-C hub', 39.95, 'USD', 'active', '{"ports": 7}', '{accessories}'),
+('AUD-001', 'Noise Cancelling Headphones', 'Over-ear bluetooth headphones', 129.00, 'USD', 'active', '{"color": "black", "wireless": true}', '{audio,bestseller}');
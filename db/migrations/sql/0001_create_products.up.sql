@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS products (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	price REAL NOT NULL
+);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS products_sku_key;
+ALTER TABLE products ALTER COLUMN price TYPE REAL;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_status_check;
+ALTER TABLE products
+	DROP COLUMN IF EXISTS updated_at,
+	DROP COLUMN IF EXISTS created_at,
+	DROP COLUMN IF EXISTS status,
+	DROP COLUMN IF EXISTS currency,
+	DROP COLUMN IF EXISTS description,
+	DROP COLUMN IF EXISTS sku;
@@ -0,0 +1,21 @@
+ALTER TABLE products
+	ADD COLUMN IF NOT EXISTS sku TEXT,
+	ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD',
+	ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active',
+	ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- existing products get a placeholder SKU so the column can be required
+UPDATE products SET sku = 'SKU-' || id WHERE sku IS NULL;
+ALTER TABLE products ALTER COLUMN sku SET NOT NULL;
+
+-- existing products were already visible, new ones start as drafts
+ALTER TABLE products ALTER COLUMN status SET DEFAULT 'draft';
+ALTER TABLE products ADD CONSTRAINT products_status_check CHECK (status IN ('draft', 'active', 'archived'));
+
+-- REAL only keeps about 7 significant digits, NUMERIC keeps cents exact
+ALTER TABLE products ALTER COLUMN price TYPE NUMERIC(19, 4) USING round(price::numeric, 2);
+
+-- deleted products release their SKU so it can be reused
+CREATE UNIQUE INDEX IF NOT EXISTS products_sku_key ON products (sku) WHERE deleted_at IS NULL;
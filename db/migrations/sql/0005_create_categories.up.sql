@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS categories (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	-- a category can't be removed while it still has children
+	parent_id INTEGER REFERENCES categories (id) ON DELETE RESTRICT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	CONSTRAINT categories_parent_check CHECK (parent_id <> id)
+);
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories (parent_id);
+-- sibling names are unique, top level categories count as siblings of each other
+CREATE UNIQUE INDEX IF NOT EXISTS categories_name_key ON categories (COALESCE(parent_id, 0), lower(name));
+
+CREATE TABLE IF NOT EXISTS product_categories (
+	product_id INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+	category_id INTEGER NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+	PRIMARY KEY (product_id, category_id)
+);
+CREATE INDEX IF NOT EXISTS product_categories_category_id_idx ON product_categories (category_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS products_tags_idx;
+DROP INDEX IF EXISTS products_attributes_idx;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_attributes_check;
+ALTER TABLE products
+	DROP COLUMN IF EXISTS tags,
+	DROP COLUMN IF EXISTS attributes;
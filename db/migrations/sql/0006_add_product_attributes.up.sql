@@ -0,0 +1,8 @@
+ALTER TABLE products
+	ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}'::jsonb,
+	ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE products ADD CONSTRAINT products_attributes_check CHECK (jsonb_typeof(attributes) = 'object');
+
+-- attr.<key> filters are containment queries (attributes @> ...), jsonb_path_ops is smaller and faster for those
+CREATE INDEX IF NOT EXISTS products_attributes_idx ON products USING GIN (attributes jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS products_tags_idx ON products USING GIN (tags);
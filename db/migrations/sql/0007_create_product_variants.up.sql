@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS product_variants (
+	id SERIAL PRIMARY KEY,
+	-- purging a product removes its variants
+	product_id INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+	sku TEXT NOT NULL,
+	options JSONB NOT NULL CHECK (jsonb_typeof(options) = 'object'),
+	-- NULL means the variant sells at the product price
+	price NUMERIC(19, 4) CHECK (price > 0),
+	available BOOLEAN NOT NULL DEFAULT true,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	-- set to the product's deleted_at when the product is soft deleted, so a restore brings back exactly these variants
+	deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS product_variants_product_id_idx ON product_variants (product_id);
+-- like product SKUs, variant SKUs are released when the product is deleted
+CREATE UNIQUE INDEX IF NOT EXISTS product_variants_sku_key ON product_variants (sku) WHERE deleted_at IS NULL;
+-- a product can't have two variants with the same options
+CREATE UNIQUE INDEX IF NOT EXISTS product_variants_options_key ON product_variants (product_id, options) WHERE deleted_at IS NULL;
@@ -0,0 +1,24 @@
+-- stock of a product in one warehouse, available stock is on_hand - reserved
+CREATE TABLE IF NOT EXISTS inventory (
+	product_id INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+	warehouse TEXT NOT NULL,
+	on_hand INTEGER NOT NULL DEFAULT 0,
+	reserved INTEGER NOT NULL DEFAULT 0,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (product_id, warehouse),
+	-- stock can neither go negative nor drop below what is reserved
+	CONSTRAINT inventory_stock_check CHECK (reserved >= 0 AND on_hand >= reserved)
+);
+
+-- stock held for a checkout until it is committed, released or expires
+CREATE TABLE IF NOT EXISTS reservations (
+	id SERIAL PRIMARY KEY,
+	product_id INTEGER NOT NULL,
+	warehouse TEXT NOT NULL,
+	quantity INTEGER NOT NULL CHECK (quantity > 0),
+	expires_at TIMESTAMPTZ NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	FOREIGN KEY (product_id, warehouse) REFERENCES inventory (product_id, warehouse) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS reservations_expires_at_idx ON reservations (expires_at);
+CREATE INDEX IF NOT EXISTS reservations_inventory_idx ON reservations (product_id, warehouse);
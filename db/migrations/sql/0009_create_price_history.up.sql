@@ -0,0 +1,13 @@
+-- every change of a product's price or currency, written in the same transaction as the change
+CREATE TABLE IF NOT EXISTS price_history (
+	id BIGSERIAL PRIMARY KEY,
+	product_id INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+	old_price NUMERIC(19, 4) NOT NULL,
+	new_price NUMERIC(19, 4) NOT NULL,
+	old_currency CHAR(3) NOT NULL,
+	new_currency CHAR(3) NOT NULL,
+	changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	-- the X-Actor of the request, NULL when it had none
+	actor TEXT
+);
+CREATE INDEX IF NOT EXISTS price_history_product_id_idx ON price_history (product_id, changed_at, id);
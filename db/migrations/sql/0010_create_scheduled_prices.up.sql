@@ -0,0 +1,22 @@
+-- a price change queued for a window, applied at effective_from and reverted at effective_to by the price scheduler
+CREATE TABLE IF NOT EXISTS scheduled_prices (
+	id SERIAL PRIMARY KEY,
+	product_id INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+	price NUMERIC(19, 4) NOT NULL,
+	-- NULL until applied when the request kept the product's currency
+	currency CHAR(3),
+	effective_from TIMESTAMPTZ NOT NULL,
+	-- NULL makes the change permanent
+	effective_to TIMESTAMPTZ,
+	status TEXT NOT NULL DEFAULT 'pending',
+	-- the price the change replaced, put back at effective_to
+	previous_price NUMERIC(19, 4),
+	previous_currency CHAR(3),
+	-- the X-Actor of the request, NULL when it had none
+	actor TEXT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	CONSTRAINT scheduled_prices_window_check CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+CREATE INDEX IF NOT EXISTS scheduled_prices_product_id_idx ON scheduled_prices (product_id, effective_from);
+CREATE INDEX IF NOT EXISTS scheduled_prices_pending_idx ON scheduled_prices (effective_from) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS scheduled_prices_active_idx ON scheduled_prices (effective_to) WHERE status = 'active';
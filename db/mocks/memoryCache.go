@@ -0,0 +1,116 @@
+package mocks
+
+import (
+	"ProductService/models"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryCache is a working in-memory CacheInterface, used where tests need real
+// read-after-write behaviour instead of scripted expectations
+type MemoryCache struct {
+	mu          sync.Mutex
+	items       map[string]models.Product
+	idempotency map[string]models.IdempotencyRecord
+	tree        []*models.CategoryNode
+	rates       map[string]string
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		items:       map[string]models.Product{},
+		idempotency: map[string]models.IdempotencyRecord{},
+		rates:       map[string]string{},
+	}
+}
+
+func (m *MemoryCache) GetProductByID(ctx context.Context, id string) (*models.Product, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	product, ok := m.items[id]
+	if !ok {
+		return nil, nil
+	}
+	return &product, nil
+}
+
+func (m *MemoryCache) SetProductByID(ctx context.Context, id string, product *models.Product, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[id] = *product
+	return nil
+}
+
+func (m *MemoryCache) DeleteProductFromCache(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, id)
+	return nil
+}
+
+func (m *MemoryCache) DeleteProductsFromCache(ctx context.Context, ids []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.items, id)
+	}
+	return nil
+}
+
+func (m *MemoryCache) ReserveIdempotencyKey(ctx context.Context, key string, record *models.IdempotencyRecord, ttl time.Duration) (*models.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.idempotency[key]; ok {
+		return &existing, false, nil
+	}
+	m.idempotency[key] = *record
+	return nil, true, nil
+}
+
+func (m *MemoryCache) SaveIdempotencyKey(ctx context.Context, key string, record *models.IdempotencyRecord, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idempotency[key] = *record
+	return nil
+}
+
+func (m *MemoryCache) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.idempotency, key)
+	return nil
+}
+
+func (m *MemoryCache) GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tree, nil
+}
+
+func (m *MemoryCache) SetCategoryTree(ctx context.Context, tree []*models.CategoryNode, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tree = tree
+	return nil
+}
+
+func (m *MemoryCache) DeleteCategoryTree(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tree = nil
+	return nil
+}
+
+func (m *MemoryCache) GetExchangeRate(ctx context.Context, from string, to string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rates[from+":"+to], nil
+}
+
+func (m *MemoryCache) SetExchangeRate(ctx context.Context, from string, to string, rate string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rates[from+":"+to] = rate
+	return nil
+}
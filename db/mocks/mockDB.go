@@ -1,8 +1,11 @@
 package mocks
 
 import (
+	"ProductService/db"
 	"ProductService/models"
+	"context"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type MockDBOperations struct {
@@ -13,8 +16,8 @@ func (m *MockDBOperations) Try() {
 	m.Called()
 }
 
-func (m *MockDBOperations) GetProductByID(id int) (*models.Product, error) {
-	args := m.Called(id)
+func (m *MockDBOperations) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	args := m.Called(ctx, id)
 	product, ok := args.Get(0).(*models.Product)
 	if !ok {
 		return nil, args.Error(1)
@@ -22,8 +25,8 @@ func (m *MockDBOperations) GetProductByID(id int) (*models.Product, error) {
 	return product, args.Error(1)
 }
 
-func (m *MockDBOperations) GetAllProducts(offset int, pageSize int) ([]*models.Product, error) {
-	args := m.Called(offset, pageSize)
+func (m *MockDBOperations) GetAllProducts(ctx context.Context, filter models.ProductFilter, offset int, pageSize int) ([]*models.Product, error) {
+	args := m.Called(ctx, filter, offset, pageSize)
 	products, ok := args.Get(0).([]*models.Product)
 	if !ok {
 		return nil, args.Error(1)
@@ -31,22 +34,212 @@ func (m *MockDBOperations) GetAllProducts(offset int, pageSize int) ([]*models.P
 	return products, args.Error(1)
 }
 
-func (m *MockDBOperations) CreateProduct(product *models.CreateProductRequest) (int, error) {
-	args := m.Called(product)
+func (m *MockDBOperations) CreateProduct(ctx context.Context, product *models.CreateProductRequest) (*models.Product, error) {
+	args := m.Called(ctx, product)
+	created, ok := args.Get(0).(*models.Product)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return created, args.Error(1)
+}
+
+func (m *MockDBOperations) UpdateProduct(ctx context.Context, product *models.Product, versions []int) error {
+	args := m.Called(ctx, product, versions)
+	return args.Error(0)
+}
+
+func (m *MockDBOperations) PatchProduct(ctx context.Context, id int, version int, changes map[string]interface{}) (*models.Product, error) {
+	args := m.Called(ctx, id, version, changes)
+	product, ok := args.Get(0).(*models.Product)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return product, args.Error(1)
+}
+
+func (m *MockDBOperations) DeleteProduct(ctx context.Context, id int, versions []int) error {
+	args := m.Called(ctx, id, versions)
+	return args.Error(0)
+}
+
+func (m *MockDBOperations) RestoreProduct(ctx context.Context, id int, versions []int) (*models.Product, error) {
+	args := m.Called(ctx, id, versions)
+	product, ok := args.Get(0).(*models.Product)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return product, args.Error(1)
+}
+
+func (m *MockDBOperations) PurgeDeletedProducts(ctx context.Context, before time.Time, limit int) (int64, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDBOperations) GetProductCount(ctx context.Context, filter models.ProductFilter) (int, error) {
+	args := m.Called(ctx, filter)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockDBOperations) UpdateProduct(product *models.Product) error {
-	args := m.Called(product)
+func (m *MockDBOperations) GetProductsAfter(ctx context.Context, filter models.ProductFilter, cursor *models.ProductCursor, limit int) ([]*models.Product, error) {
+	args := m.Called(ctx, filter, cursor, limit)
+	products, ok := args.Get(0).([]*models.Product)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return products, args.Error(1)
+}
+
+func (m *MockDBOperations) ExecBatch(ctx context.Context, operations []models.BatchOperation, atomic bool) ([]db.BatchOutcome, error) {
+	args := m.Called(ctx, operations, atomic)
+	outcomes, _ := args.Get(0).([]db.BatchOutcome)
+	return outcomes, args.Error(1)
+}
+
+func (m *MockDBOperations) ListCategories(ctx context.Context) ([]*models.Category, error) {
+	args := m.Called(ctx)
+	categories, _ := args.Get(0).([]*models.Category)
+	return categories, args.Error(1)
+}
+
+func (m *MockDBOperations) CreateCategory(ctx context.Context, request *models.CategoryRequest) (*models.Category, error) {
+	args := m.Called(ctx, request)
+	category, _ := args.Get(0).(*models.Category)
+	return category, args.Error(1)
+}
+
+func (m *MockDBOperations) UpdateCategory(ctx context.Context, id int, request *models.CategoryRequest) (*models.Category, error) {
+	args := m.Called(ctx, id, request)
+	category, _ := args.Get(0).(*models.Category)
+	return category, args.Error(1)
+}
+
+func (m *MockDBOperations) DeleteCategory(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDBOperations) GetProductCategories(ctx context.Context, productID int) ([]*models.Category, error) {
+	args := m.Called(ctx, productID)
+	categories, _ := args.Get(0).([]*models.Category)
+	return categories, args.Error(1)
+}
+
+func (m *MockDBOperations) SetProductCategories(ctx context.Context, productID int, categoryIDs []int) error {
+	args := m.Called(ctx, productID, categoryIDs)
 	return args.Error(0)
 }
 
-func (m *MockDBOperations) DeleteProduct(id int) error {
-	args := m.Called(id)
+func (m *MockDBOperations) ListProductVariants(ctx context.Context, productID int) ([]*models.ProductVariant, error) {
+	args := m.Called(ctx, productID)
+	variants, _ := args.Get(0).([]*models.ProductVariant)
+	return variants, args.Error(1)
+}
+
+func (m *MockDBOperations) GetProductVariant(ctx context.Context, productID int, variantID int) (*models.ProductVariant, error) {
+	args := m.Called(ctx, productID, variantID)
+	variant, _ := args.Get(0).(*models.ProductVariant)
+	return variant, args.Error(1)
+}
+
+func (m *MockDBOperations) CreateProductVariant(ctx context.Context, productID int, request *models.VariantRequest) (*models.ProductVariant, error) {
+	args := m.Called(ctx, productID, request)
+	variant, _ := args.Get(0).(*models.ProductVariant)
+	return variant, args.Error(1)
+}
+
+func (m *MockDBOperations) UpdateProductVariant(ctx context.Context, productID int, variantID int, request *models.VariantRequest) (*models.ProductVariant, error) {
+	args := m.Called(ctx, productID, variantID, request)
+	variant, _ := args.Get(0).(*models.ProductVariant)
+	return variant, args.Error(1)
+}
+
+func (m *MockDBOperations) DeleteProductVariant(ctx context.Context, productID int, variantID int) error {
+	args := m.Called(ctx, productID, variantID)
 	return args.Error(0)
 }
 
-func (m *MockDBOperations) GetProductCount() (int, error) {
-	args := m.Called()
+func (m *MockDBOperations) GetInventory(ctx context.Context, productID int) (*models.Inventory, error) {
+	args := m.Called(ctx, productID)
+	inventory, _ := args.Get(0).(*models.Inventory)
+	return inventory, args.Error(1)
+}
+
+func (m *MockDBOperations) SetStockLevel(ctx context.Context, productID int, warehouse string, onHand int) (*models.StockLevel, error) {
+	args := m.Called(ctx, productID, warehouse, onHand)
+	level, _ := args.Get(0).(*models.StockLevel)
+	return level, args.Error(1)
+}
+
+func (m *MockDBOperations) AdjustStockLevel(ctx context.Context, productID int, warehouse string, delta int) (*models.StockLevel, error) {
+	args := m.Called(ctx, productID, warehouse, delta)
+	level, _ := args.Get(0).(*models.StockLevel)
+	return level, args.Error(1)
+}
+
+func (m *MockDBOperations) ReserveStock(ctx context.Context, productID int, warehouse string, quantity int, expiresAt time.Time) (*models.Reservation, error) {
+	args := m.Called(ctx, productID, warehouse, quantity, expiresAt)
+	reservation, _ := args.Get(0).(*models.Reservation)
+	return reservation, args.Error(1)
+}
+
+func (m *MockDBOperations) CommitReservation(ctx context.Context, productID int, reservationID int) error {
+	args := m.Called(ctx, productID, reservationID)
+	return args.Error(0)
+}
+
+func (m *MockDBOperations) ReleaseReservation(ctx context.Context, productID int, reservationID int) error {
+	args := m.Called(ctx, productID, reservationID)
+	return args.Error(0)
+}
+
+func (m *MockDBOperations) ExpireReservations(ctx context.Context, now time.Time, limit int) (int64, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDBOperations) GetPriceHistoryCount(ctx context.Context, productID int) (int, error) {
+	args := m.Called(ctx, productID)
 	return args.Int(0), args.Error(1)
 }
+
+func (m *MockDBOperations) GetPriceHistory(ctx context.Context, productID int, offset int, pageSize int) ([]*models.PriceChange, error) {
+	args := m.Called(ctx, productID, offset, pageSize)
+	changes, _ := args.Get(0).([]*models.PriceChange)
+	return changes, args.Error(1)
+}
+
+func (m *MockDBOperations) GetPriceAt(ctx context.Context, productID int, at time.Time) (*models.EffectivePrice, error) {
+	args := m.Called(ctx, productID, at)
+	price, _ := args.Get(0).(*models.EffectivePrice)
+	return price, args.Error(1)
+}
+
+func (m *MockDBOperations) ListScheduledPrices(ctx context.Context, productID int) ([]*models.ScheduledPrice, error) {
+	args := m.Called(ctx, productID)
+	schedules, _ := args.Get(0).([]*models.ScheduledPrice)
+	return schedules, args.Error(1)
+}
+
+func (m *MockDBOperations) GetScheduledPrice(ctx context.Context, productID int, scheduleID int) (*models.ScheduledPrice, error) {
+	args := m.Called(ctx, productID, scheduleID)
+	schedule, _ := args.Get(0).(*models.ScheduledPrice)
+	return schedule, args.Error(1)
+}
+
+func (m *MockDBOperations) CreateScheduledPrice(ctx context.Context, productID int, request *models.ScheduledPriceRequest) (*models.ScheduledPrice, error) {
+	args := m.Called(ctx, productID, request)
+	schedule, _ := args.Get(0).(*models.ScheduledPrice)
+	return schedule, args.Error(1)
+}
+
+func (m *MockDBOperations) CancelScheduledPrice(ctx context.Context, productID int, scheduleID int) error {
+	args := m.Called(ctx, productID, scheduleID)
+	return args.Error(0)
+}
+
+func (m *MockDBOperations) RunPriceSchedule(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	args := m.Called(ctx, now, limit)
+	productIDs, _ := args.Get(0).([]int)
+	return productIDs, args.Error(1)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"ProductService/models"
+	"context"
 	"github.com/stretchr/testify/mock"
 	"time"
 )
@@ -11,8 +12,8 @@ type MockCacheInterface struct {
 	mock.Mock
 }
 
-func (m *MockCacheInterface) GetProductByID(id string) (*models.Product, error) {
-	args := m.Called(id)
+func (m *MockCacheInterface) GetProductByID(ctx context.Context, id string) (*models.Product, error) {
+	args := m.Called(ctx, id)
 	product, ok := args.Get(0).(*models.Product)
 	if !ok {
 		return nil, args.Error(1)
@@ -20,12 +21,59 @@ func (m *MockCacheInterface) GetProductByID(id string) (*models.Product, error)
 	return product, args.Error(1)
 }
 
-func (m *MockCacheInterface) SetProductByID(id string, product *models.Product, ttl time.Duration) error {
-	args := m.Called(id, product, ttl)
+func (m *MockCacheInterface) SetProductByID(ctx context.Context, id string, product *models.Product, ttl time.Duration) error {
+	args := m.Called(ctx, id, product, ttl)
 	return args.Error(0)
 }
 
-func (m *MockCacheInterface) DeleteProductFromCache(id string) error {
-	args := m.Called(id)
+func (m *MockCacheInterface) DeleteProductFromCache(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCacheInterface) DeleteProductsFromCache(ctx context.Context, ids []string) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (m *MockCacheInterface) ReserveIdempotencyKey(ctx context.Context, key string, record *models.IdempotencyRecord, ttl time.Duration) (*models.IdempotencyRecord, bool, error) {
+	args := m.Called(ctx, key, record, ttl)
+	existing, _ := args.Get(0).(*models.IdempotencyRecord)
+	return existing, args.Bool(1), args.Error(2)
+}
+
+func (m *MockCacheInterface) SaveIdempotencyKey(ctx context.Context, key string, record *models.IdempotencyRecord, ttl time.Duration) error {
+	args := m.Called(ctx, key, record, ttl)
+	return args.Error(0)
+}
+
+func (m *MockCacheInterface) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockCacheInterface) GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error) {
+	args := m.Called(ctx)
+	tree, _ := args.Get(0).([]*models.CategoryNode)
+	return tree, args.Error(1)
+}
+
+func (m *MockCacheInterface) SetCategoryTree(ctx context.Context, tree []*models.CategoryNode, ttl time.Duration) error {
+	args := m.Called(ctx, tree, ttl)
+	return args.Error(0)
+}
+
+func (m *MockCacheInterface) DeleteCategoryTree(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockCacheInterface) GetExchangeRate(ctx context.Context, from string, to string) (string, error) {
+	args := m.Called(ctx, from, to)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCacheInterface) SetExchangeRate(ctx context.Context, from string, to string, rate string, ttl time.Duration) error {
+	args := m.Called(ctx, from, to, rate, ttl)
 	return args.Error(0)
 }
@@ -1,32 +1,65 @@
 package db
 
 import (
+	"ProductService/metrics"
 	"ProductService/models"
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
-	"log"
+	"fmt"
+	"github.com/lib/pq"
+	"log/slog"
+	"slices"
+	"time"
 )
 
+// productColumns is the column list every product query selects, in the order scanProduct reads them
+const productColumns = "id, sku, name, description, price, currency, status, attributes, tags, version, created_at, updated_at, deleted_at"
+
+// ErrVersionMismatch is returned by a conditional write when the product exists but its version has moved on
+var ErrVersionMismatch = errors.New("product version does not match")
+
+// ErrNotDeleted is returned by RestoreProduct when the product exists but was never deleted
+var ErrNotDeleted = errors.New("product is not deleted")
+
+// ProductSKUConstraint is the unique index keeping the SKUs of products that are not deleted distinct
+const ProductSKUConstraint = "products_sku_key"
+
+// UniqueViolation reports whether err comes from a unique constraint and returns the constraint's name
+func UniqueViolation(err error) (string, bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return pqErr.Constraint, true
+	}
+	return "", false
+}
+
 type PGConnector struct {
-	Conn *sql.DB
+	Conn         *sql.DB
+	QueryTimeout time.Duration
 }
 
-func NewPGConnector(conn *sql.DB) DBOperations {
-	return &PGConnector{Conn: conn}
+func NewPGConnector(conn *sql.DB, queryTimeout time.Duration) DBOperations {
+	return &PGConnector{Conn: conn, QueryTimeout: queryTimeout}
 }
 
 // Should contain all the implemented functions
 
 func (d *PGConnector) Try() {
-	log.Println("Successful interface")
+	slog.Info("Successful interface")
 }
 
-func (d *PGConnector) GetProductByID(id int) (*models.Product, error) {
-	log.Println("Entering GetProductByID DB Function")
+func (d *PGConnector) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	slog.DebugContext(ctx, "Entering GetProductByID DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
 	var product models.Product
 
-	query := "SELECT id, name, price FROM products WHERE id = $1"
-	err := d.Conn.QueryRow(query, id).Scan(&product.ID, &product.Name, &product.Price)
+	query := "SELECT " + productWithVariantsColumns + " FROM products WHERE id = $1 AND deleted_at IS NULL"
+	start := time.Now()
+	err := scanProductWithVariants(d.Conn.QueryRowContext(ctx, query, id), &product)
+	metrics.ObserveQuery("GetProductByID", start, queryError(err))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			// No product found with given ID
@@ -35,25 +68,81 @@ func (d *PGConnector) GetProductByID(id int) (*models.Product, error) {
 		// Some other database error
 		return nil, err
 	}
-	log.Println("Product Found in DB")
-	log.Println("Exiting GetProductByID DB Function")
+	slog.DebugContext(ctx, "Product Found in DB")
+	slog.DebugContext(ctx, "Exiting GetProductByID DB Function")
 	return &product, nil
 }
 
-func (d *PGConnector) GetAllProducts(offset int, pageSize int) ([]*models.Product, error) {
-	log.Println("Entering GetAllProducts DB Function")
-	query := "SELECT id, name, price FROM products OFFSET $1 LIMIT $2"
-	rows, err := d.Conn.Query(query, offset, pageSize)
+func (d *PGConnector) GetAllProducts(ctx context.Context, filter models.ProductFilter, offset int, pageSize int) ([]*models.Product, error) {
+	slog.DebugContext(ctx, "Entering GetAllProducts DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	where, args := buildProductWhere(filter)
+	order, err := buildProductOrder(filter)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	args = append(args, offset, pageSize)
+	query := fmt.Sprintf("SELECT %s FROM products%s%s OFFSET $%d LIMIT $%d", productColumns, where, order, len(args)-1, len(args))
+	products, err := d.queryProducts(ctx, "GetAllProducts", query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "Exiting GetAllProducts DB Function")
+	return products, nil
+}
+
+// GetProductsAfter returns up to limit products that sort after the cursor, a nil cursor starts from the first product
+func (d *PGConnector) GetProductsAfter(ctx context.Context, filter models.ProductFilter, cursor *models.ProductCursor, limit int) ([]*models.Product, error) {
+	slog.DebugContext(ctx, "Entering GetProductsAfter DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	where, args := buildProductWhere(filter)
+	order, err := buildProductOrder(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor != nil {
+		var condition string
+		condition, args, err = buildCursorCondition(filter.Sort, cursor, args)
+		if err != nil {
+			return nil, err
+		}
+		if where == "" {
+			where = " WHERE " + condition
+		} else {
+			where += " AND " + condition
+		}
+	}
 
-	var products []*models.Product
+	args = append(args, limit)
+	query := fmt.Sprintf("SELECT %s FROM products%s%s LIMIT $%d", productColumns, where, order, len(args))
+	products, err := d.queryProducts(ctx, "GetProductsAfter", query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "Exiting GetProductsAfter DB Function")
+	return products, nil
+}
+
+func (d *PGConnector) queryProducts(ctx context.Context, operation string, query string, args ...interface{}) (products []*models.Product, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery(operation, start, err)
+	}()
+
+	rows, err := d.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var product models.Product
-		err := rows.Scan(&product.ID, &product.Name, &product.Price)
+		err := scanProduct(rows, &product)
 		if err != nil {
 			return nil, err
 		}
@@ -64,73 +153,398 @@ func (d *PGConnector) GetAllProducts(offset int, pageSize int) ([]*models.Produc
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-
-	log.Println("Exiting GetAllProducts DB Function")
 	return products, nil
 }
 
-func (d *PGConnector) CreateProduct(product *models.CreateProductRequest) (int, error) {
-	log.Println("Entering CreateProduct DB Function")
-	query := "INSERT INTO products (name, price) VALUES ($1, $2) RETURNING id"
-	var id int
-	err := d.Conn.QueryRow(query, product.Name, product.Price).Scan(&id)
+// scanProduct reads a row selected with productColumns, it accepts both *sql.Row and *sql.Rows
+func scanProduct(row interface{ Scan(dest ...any) error }, product *models.Product) error {
+	err := row.Scan(productFields(product)...)
+	if err == nil {
+		normalizeTags(product)
+	}
+	return err
+}
+
+// scanProductWithVariants reads a row selected with productWithVariantsColumns
+func scanProductWithVariants(row interface{ Scan(dest ...any) error }, product *models.Product) error {
+	var variants []byte
+	if err := row.Scan(append(productFields(product), &variants)...); err != nil {
+		return err
+	}
+	normalizeTags(product)
+	return json.Unmarshal(variants, &product.Variants)
+}
+
+func productFields(product *models.Product) []any {
+	return []any{&product.ID, &product.SKU, &product.Name, &product.Description, &product.Price, &product.Currency,
+		&product.Status, &product.Attributes, (*pq.StringArray)(&product.Tags), &product.Version, &product.CreatedAt,
+		&product.UpdatedAt, &product.DeletedAt}
+}
+
+// normalizeTags replaces nil tags, pq scans an empty array as nil and clients get [] like they get {} for no attributes
+func normalizeTags(product *models.Product) {
+	if product.Tags == nil {
+		product.Tags = []string{}
+	}
+}
+
+// tagsArray converts tags into a postgres array argument, nil is written as an empty array
+func tagsArray(tags []string) pq.StringArray {
+	if tags == nil {
+		return pq.StringArray{}
+	}
+	return tags
+}
+
+// versionsArray binds the versions a conditional write may match, an empty array makes the write unconditional
+func versionsArray(versions []int) pq.Int64Array {
+	array := pq.Int64Array{}
+	for _, version := range versions {
+		array = append(array, int64(version))
+	}
+	return array
+}
+
+// matchVersions is the versions list of a write conditional on a single version, 0 makes it unconditional
+func matchVersions(version int) []int {
+	if version == 0 {
+		return nil
+	}
+	return []int{version}
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx, so single writes and batches run the same queries
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// CreateProduct inserts the product and returns the stored row, including the id, version and any other column defaults
+func (d *PGConnector) CreateProduct(ctx context.Context, product *models.CreateProductRequest) (*models.Product, error) {
+	slog.DebugContext(ctx, "Entering CreateProduct DB Function")
+	created, err := d.createProduct(ctx, d.Conn, product)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	log.Println("Exiting CreateProduct DB Function")
-	return id, nil
+	slog.DebugContext(ctx, "Exiting CreateProduct DB Function")
+	return created, nil
 }
 
-func (d *PGConnector) UpdateProduct(product *models.Product) error {
-	log.Println("Entering UpdateProduct DB Function")
-	query := "UPDATE products SET name = $1, price = $2 WHERE id = $3"
-	result, err := d.Conn.Exec(query, product.Name, product.Price, product.ID)
+func (d *PGConnector) createProduct(ctx context.Context, q querier, product *models.CreateProductRequest) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	query := "INSERT INTO products (sku, name, description, price, currency, status, attributes, tags) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING " + productColumns
+	var created models.Product
+	start := time.Now()
+	row := q.QueryRowContext(ctx, query, product.SKU, product.Name, product.Description, product.Price, product.Currency, product.Status,
+		product.Attributes, tagsArray(product.Tags))
+	err := scanProduct(row, &created)
+	metrics.ObserveQuery("CreateProduct", start, err)
 	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateProduct overwrites every writable column and bumps the version. A non empty versions list makes the
+// write conditional on the stored version being one of them, on success product holds the stored row with its new version.
+// A changed price or currency is recorded in the price history in the same transaction.
+func (d *PGConnector) UpdateProduct(ctx context.Context, product *models.Product, versions []int) error {
+	slog.DebugContext(ctx, "Entering UpdateProduct DB Function")
+	if err := d.updateProduct(ctx, d.Conn, product, versions); err != nil {
 		return err
 	}
+	slog.DebugContext(ctx, "Exiting UpdateProduct DB Function")
+	return nil
+}
 
-	rowsAffected, err := result.RowsAffected()
+func (d *PGConnector) updateProduct(ctx context.Context, q querier, product *models.Product, versions []int) error {
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	query := "UPDATE products SET sku = $1, name = $2, description = $3, price = $4, currency = $5, status = $6, attributes = $7, tags = $8, " +
+		"version = version + 1, updated_at = now() WHERE id = $9 AND deleted_at IS NULL " +
+		"AND (cardinality($10::bigint[]) = 0 OR version = ANY($10::bigint[])) RETURNING " + productWithVariantsColumns
+	id := product.ID
+	return d.inTx(ctx, q, func(tx querier) error {
+		old, err := lockPrice(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		row := tx.QueryRowContext(ctx, query, product.SKU, product.Name, product.Description, product.Price, product.Currency, product.Status,
+			product.Attributes, tagsArray(product.Tags), id, versionsArray(versions))
+		err = scanProductWithVariants(row, product)
+		metrics.ObserveQuery("UpdateProduct", start, queryError(err))
+		if errors.Is(err, sql.ErrNoRows) {
+			return d.missedWrite(ctx, tx, id, versions)
+		}
+		if err != nil {
+			return err
+		}
+		return recordPriceChange(ctx, tx, old, product)
+	})
+}
+
+// PatchProduct writes only the changed columns and bumps the version, a non zero version makes the
+// write conditional on the stored version. It returns the stored row. Like UpdateProduct it records
+// a changed price or currency in the price history.
+func (d *PGConnector) PatchProduct(ctx context.Context, id int, version int, changes map[string]interface{}) (*models.Product, error) {
+	slog.DebugContext(ctx, "Entering PatchProduct DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	set, args, err := buildProductSet(changes)
 	if err != nil {
+		return nil, err
+	}
+	args = append(args, id, version)
+	query := fmt.Sprintf("UPDATE products SET %s, version = version + 1, updated_at = now() WHERE id = $%d AND deleted_at IS NULL AND ($%d = 0 OR version = $%d) RETURNING %s",
+		set, len(args)-1, len(args), len(args), productWithVariantsColumns)
+	var product models.Product
+	err = d.inTx(ctx, d.Conn, func(tx querier) error {
+		old, err := lockPrice(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		err = scanProductWithVariants(tx.QueryRowContext(ctx, query, args...), &product)
+		metrics.ObserveQuery("PatchProduct", start, queryError(err))
+		if errors.Is(err, sql.ErrNoRows) {
+			return d.missedWrite(ctx, tx, id, matchVersions(version))
+		}
+		if err != nil {
+			return err
+		}
+		return recordPriceChange(ctx, tx, old, &product)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "Exiting PatchProduct DB Function")
+	return &product, nil
+}
+
+// DeleteProduct soft deletes the product by setting deleted_at and bumping the version, the row is only removed
+// by PurgeDeletedProducts. A non empty versions list makes the delete conditional on the stored version being one of them.
+// The product's variants are deleted along with it and stamped with the same deleted_at.
+func (d *PGConnector) DeleteProduct(ctx context.Context, id int, versions []int) error {
+	slog.DebugContext(ctx, "Entering DeleteProduct DB Function")
+	if err := d.deleteProduct(ctx, d.Conn, id, versions); err != nil {
 		return err
 	}
+	slog.DebugContext(ctx, "Exiting DeleteProduct DB Function")
+	return nil
+}
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows // standard error if product not found
+func (d *PGConnector) deleteProduct(ctx context.Context, q querier, id int, versions []int) error {
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	// one statement deletes the product and its variants, so it also works outside a transaction
+	query := "WITH deleted AS (UPDATE products SET deleted_at = now(), updated_at = now(), version = version + 1 " +
+		"WHERE id = $1 AND deleted_at IS NULL AND (cardinality($2::bigint[]) = 0 OR version = ANY($2::bigint[])) RETURNING id, deleted_at), " +
+		"variants AS (UPDATE product_variants v SET deleted_at = deleted.deleted_at FROM deleted " +
+		"WHERE v.product_id = deleted.id AND v.deleted_at IS NULL) " +
+		"SELECT COUNT(*) FROM deleted"
+	var deleted int
+	start := time.Now()
+	err := q.QueryRowContext(ctx, query, id, versionsArray(versions)).Scan(&deleted)
+	metrics.ObserveQuery("DeleteProduct", start, err)
+	if err != nil {
+		return err
 	}
 
-	log.Println("Exiting UpdateProduct DB Function")
+	if deleted == 0 {
+		return d.missedWrite(ctx, q, id, versions)
+	}
 	return nil
 }
 
-func (d *PGConnector) DeleteProduct(id int) error {
-	log.Println("Entering DeleteProduct DB Function")
-	query := "DELETE FROM products WHERE id = $1"
-	result, err := d.Conn.Exec(query, id)
+// missedWrite explains why a write matched no rows: the product is gone or deleted (sql.ErrNoRows)
+// or, for a conditional write, it exists with another version (ErrVersionMismatch)
+func (d *PGConnector) missedWrite(ctx context.Context, q querier, id int, versions []int) error {
+	if len(versions) == 0 {
+		return sql.ErrNoRows
+	}
+	exists, err := productExists(ctx, q, id)
 	if err != nil {
 		return err
 	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+	return ErrVersionMismatch
+}
+
+// productExists reports whether the product exists and isn't deleted
+func productExists(ctx context.Context, q querier, id int) (bool, error) {
+	var exists bool
+	start := time.Now()
+	err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)", id).Scan(&exists)
+	metrics.ObserveQuery("ProductExists", start, err)
+	return exists, err
+}
+
+// RestoreProduct clears deleted_at of a soft deleted product and bumps the version, a non empty versions list
+// makes the restore conditional on the stored version being one of them. The variants deleted along with the product are
+// restored too. It returns sql.ErrNoRows when the product doesn't exist (or was already purged) and
+// ErrNotDeleted when it isn't deleted.
+func (d *PGConnector) RestoreProduct(ctx context.Context, id int, versions []int) (product *models.Product, err error) {
+	slog.DebugContext(ctx, "Entering RestoreProduct DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery("RestoreProduct", start, queryError(err))
+	}()
 
-	rowsAffected, err := result.RowsAffected()
+	tx, err := d.Conn.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows // Product not found
+	var deletedAt *time.Time
+	var stored int
+	err = tx.QueryRowContext(ctx, "SELECT deleted_at, version FROM products WHERE id = $1 FOR UPDATE", id).Scan(&deletedAt, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if deletedAt == nil {
+		return nil, ErrNotDeleted
+	}
+	if len(versions) > 0 && !slices.Contains(versions, stored) {
+		return nil, ErrVersionMismatch
 	}
 
-	log.Println("Exiting DeleteProduct DB Function")
-	return nil
+	// variants deleted on their own before the product are gone for good, only the ones deleted with it come back
+	_, err = tx.ExecContext(ctx, "UPDATE product_variants SET deleted_at = NULL WHERE product_id = $1 AND deleted_at = $2", id, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	query := "UPDATE products SET deleted_at = NULL, updated_at = now(), version = version + 1 WHERE id = $1 RETURNING " + productWithVariantsColumns
+	var restored models.Product
+	if err = scanProductWithVariants(tx.QueryRowContext(ctx, query, id), &restored); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "Exiting RestoreProduct DB Function")
+	return &restored, nil
 }
 
-func (d *PGConnector) GetProductCount() (int, error) {
-	log.Println("Entering GetProductCount DB Function")
-	query := "SELECT COUNT(*) FROM products"
+// PurgeDeletedProducts permanently removes up to limit products soft deleted before the given time
+// and returns how many were removed. Callers repeat it until fewer than limit rows are removed,
+// so a large backlog is purged in short statements instead of one long one.
+// Their variants are removed by the product_variants foreign key cascade.
+func (d *PGConnector) PurgeDeletedProducts(ctx context.Context, before time.Time, limit int) (int64, error) {
+	slog.DebugContext(ctx, "Entering PurgeDeletedProducts DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	query := "DELETE FROM products WHERE id IN (SELECT id FROM products WHERE deleted_at < $1 ORDER BY deleted_at LIMIT $2)"
+	start := time.Now()
+	result, err := d.Conn.ExecContext(ctx, query, before, limit)
+	metrics.ObserveQuery("PurgeDeletedProducts", start, err)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	slog.DebugContext(ctx, "Exiting PurgeDeletedProducts DB Function")
+	return purged, nil
+}
+
+// ExecBatch runs the operations in order and returns one outcome per operation it attempted.
+// In atomic mode they share a transaction that is rolled back at the first failed operation,
+// so the last outcome holds the error and nothing is written. Otherwise every operation
+// commits on its own and all of them are attempted. The returned error is only set when
+// the transaction itself could not be started or committed.
+func (d *PGConnector) ExecBatch(ctx context.Context, operations []models.BatchOperation, atomic bool) ([]BatchOutcome, error) {
+	slog.DebugContext(ctx, "Entering ExecBatch DB Function", "operations", len(operations), "atomic", atomic)
+	outcomes := make([]BatchOutcome, 0, len(operations))
+	if !atomic {
+		for _, operation := range operations {
+			product, err := d.execBatchOperation(ctx, d.Conn, operation)
+			outcomes = append(outcomes, BatchOutcome{Product: product, Err: err})
+		}
+		slog.DebugContext(ctx, "Exiting ExecBatch DB Function")
+		return outcomes, nil
+	}
+
+	tx, err := d.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, operation := range operations {
+		product, err := d.execBatchOperation(ctx, tx, operation)
+		outcomes = append(outcomes, BatchOutcome{Product: product, Err: err})
+		if err != nil {
+			tx.Rollback()
+			return outcomes, nil
+		}
+	}
+
+	start := time.Now()
+	err = tx.Commit()
+	metrics.ObserveQuery("CommitBatch", start, err)
+	if err != nil {
+		return outcomes, err
+	}
+	slog.DebugContext(ctx, "Exiting ExecBatch DB Function")
+	return outcomes, nil
+}
+
+func (d *PGConnector) execBatchOperation(ctx context.Context, q querier, operation models.BatchOperation) (*models.Product, error) {
+	switch operation.Op {
+	case models.BatchCreate:
+		return d.createProduct(ctx, q, operation.CreateRequest())
+	case models.BatchUpdate:
+		product := &models.Product{
+			ID:          operation.ID,
+			SKU:         operation.SKU,
+			Name:        operation.Name,
+			Description: operation.Description,
+			Price:       operation.Price,
+			Currency:    operation.Currency,
+			Status:      operation.Status,
+			Attributes:  operation.Attributes,
+			Tags:        operation.Tags,
+		}
+		if err := d.updateProduct(ctx, q, product, matchVersions(operation.Version)); err != nil {
+			return nil, err
+		}
+		return product, nil
+	case models.BatchDelete:
+		return nil, d.deleteProduct(ctx, q, operation.ID, matchVersions(operation.Version))
+	}
+	return nil, fmt.Errorf("unknown batch operation %q", operation.Op)
+}
+
+func (d *PGConnector) GetProductCount(ctx context.Context, filter models.ProductFilter) (int, error) {
+	slog.DebugContext(ctx, "Entering GetProductCount DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	where, args := buildProductWhere(filter)
+	query := "SELECT COUNT(*) FROM products" + where
 	var count int
-	err := d.Conn.QueryRow(query).Scan(&count)
+	start := time.Now()
+	err := d.Conn.QueryRowContext(ctx, query, args...).Scan(&count)
+	metrics.ObserveQuery("GetProductCount", start, err)
 	if err != nil {
 		return 0, err
 	}
-	log.Println("Entering GetProductCount DB Function")
+	slog.DebugContext(ctx, "Entering GetProductCount DB Function")
 	return count, nil
 }
+
+// queryError drops sql.ErrNoRows, a missing row is a normal result and not a failed query
+func queryError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
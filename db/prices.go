@@ -0,0 +1,146 @@
+package db
+
+import (
+	"ProductService/metrics"
+	"ProductService/models"
+	"ProductService/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// priceChangeColumns is the column list every price history query selects, in the order scanPriceChange reads them
+const priceChangeColumns = "id, product_id, old_price, new_price, old_currency, new_currency, changed_at, COALESCE(actor, '')"
+
+// ErrNoPriceAt is returned by GetPriceAt for a time before the product was created
+var ErrNoPriceAt = errors.New("product did not exist at that time")
+
+func scanPriceChange(row interface{ Scan(dest ...any) error }, change *models.PriceChange) error {
+	return row.Scan(&change.ID, &change.ProductID, &change.OldPrice, &change.NewPrice, &change.OldCurrency, &change.NewCurrency,
+		&change.ChangedAt, &change.Actor)
+}
+
+// inTx runs fn in q when it already is a transaction, otherwise in a transaction of its own,
+// so writes made of several statements are atomic both on their own and inside a batch
+func (d *PGConnector) inTx(ctx context.Context, q querier, fn func(tx querier) error) error {
+	if tx, ok := q.(*sql.Tx); ok {
+		return fn(tx)
+	}
+	tx, err := d.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockPrice locks the product row until the transaction ends and returns its price and currency,
+// sql.ErrNoRows when the product doesn't exist or is deleted
+func lockPrice(ctx context.Context, tx querier, id int) (models.EffectivePrice, error) {
+	var price models.EffectivePrice
+	start := time.Now()
+	err := tx.QueryRowContext(ctx, "SELECT price, currency FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).
+		Scan(&price.Price, &price.Currency)
+	metrics.ObserveQuery("LockPrice", start, queryError(err))
+	return price, err
+}
+
+// recordPriceChange adds a price history row when the product's price or currency differs from old,
+// the actor is taken from ctx
+func recordPriceChange(ctx context.Context, tx querier, old models.EffectivePrice, product *models.Product) error {
+	if old.Price == product.Price && old.Currency == product.Currency {
+		return nil
+	}
+	var actor *string
+	if a := utils.Actor(ctx); a != "" {
+		actor = &a
+	}
+	query := "INSERT INTO price_history (product_id, old_price, new_price, old_currency, new_currency, actor) VALUES ($1, $2, $3, $4, $5, $6)"
+	start := time.Now()
+	_, err := tx.ExecContext(ctx, query, product.ID, old.Price, product.Price, old.Currency, product.Currency, actor)
+	metrics.ObserveQuery("RecordPriceChange", start, err)
+	return err
+}
+
+// GetPriceHistoryCount returns how many price changes the product has,
+// sql.ErrNoRows when the product doesn't exist or is deleted
+func (d *PGConnector) GetPriceHistoryCount(ctx context.Context, productID int) (int, error) {
+	slog.DebugContext(ctx, "Entering GetPriceHistoryCount DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	query := "SELECT (SELECT COUNT(*) FROM price_history WHERE product_id = p.id) FROM products p WHERE p.id = $1 AND p.deleted_at IS NULL"
+	var count int
+	start := time.Now()
+	err := d.Conn.QueryRowContext(ctx, query, productID).Scan(&count)
+	metrics.ObserveQuery("GetPriceHistoryCount", start, queryError(err))
+	if err != nil {
+		return 0, err
+	}
+	slog.DebugContext(ctx, "Exiting GetPriceHistoryCount DB Function")
+	return count, nil
+}
+
+// GetPriceHistory returns a page of the product's price changes, newest first
+func (d *PGConnector) GetPriceHistory(ctx context.Context, productID int, offset int, pageSize int) (changes []*models.PriceChange, err error) {
+	slog.DebugContext(ctx, "Entering GetPriceHistory DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery("GetPriceHistory", start, err)
+	}()
+
+	query := "SELECT " + priceChangeColumns + " FROM price_history WHERE product_id = $1 ORDER BY changed_at DESC, id DESC OFFSET $2 LIMIT $3"
+	rows, err := d.Conn.QueryContext(ctx, query, productID, offset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var change models.PriceChange
+		if err = scanPriceChange(rows, &change); err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting GetPriceHistory DB Function")
+	return changes, nil
+}
+
+// GetPriceAt returns the price the product had at the given time: the new price of the last change before it,
+// else the old price of the first change after it, else the current price. It returns sql.ErrNoRows when
+// the product doesn't exist or is deleted and ErrNoPriceAt when it was created after that time.
+func (d *PGConnector) GetPriceAt(ctx context.Context, productID int, at time.Time) (*models.EffectivePrice, error) {
+	slog.DebugContext(ctx, "Entering GetPriceAt DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	query := "SELECT COALESCE(earlier.new_price, later.old_price, p.price), COALESCE(earlier.new_currency, later.old_currency, p.currency), " +
+		"p.created_at <= $2 FROM products p " +
+		"LEFT JOIN LATERAL (SELECT new_price, new_currency FROM price_history WHERE product_id = p.id AND changed_at <= $2 " +
+		"ORDER BY changed_at DESC, id DESC LIMIT 1) earlier ON true " +
+		"LEFT JOIN LATERAL (SELECT old_price, old_currency FROM price_history WHERE product_id = p.id AND changed_at > $2 " +
+		"ORDER BY changed_at, id LIMIT 1) later ON true " +
+		"WHERE p.id = $1 AND p.deleted_at IS NULL"
+	var price models.EffectivePrice
+	var existed bool
+	start := time.Now()
+	err := d.Conn.QueryRowContext(ctx, query, productID, at).Scan(&price.Price, &price.Currency, &existed)
+	metrics.ObserveQuery("GetPriceAt", start, queryError(err))
+	if err != nil {
+		return nil, err
+	}
+	if !existed {
+		return nil, ErrNoPriceAt
+	}
+	slog.DebugContext(ctx, "Exiting GetPriceAt DB Function")
+	return &price, nil
+}
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"ProductService/models"
+	"encoding/json"
+	"fmt"
+	"github.com/lib/pq"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// productSortColumns whitelists the sort fields accepted on the product list.
+// Only these column names are ever written into the ORDER BY clause.
+var productSortColumns = map[string]string{
+	"id":    "id",
+	"name":  "name",
+	"price": "price",
+}
+
+// ParseProductSort validates a sort value such as "price" or "-price" and
+// returns the column to sort on and whether the order is descending.
+// An empty value sorts by id ascending.
+func ParseProductSort(sort string) (string, bool, error) {
+	if sort == "" {
+		return "id", false, nil
+	}
+	desc := strings.HasPrefix(sort, "-")
+	column, ok := productSortColumns[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		return "", false, fmt.Errorf("invalid sort field: %v", sort)
+	}
+	return column, desc, nil
+}
+
+// buildProductWhere converts the filter into a WHERE clause and its arguments.
+// Placeholders are numbered from 1 so callers can append their own after len(args).
+func buildProductWhere(filter models.ProductFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	if len(filter.CategoryIDs) > 0 {
+		args = append(args, pq.Array(filter.CategoryIDs))
+		conditions = append(conditions, fmt.Sprintf("id IN (SELECT product_id FROM product_categories WHERE category_id = ANY($%d))", len(args)))
+	}
+	// containment (@>) is what the GIN indexes on tags and attributes can serve
+	if len(filter.Tags) > 0 {
+		args = append(args, pq.StringArray(filter.Tags))
+		conditions = append(conditions, fmt.Sprintf("tags @> $%d", len(args)))
+	}
+	keys := make([]string, 0, len(filter.Attributes))
+	for key := range filter.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var matches []string
+		for _, match := range attributeMatches(key, filter.Attributes[key]) {
+			args = append(args, match)
+			matches = append(matches, fmt.Sprintf("attributes @> $%d::jsonb", len(args)))
+		}
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+	}
+	if filter.Query != "" {
+		args = append(args, "%"+escapeLike(filter.Query)+"%")
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+	if filter.MinPrice != nil {
+		args = append(args, *filter.MinPrice)
+		conditions = append(conditions, fmt.Sprintf("price >= $%d", len(args)))
+	}
+	if filter.MaxPrice != nil {
+		args = append(args, *filter.MaxPrice)
+		conditions = append(conditions, fmt.Sprintf("price <= $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// buildProductOrder returns the ORDER BY clause for the filter, id is always
+// added as a tie breaker so pages are deterministic
+func buildProductOrder(filter models.ProductFilter) (string, error) {
+	column, desc, err := ParseProductSort(filter.Sort)
+	if err != nil {
+		return "", err
+	}
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	if column == "id" {
+		return " ORDER BY id " + direction, nil
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction), nil
+}
+
+// buildCursorCondition returns the keyset condition selecting rows after the cursor,
+// (sort_key, id) > (key, id) for ascending order and < for descending
+func buildCursorCondition(sort string, cursor *models.ProductCursor, args []interface{}) (string, []interface{}, error) {
+	column, desc, err := ParseProductSort(sort)
+	if err != nil {
+		return "", args, err
+	}
+	operator := ">"
+	if desc {
+		operator = "<"
+	}
+
+	if column == "id" {
+		args = append(args, cursor.ID)
+		return fmt.Sprintf("id %s $%d", operator, len(args)), args, nil
+	}
+
+	key, err := cursorKey(column, cursor.Key)
+	if err != nil {
+		return "", args, err
+	}
+	args = append(args, key, cursor.ID)
+	return fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, operator, len(args)-1, len(args)), args, nil
+}
+
+// cursorKey checks the cursor key has the type of the sort column
+func cursorKey(column string, key interface{}) (interface{}, error) {
+	switch column {
+	case "name":
+		if v, ok := key.(string); ok {
+			return v, nil
+		}
+	case "price":
+		if v, ok := key.(float64); ok {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid cursor key for sort field %v", column)
+}
+
+// attributeMatches returns the JSON objects an attribute filter matches. Query values are always strings,
+// so "42" also matches the number 42 and "true" the boolean true.
+func attributeMatches(key string, value string) []string {
+	candidates := []interface{}{value}
+	if number, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(number, 0) && !math.IsNaN(number) {
+		candidates = append(candidates, number)
+	}
+	if value == "true" || value == "false" {
+		candidates = append(candidates, value == "true")
+	}
+
+	matches := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		// marshaling a string, a finite number or a bool can't fail
+		match, _ := json.Marshal(map[string]interface{}{key: candidate})
+		matches = append(matches, string(match))
+	}
+	return matches
+}
+
+// escapeLike escapes the LIKE wildcards so the search term is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
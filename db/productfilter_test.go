@@ -0,0 +1,217 @@
+package db
+
+import (
+	"ProductService/models"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func floatPtr(p float64) *float64 {
+	return &p
+}
+
+func TestBuildProductWhere(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter models.ProductFilter
+		where  string
+		args   []interface{}
+	}{
+		{
+			name:   "no filter",
+			filter: models.ProductFilter{},
+			where:  " WHERE deleted_at IS NULL",
+		},
+		{
+			name:   "include deleted without other filters",
+			filter: models.ProductFilter{IncludeDeleted: true},
+			where:  "",
+		},
+		{
+			name:   "search and price range",
+			filter: models.ProductFilter{Query: "50%_off", MinPrice: floatPtr(10), MaxPrice: floatPtr(99.5)},
+			where:  " WHERE deleted_at IS NULL AND name ILIKE $1 AND price >= $2 AND price <= $3",
+			args:   []interface{}{`%50\%\_off%`, 10.0, 99.5},
+		},
+		{
+			name:   "tags",
+			filter: models.ProductFilter{Tags: []string{"sale", "running"}},
+			where:  " WHERE deleted_at IS NULL AND tags @> $1",
+			args:   []interface{}{pq.StringArray{"sale", "running"}},
+		},
+		{
+			name:   "string attribute",
+			filter: models.ProductFilter{Attributes: map[string]string{"color": "red"}},
+			where:  " WHERE deleted_at IS NULL AND (attributes @> $1::jsonb)",
+			args:   []interface{}{`{"color":"red"}`},
+		},
+		{
+			name:   "numeric and boolean attributes, in key order",
+			filter: models.ProductFilter{Attributes: map[string]string{"waterproof": "true", "size": "42"}},
+			where: " WHERE deleted_at IS NULL AND (attributes @> $1::jsonb OR attributes @> $2::jsonb)" +
+				" AND (attributes @> $3::jsonb OR attributes @> $4::jsonb)",
+			args: []interface{}{`{"size":"42"}`, `{"size":42}`, `{"waterproof":"true"}`, `{"waterproof":true}`},
+		},
+		{
+			name: "every filter after the categories",
+			filter: models.ProductFilter{
+				CategoryIDs: []int{3, 7},
+				Tags:        []string{"sale"},
+				Attributes:  map[string]string{"size": "42"},
+				Query:       "shoe",
+				MinPrice:    floatPtr(20),
+				MaxPrice:    floatPtr(150),
+			},
+			where: " WHERE deleted_at IS NULL" +
+				" AND id IN (SELECT product_id FROM product_categories WHERE category_id = ANY($1))" +
+				" AND tags @> $2" +
+				" AND (attributes @> $3::jsonb OR attributes @> $4::jsonb)" +
+				" AND name ILIKE $5 AND price >= $6 AND price <= $7",
+			args: []interface{}{
+				pq.Array([]int{3, 7}), pq.StringArray{"sale"}, `{"size":"42"}`, `{"size":42}`, "%shoe%", 20.0, 150.0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := buildProductWhere(tt.filter)
+
+			assert.Equal(t, tt.where, where)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestAttributeMatches(t *testing.T) {
+	tests := []struct {
+		value   string
+		matches []string
+	}{
+		{"red", []string{`{"k":"red"}`}},
+		{"42", []string{`{"k":"42"}`, `{"k":42}`}},
+		{"-1.5", []string{`{"k":"-1.5"}`, `{"k":-1.5}`}},
+		{"false", []string{`{"k":"false"}`, `{"k":false}`}},
+		// not finite numbers have no JSON form, they are only matched as strings
+		{"Inf", []string{`{"k":"Inf"}`}},
+		{"NaN", []string{`{"k":"NaN"}`}},
+		{"True", []string{`{"k":"True"}`}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.matches, attributeMatches("k", tt.value), tt.value)
+	}
+}
+
+func TestParseProductSort(t *testing.T) {
+	tests := []struct {
+		sort   string
+		column string
+		desc   bool
+		err    bool
+	}{
+		{"", "id", false, false},
+		{"price", "price", false, false},
+		{"-price", "price", true, false},
+		{"-name", "name", true, false},
+		{"stock", "", false, true},
+		{"--price", "", false, true},
+		{"price; DROP TABLE products", "", false, true},
+	}
+
+	for _, tt := range tests {
+		column, desc, err := ParseProductSort(tt.sort)
+
+		assert.Equal(t, tt.column, column, tt.sort)
+		assert.Equal(t, tt.desc, desc, tt.sort)
+		assert.Equal(t, tt.err, err != nil, tt.sort)
+	}
+}
+
+func TestBuildProductOrder(t *testing.T) {
+	tests := []struct {
+		sort  string
+		order string
+	}{
+		{"", " ORDER BY id ASC"},
+		{"-id", " ORDER BY id DESC"},
+		{"price", " ORDER BY price ASC, id ASC"},
+		{"-price", " ORDER BY price DESC, id DESC"},
+	}
+
+	for _, tt := range tests {
+		order, err := buildProductOrder(models.ProductFilter{Sort: tt.sort})
+
+		assert.NoError(t, err, tt.sort)
+		assert.Equal(t, tt.order, order, tt.sort)
+	}
+
+	_, err := buildProductOrder(models.ProductFilter{Sort: "created_at"})
+	assert.EqualError(t, err, "invalid sort field: created_at")
+}
+
+func TestBuildCursorCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		sort      string
+		cursor    models.ProductCursor
+		condition string
+		args      []interface{}
+	}{
+		{"id ascending", "", models.ProductCursor{ID: 5}, "id > $2", []interface{}{"prior", 5}},
+		{"id descending", "-id", models.ProductCursor{Sort: "-id", ID: 5}, "id < $2", []interface{}{"prior", 5}},
+		{"price ascending", "price", models.ProductCursor{Sort: "price", Key: 89.5, ID: 2}, "(price, id) > ($2, $3)", []interface{}{"prior", 89.5, 2}},
+		{"price descending", "-price", models.ProductCursor{Sort: "-price", Key: 89.5, ID: 2}, "(price, id) < ($2, $3)", []interface{}{"prior", 89.5, 2}},
+		{"name descending", "-name", models.ProductCursor{Sort: "-name", Key: "Mouse", ID: 7}, "(name, id) < ($2, $3)", []interface{}{"prior", "Mouse", 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the filter's own arguments come first, the cursor placeholders continue after them
+			condition, args, err := buildCursorCondition(tt.sort, &tt.cursor, []interface{}{"prior"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.condition, condition)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestBuildCursorCondition_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		sort   string
+		cursor models.ProductCursor
+	}{
+		{"unknown sort field", "stock", models.ProductCursor{Sort: "stock", Key: 1.0, ID: 1}},
+		{"string key for price", "price", models.ProductCursor{Sort: "price", Key: "89.5", ID: 1}},
+		{"number key for name", "-name", models.ProductCursor{Sort: "-name", Key: 42.0, ID: 1}},
+		{"missing key", "price", models.ProductCursor{Sort: "price", ID: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, args, err := buildCursorCondition(tt.sort, &tt.cursor, nil)
+
+			assert.Error(t, err)
+			assert.Empty(t, args)
+		})
+	}
+}
+
+func TestCursorKey(t *testing.T) {
+	key, err := cursorKey("price", 12.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 12.5, key)
+
+	key, err = cursorKey("name", "Keyboard")
+	assert.NoError(t, err)
+	assert.Equal(t, "Keyboard", key)
+
+	// cursors are decoded from JSON, so an integer key would never arrive as an int
+	_, err = cursorKey("price", 12)
+	assert.EqualError(t, err, "invalid cursor key for sort field price")
+	_, err = cursorKey("id", 12.0)
+	assert.Error(t, err)
+}
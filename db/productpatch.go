@@ -0,0 +1,49 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// productPatchColumns whitelists the columns a partial update may touch.
+// Only these column names are ever written into the SET clause.
+var productPatchColumns = map[string]string{
+	"sku":         "sku",
+	"name":        "name",
+	"description": "description",
+	"price":       "price",
+	"currency":    "currency",
+	"status":      "status",
+	"attributes":  "attributes",
+	"tags":        "tags",
+}
+
+// buildProductSet converts the changed columns into a SET clause and its arguments.
+// Columns are sorted so the same change set always produces the same query.
+func buildProductSet(changes map[string]interface{}) (string, []interface{}, error) {
+	if len(changes) == 0 {
+		return "", nil, fmt.Errorf("no columns to update")
+	}
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		if _, ok := productPatchColumns[field]; !ok {
+			return "", nil, fmt.Errorf("column %v cannot be patched", field)
+		}
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	assignments := make([]string, 0, len(fields))
+	args := make([]interface{}, 0, len(fields))
+	for _, field := range fields {
+		value := changes[field]
+		if tags, ok := value.([]string); ok {
+			value = tagsArray(tags)
+		}
+		args = append(args, value)
+		assignments = append(assignments, fmt.Sprintf("%s = $%d", productPatchColumns[field], len(args)))
+	}
+	return strings.Join(assignments, ", "), args, nil
+}
@@ -1,80 +1,280 @@
 package db
 
 import (
+	"ProductService/metrics"
 	"ProductService/models"
 	"context"
 	"encoding/json"
 	"errors"
 	"github.com/go-redis/redis/v8"
-	"log"
+	"log/slog"
 	"time"
 )
 
-var ctx = context.Background()
-
 type Redis struct {
-	Con *redis.Client
+	Con     *redis.Client
+	Timeout time.Duration
 }
 
-func NewRedisConnector(conn *redis.Client) CacheInterface {
+func NewRedisConnector(conn *redis.Client, timeout time.Duration) CacheInterface {
 	return &Redis{
-		Con: conn,
+		Con:     conn,
+		Timeout: timeout,
 	}
 }
 
 // redis function to get product details by id
 // GetProductByID retrieves and unmarshals the product from Redis by ID
-func (r *Redis) GetProductByID(id string) (*models.Product, error) {
-	log.Println("Entering GetProductByID Cache")
+func (r *Redis) GetProductByID(ctx context.Context, id string) (*models.Product, error) {
+	slog.DebugContext(ctx, "Entering GetProductByID Cache")
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
 	result, err := r.Con.Get(ctx, id).Bytes()
 	if err == redis.Nil {
 		// Key does not exist
+		metrics.ObserveCache("GetProductByID", metrics.CacheMiss)
 		return nil, nil
 	} else if err != nil {
 		// Redis error
+		metrics.ObserveCache("GetProductByID", metrics.CacheError)
 		return nil, err
 	}
 
 	var product models.Product
 	if err := json.Unmarshal([]byte(result), &product); err != nil {
 		// Failed to unmarshal JSON
+		metrics.ObserveCache("GetProductByID", metrics.CacheError)
 		return nil, errors.New("failed to unmarshal product from redis")
 	}
-	log.Println("Product Found in Cache")
-	log.Println("Exiting GetProductByID Cache")
+	metrics.ObserveCache("GetProductByID", metrics.CacheHit)
+	slog.DebugContext(ctx, "Product Found in Cache")
+	slog.DebugContext(ctx, "Exiting GetProductByID Cache")
 	return &product, nil
 }
 
 // SetProductByID stores the product in Redis with a TTL
-func (r *Redis) SetProductByID(id string, product *models.Product, ttl time.Duration) error {
-	log.Println("Entering SetProductByID Cache")
+func (r *Redis) SetProductByID(ctx context.Context, id string, product *models.Product, ttl time.Duration) error {
+	slog.DebugContext(ctx, "Entering SetProductByID Cache")
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
 
 	// Marshal the product struct to JSON
 	productJSON, err := json.Marshal(product)
 	if err != nil {
-		log.Println("Failed to marshal product:", err)
+		slog.ErrorContext(ctx, "Failed to marshal product", "error", err)
 		return errors.New("failed to marshal product for redis")
 	}
 
 	// Store in Redis
 	err = r.Con.Set(ctx, id, productJSON, ttl).Err()
 	if err != nil {
-		log.Println("Failed to store product in Redis:", err)
+		slog.ErrorContext(ctx, "Failed to store product in Redis", "error", err)
+		metrics.ObserveCache("SetProductByID", metrics.CacheError)
 		return err
 	}
+	metrics.ObserveCache("SetProductByID", metrics.CacheOK)
 
-	log.Println("Product Stored Successfully in Cache")
-	log.Println("Exiting SetProductByID Cache")
+	slog.DebugContext(ctx, "Product Stored Successfully in Cache")
+	slog.DebugContext(ctx, "Exiting SetProductByID Cache")
 	return nil
 }
 
-func (r *Redis) DeleteProductFromCache(id string) error {
-	log.Println("Entering DeleteProductFromCache Cache")
-	log.Println("Deleting product from cache:", id)
+func (r *Redis) DeleteProductFromCache(ctx context.Context, id string) error {
+	slog.DebugContext(ctx, "Entering DeleteProductFromCache Cache")
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+	slog.DebugContext(ctx, "Deleting product from cache", "id", id)
 	err := r.Con.Del(ctx, id).Err()
 	if err != nil && err != redis.Nil {
+		metrics.ObserveCache("DeleteProductFromCache", metrics.CacheError)
+		return err
+	}
+	metrics.ObserveCache("DeleteProductFromCache", metrics.CacheOK)
+	slog.DebugContext(ctx, "Exiting DeleteProductFromCache Cache")
+	return nil
+}
+
+func (r *Redis) DeleteProductsFromCache(ctx context.Context, ids []string) error {
+	slog.DebugContext(ctx, "Entering DeleteProductsFromCache Cache")
+	if len(ids) == 0 {
+		return nil
+	}
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+	slog.DebugContext(ctx, "Deleting products from cache", "count", len(ids))
+	err := r.Con.Del(ctx, ids...).Err()
+	if err != nil && err != redis.Nil {
+		metrics.ObserveCache("DeleteProductsFromCache", metrics.CacheError)
+		return err
+	}
+	metrics.ObserveCache("DeleteProductsFromCache", metrics.CacheOK)
+	slog.DebugContext(ctx, "Exiting DeleteProductsFromCache Cache")
+	return nil
+}
+
+// idempotencyKeyPrefix keeps idempotency records apart from the product entries, which are keyed by bare id
+const idempotencyKeyPrefix = "idempotency:"
+
+func (r *Redis) ReserveIdempotencyKey(ctx context.Context, key string, record *models.IdempotencyRecord, ttl time.Duration) (*models.IdempotencyRecord, bool, error) {
+	slog.DebugContext(ctx, "Entering ReserveIdempotencyKey Cache")
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return nil, false, errors.New("failed to marshal idempotency record for redis")
+	}
+
+	reserved, err := r.Con.SetNX(ctx, idempotencyKeyPrefix+key, recordJSON, ttl).Result()
+	if err != nil {
+		metrics.ObserveCache("ReserveIdempotencyKey", metrics.CacheError)
+		return nil, false, err
+	}
+	if reserved {
+		metrics.ObserveCache("ReserveIdempotencyKey", metrics.CacheMiss)
+		slog.DebugContext(ctx, "Exiting ReserveIdempotencyKey Cache")
+		return nil, true, nil
+	}
+
+	result, err := r.Con.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+	if err == redis.Nil {
+		// the key expired between SETNX and GET
+		metrics.ObserveCache("ReserveIdempotencyKey", metrics.CacheError)
+		return nil, false, errors.New("idempotency key expired while being read")
+	} else if err != nil {
+		metrics.ObserveCache("ReserveIdempotencyKey", metrics.CacheError)
+		return nil, false, err
+	}
+
+	var existing models.IdempotencyRecord
+	if err := json.Unmarshal(result, &existing); err != nil {
+		metrics.ObserveCache("ReserveIdempotencyKey", metrics.CacheError)
+		return nil, false, errors.New("failed to unmarshal idempotency record from redis")
+	}
+	metrics.ObserveCache("ReserveIdempotencyKey", metrics.CacheHit)
+	slog.DebugContext(ctx, "Exiting ReserveIdempotencyKey Cache")
+	return &existing, false, nil
+}
+
+func (r *Redis) SaveIdempotencyKey(ctx context.Context, key string, record *models.IdempotencyRecord, ttl time.Duration) error {
+	slog.DebugContext(ctx, "Entering SaveIdempotencyKey Cache")
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return errors.New("failed to marshal idempotency record for redis")
+	}
+
+	if err := r.Con.Set(ctx, idempotencyKeyPrefix+key, recordJSON, ttl).Err(); err != nil {
+		metrics.ObserveCache("SaveIdempotencyKey", metrics.CacheError)
+		return err
+	}
+	metrics.ObserveCache("SaveIdempotencyKey", metrics.CacheOK)
+	slog.DebugContext(ctx, "Exiting SaveIdempotencyKey Cache")
+	return nil
+}
+
+func (r *Redis) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	slog.DebugContext(ctx, "Entering DeleteIdempotencyKey Cache")
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+	if err := r.Con.Del(ctx, idempotencyKeyPrefix+key).Err(); err != nil && err != redis.Nil {
+		metrics.ObserveCache("DeleteIdempotencyKey", metrics.CacheError)
+		return err
+	}
+	metrics.ObserveCache("DeleteIdempotencyKey", metrics.CacheOK)
+	slog.DebugContext(ctx, "Exiting DeleteIdempotencyKey Cache")
+	return nil
+}
+
+// categoryTreeKey holds the whole category tree, it is small and always read as a unit
+const categoryTreeKey = "categories:tree"
+
+func (r *Redis) GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error) {
+	slog.DebugContext(ctx, "Entering GetCategoryTree Cache")
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+	result, err := r.Con.Get(ctx, categoryTreeKey).Bytes()
+	if err == redis.Nil {
+		metrics.ObserveCache("GetCategoryTree", metrics.CacheMiss)
+		return nil, nil
+	} else if err != nil {
+		metrics.ObserveCache("GetCategoryTree", metrics.CacheError)
+		return nil, err
+	}
+
+	tree := []*models.CategoryNode{}
+	if err := json.Unmarshal(result, &tree); err != nil {
+		metrics.ObserveCache("GetCategoryTree", metrics.CacheError)
+		return nil, errors.New("failed to unmarshal category tree from redis")
+	}
+	metrics.ObserveCache("GetCategoryTree", metrics.CacheHit)
+	slog.DebugContext(ctx, "Exiting GetCategoryTree Cache")
+	return tree, nil
+}
+
+func (r *Redis) SetCategoryTree(ctx context.Context, tree []*models.CategoryNode, ttl time.Duration) error {
+	slog.DebugContext(ctx, "Entering SetCategoryTree Cache")
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	treeJSON, err := json.Marshal(tree)
+	if err != nil {
+		return errors.New("failed to marshal category tree for redis")
+	}
+
+	if err := r.Con.Set(ctx, categoryTreeKey, treeJSON, ttl).Err(); err != nil {
+		metrics.ObserveCache("SetCategoryTree", metrics.CacheError)
+		return err
+	}
+	metrics.ObserveCache("SetCategoryTree", metrics.CacheOK)
+	slog.DebugContext(ctx, "Exiting SetCategoryTree Cache")
+	return nil
+}
+
+func (r *Redis) DeleteCategoryTree(ctx context.Context) error {
+	slog.DebugContext(ctx, "Entering DeleteCategoryTree Cache")
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+	if err := r.Con.Del(ctx, categoryTreeKey).Err(); err != nil && err != redis.Nil {
+		metrics.ObserveCache("DeleteCategoryTree", metrics.CacheError)
+		return err
+	}
+	metrics.ObserveCache("DeleteCategoryTree", metrics.CacheOK)
+	slog.DebugContext(ctx, "Exiting DeleteCategoryTree Cache")
+	return nil
+}
+
+// exchangeRateKeyPrefix keeps the exchange rates apart from the product entries, a rate is keyed by rates:<from>:<to>
+const exchangeRateKeyPrefix = "rates:"
+
+func (r *Redis) GetExchangeRate(ctx context.Context, from string, to string) (string, error) {
+	slog.DebugContext(ctx, "Entering GetExchangeRate Cache")
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+	rate, err := r.Con.Get(ctx, exchangeRateKeyPrefix+from+":"+to).Result()
+	if err == redis.Nil {
+		metrics.ObserveCache("GetExchangeRate", metrics.CacheMiss)
+		return "", nil
+	} else if err != nil {
+		metrics.ObserveCache("GetExchangeRate", metrics.CacheError)
+		return "", err
+	}
+	metrics.ObserveCache("GetExchangeRate", metrics.CacheHit)
+	slog.DebugContext(ctx, "Exiting GetExchangeRate Cache")
+	return rate, nil
+}
+
+func (r *Redis) SetExchangeRate(ctx context.Context, from string, to string, rate string, ttl time.Duration) error {
+	slog.DebugContext(ctx, "Entering SetExchangeRate Cache")
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+	if err := r.Con.Set(ctx, exchangeRateKeyPrefix+from+":"+to, rate, ttl).Err(); err != nil {
+		metrics.ObserveCache("SetExchangeRate", metrics.CacheError)
 		return err
 	}
-	log.Println("Exiting DeleteProductFromCache Cache")
+	metrics.ObserveCache("SetExchangeRate", metrics.CacheOK)
+	slog.DebugContext(ctx, "Exiting SetExchangeRate Cache")
 	return nil
 }
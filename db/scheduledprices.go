@@ -0,0 +1,367 @@
+package db
+
+import (
+	"ProductService/metrics"
+	"ProductService/models"
+	"ProductService/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// scheduledPriceColumns is the column list every scheduled price query selects, in the order scanScheduledPrice reads them
+const scheduledPriceColumns = "id, product_id, price, COALESCE(currency, ''), effective_from, effective_to, status, " +
+	"previous_price, COALESCE(previous_currency, ''), COALESCE(actor, ''), created_at"
+
+// scheduleWindow is the span a scheduled price occupies. A permanent change only occupies its effective_from,
+// so it may follow a window but can't land inside one, where the window's revert would undo it.
+const scheduleWindow = "tstzrange(effective_from, COALESCE(effective_to, effective_from), CASE WHEN effective_to IS NULL THEN '[]' ELSE '[)' END)"
+
+// priceSchedulerLockID is the Postgres advisory lock held while the price scheduler runs,
+// only one instance applies and reverts scheduled prices at a time
+const priceSchedulerLockID int64 = 7_301_202_401
+
+// SchedulerActor is recorded in the price history for scheduled changes whose request had no X-Actor
+const SchedulerActor = "price-scheduler"
+
+var (
+	// ErrScheduleOverlap is returned when a scheduled price overlaps another pending or active one of the product
+	ErrScheduleOverlap = errors.New("scheduled price overlaps another one")
+	// ErrScheduledPriceNotFound is returned when the product exists but has no such scheduled price
+	ErrScheduledPriceNotFound = errors.New("scheduled price not found")
+	// ErrScheduleNotPending is returned when canceling a scheduled price that has already been applied or has ended
+	ErrScheduleNotPending = errors.New("scheduled price is no longer pending")
+)
+
+func scanScheduledPrice(row interface{ Scan(dest ...any) error }, schedule *models.ScheduledPrice) error {
+	return row.Scan(&schedule.ID, &schedule.ProductID, &schedule.Price, &schedule.Currency, &schedule.EffectiveFrom, &schedule.EffectiveTo,
+		&schedule.Status, &schedule.PreviousPrice, &schedule.PreviousCurrency, &schedule.Actor, &schedule.CreatedAt)
+}
+
+// ListScheduledPrices returns the scheduled prices of a product ordered by effective_from,
+// sql.ErrNoRows when the product doesn't exist or is deleted
+func (d *PGConnector) ListScheduledPrices(ctx context.Context, productID int) (schedules []*models.ScheduledPrice, err error) {
+	slog.DebugContext(ctx, "Entering ListScheduledPrices DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery("ListScheduledPrices", start, queryError(err))
+	}()
+
+	query := "SELECT " + scheduledPriceColumns + " FROM scheduled_prices WHERE product_id = $1 ORDER BY effective_from, id"
+	rows, err := d.Conn.QueryContext(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules = []*models.ScheduledPrice{}
+	for rows.Next() {
+		var schedule models.ScheduledPrice
+		if err = scanScheduledPrice(rows, &schedule); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, &schedule)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	// the schedules of a deleted product are kept until it is purged, but like its other parts they aren't listed
+	var exists bool
+	if exists, err = productExists(ctx, d.Conn, productID); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	slog.DebugContext(ctx, "Exiting ListScheduledPrices DB Function")
+	return schedules, nil
+}
+
+// GetScheduledPrice returns one scheduled price of a product, sql.ErrNoRows when the product doesn't exist
+// or is deleted and ErrScheduledPriceNotFound when the product has no such scheduled price
+func (d *PGConnector) GetScheduledPrice(ctx context.Context, productID int, scheduleID int) (*models.ScheduledPrice, error) {
+	slog.DebugContext(ctx, "Entering GetScheduledPrice DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	query := "SELECT " + scheduledPriceColumns + " FROM scheduled_prices WHERE id = $1 AND product_id = $2 " +
+		"AND EXISTS (SELECT 1 FROM products p WHERE p.id = product_id AND p.deleted_at IS NULL)"
+	var schedule models.ScheduledPrice
+	start := time.Now()
+	err := scanScheduledPrice(d.Conn.QueryRowContext(ctx, query, scheduleID, productID), &schedule)
+	metrics.ObserveQuery("GetScheduledPrice", start, queryError(err))
+	if errors.Is(err, sql.ErrNoRows) {
+		exists, err := productExists(ctx, d.Conn, productID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, sql.ErrNoRows
+		}
+		return nil, ErrScheduledPriceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting GetScheduledPrice DB Function")
+	return &schedule, nil
+}
+
+// CreateScheduledPrice queues a price change for the product, the X-Actor in ctx is kept for the price history.
+// It returns sql.ErrNoRows when the product doesn't exist or is deleted and ErrScheduleOverlap when the window
+// overlaps another pending or active scheduled price of the product.
+func (d *PGConnector) CreateScheduledPrice(ctx context.Context, productID int, request *models.ScheduledPriceRequest) (schedule *models.ScheduledPrice, err error) {
+	slog.DebugContext(ctx, "Entering CreateScheduledPrice DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery("CreateScheduledPrice", start, queryError(err))
+	}()
+
+	tx, err := d.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// locking the product row serializes the overlap check with concurrent schedules of the same product
+	var id int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", productID).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	var currency, actor *string
+	if request.Currency != "" {
+		currency = &request.Currency
+	}
+	if a := utils.Actor(ctx); a != "" {
+		actor = &a
+	}
+	query := "INSERT INTO scheduled_prices (product_id, price, currency, effective_from, effective_to, actor) " +
+		"SELECT $1, $4::numeric, $5::text, $2, $3, $6::text WHERE NOT EXISTS (SELECT 1 FROM scheduled_prices WHERE product_id = $1 " +
+		"AND status IN ('pending', 'active') AND " + scheduleWindow + " && " +
+		"tstzrange($2::timestamptz, COALESCE($3::timestamptz, $2::timestamptz), CASE WHEN $3::timestamptz IS NULL THEN '[]' ELSE '[)' END)) " +
+		"RETURNING " + scheduledPriceColumns
+	schedule = &models.ScheduledPrice{}
+	err = scanScheduledPrice(tx.QueryRowContext(ctx, query, productID, request.EffectiveFrom, request.EffectiveTo, request.Price, currency, actor), schedule)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrScheduleOverlap
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting CreateScheduledPrice DB Function")
+	return schedule, nil
+}
+
+// CancelScheduledPrice cancels a pending scheduled price. It returns sql.ErrNoRows when the product doesn't exist
+// or is deleted, ErrScheduledPriceNotFound when it has no such scheduled price and ErrScheduleNotPending
+// when the change has already been applied or has ended.
+func (d *PGConnector) CancelScheduledPrice(ctx context.Context, productID int, scheduleID int) error {
+	slog.DebugContext(ctx, "Entering CancelScheduledPrice DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	query := "UPDATE scheduled_prices s SET status = 'canceled' FROM products p WHERE s.id = $1 AND s.product_id = $2 " +
+		"AND p.id = s.product_id AND p.deleted_at IS NULL AND s.status = 'pending'"
+	start := time.Now()
+	result, err := d.Conn.ExecContext(ctx, query, scheduleID, productID)
+	metrics.ObserveQuery("CancelScheduledPrice", start, err)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return d.missedSchedule(ctx, productID, scheduleID)
+	}
+	slog.DebugContext(ctx, "Exiting CancelScheduledPrice DB Function")
+	return nil
+}
+
+// missedSchedule tells apart the reasons a scheduled price wasn't canceled
+func (d *PGConnector) missedSchedule(ctx context.Context, productID int, scheduleID int) error {
+	exists, err := productExists(ctx, d.Conn, productID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+	var status string
+	start := time.Now()
+	err = d.Conn.QueryRowContext(ctx, "SELECT status FROM scheduled_prices WHERE id = $1 AND product_id = $2", scheduleID, productID).Scan(&status)
+	metrics.ObserveQuery("ScheduledPriceStatus", start, queryError(err))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrScheduledPriceNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return ErrScheduleNotPending
+}
+
+// RunPriceSchedule reverts up to limit active scheduled prices whose window ended by now, then applies pending ones
+// whose window started, and returns the product id of every scheduled price it handled. Reverts come first so a
+// window can be followed right away by the next one. Everything runs in one transaction holding an advisory lock,
+// when another instance holds it nothing is done and no ids are returned.
+//
+// Applying a change whose window has already ended skips it, and a revert only puts the previous price back while
+// the product still has the scheduled one, so a price set by a client during the window is kept. Every price
+// written is recorded in the price history under the X-Actor of the scheduling request, or SchedulerActor.
+func (d *PGConnector) RunPriceSchedule(ctx context.Context, now time.Time, limit int) (productIDs []int, err error) {
+	slog.DebugContext(ctx, "Entering RunPriceSchedule DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery("RunPriceSchedule", start, queryError(err))
+	}()
+
+	tx, err := d.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err = tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", priceSchedulerLockID).Scan(&locked); err != nil {
+		return nil, err
+	}
+	if !locked {
+		slog.DebugContext(ctx, "Price schedule is being run by another instance")
+		return nil, nil
+	}
+
+	ending, err := dueSchedules(ctx, tx, "status = 'active' AND effective_to <= $1 ORDER BY effective_to, id", now, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, schedule := range ending {
+		if err = revertScheduledPrice(ctx, tx, schedule); err != nil {
+			return nil, err
+		}
+		productIDs = append(productIDs, schedule.ProductID)
+	}
+
+	starting, err := dueSchedules(ctx, tx, "status = 'pending' AND effective_from <= $1 ORDER BY effective_from, id", now, limit-len(ending))
+	if err != nil {
+		return nil, err
+	}
+	for _, schedule := range starting {
+		if err = applyScheduledPrice(ctx, tx, schedule, now); err != nil {
+			return nil, err
+		}
+		productIDs = append(productIDs, schedule.ProductID)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting RunPriceSchedule DB Function")
+	return productIDs, nil
+}
+
+// dueSchedules reads the scheduled prices matching where, which takes now as $1, up to limit of them
+func dueSchedules(ctx context.Context, tx *sql.Tx, where string, now time.Time, limit int) ([]*models.ScheduledPrice, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT "+scheduledPriceColumns+" FROM scheduled_prices WHERE "+where+" LIMIT $2", now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*models.ScheduledPrice
+	for rows.Next() {
+		var schedule models.ScheduledPrice
+		if err = scanScheduledPrice(rows, &schedule); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, &schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// applyScheduledPrice writes the scheduled price to the product and remembers the price it replaced
+func applyScheduledPrice(ctx context.Context, tx *sql.Tx, schedule *models.ScheduledPrice, now time.Time) error {
+	if schedule.EffectiveTo != nil && !schedule.EffectiveTo.After(now) {
+		return setScheduleStatus(ctx, tx, schedule.ID, models.ScheduleStatusSkipped)
+	}
+	old, err := lockPrice(ctx, tx, schedule.ProductID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return setScheduleStatus(ctx, tx, schedule.ID, models.ScheduleStatusCanceled)
+	}
+	if err != nil {
+		return err
+	}
+	currency := schedule.Currency
+	if currency == "" {
+		currency = old.Currency
+	}
+	if err = writeScheduledPrice(ctx, tx, schedule, old, models.EffectivePrice{Price: schedule.Price, Currency: currency}); err != nil {
+		return err
+	}
+
+	status := models.ScheduleStatusCompleted
+	if schedule.EffectiveTo != nil {
+		status = models.ScheduleStatusActive
+	}
+	query := "UPDATE scheduled_prices SET status = $2, currency = $3, previous_price = $4, previous_currency = $5 WHERE id = $1"
+	start := time.Now()
+	_, err = tx.ExecContext(ctx, query, schedule.ID, status, currency, old.Price, old.Currency)
+	metrics.ObserveQuery("ApplyScheduledPrice", start, err)
+	return err
+}
+
+// revertScheduledPrice puts the previous price back unless the product's price was changed during the window
+func revertScheduledPrice(ctx context.Context, tx *sql.Tx, schedule *models.ScheduledPrice) error {
+	current, err := lockPrice(ctx, tx, schedule.ProductID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return setScheduleStatus(ctx, tx, schedule.ID, models.ScheduleStatusCompleted)
+	}
+	if err != nil {
+		return err
+	}
+	if schedule.PreviousPrice != nil && current.Price == schedule.Price && current.Currency == schedule.Currency {
+		previous := models.EffectivePrice{Price: *schedule.PreviousPrice, Currency: schedule.PreviousCurrency}
+		if err = writeScheduledPrice(ctx, tx, schedule, current, previous); err != nil {
+			return err
+		}
+	}
+	return setScheduleStatus(ctx, tx, schedule.ID, models.ScheduleStatusCompleted)
+}
+
+// writeScheduledPrice sets the product's price like a client update would, bumping the version, and records the change
+func writeScheduledPrice(ctx context.Context, tx *sql.Tx, schedule *models.ScheduledPrice, old models.EffectivePrice, price models.EffectivePrice) error {
+	start := time.Now()
+	_, err := tx.ExecContext(ctx, "UPDATE products SET price = $2, currency = $3, version = version + 1, updated_at = now() WHERE id = $1",
+		schedule.ProductID, price.Price, price.Currency)
+	metrics.ObserveQuery("WriteScheduledPrice", start, err)
+	if err != nil {
+		return err
+	}
+	actor := schedule.Actor
+	if actor == "" {
+		actor = SchedulerActor
+	}
+	product := &models.Product{ID: schedule.ProductID, Price: price.Price, Currency: price.Currency}
+	return recordPriceChange(utils.WithActor(ctx, actor), tx, old, product)
+}
+
+func setScheduleStatus(ctx context.Context, tx *sql.Tx, id int, status string) error {
+	start := time.Now()
+	_, err := tx.ExecContext(ctx, "UPDATE scheduled_prices SET status = $2 WHERE id = $1", id, status)
+	metrics.ObserveQuery("SetScheduleStatus", start, err)
+	return err
+}
@@ -0,0 +1,25 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// withTimeout bounds a single DB or cache operation, a zero timeout only keeps the caller's deadline
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// IsTimeout reports whether err comes from an operation that ran out of time
+func IsTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsCanceled reports whether err comes from the caller going away, e.g. the HTTP client disconnecting
+func IsCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,207 @@
+package db
+
+import (
+	"ProductService/metrics"
+	"ProductService/models"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// variantColumns is the column list every variant query selects, in the order scanVariant reads them
+const variantColumns = "id, product_id, sku, options, price, available, created_at, updated_at"
+
+// productWithVariantsColumns adds the product's variants as a JSON array to productColumns, single product
+// reads and writes select it so the product, and the copy cached from it, carries its variants
+const productWithVariantsColumns = productColumns + ", COALESCE((SELECT json_agg(json_build_object(" +
+	"'id', v.id, 'product_id', v.product_id, 'sku', v.sku, 'options', v.options, 'price', v.price, " +
+	"'available', v.available, 'created_at', v.created_at, 'updated_at', v.updated_at) ORDER BY v.id) " +
+	"FROM product_variants v WHERE v.product_id = products.id AND v.deleted_at IS NULL), '[]')"
+
+const (
+	// VariantSKUConstraint is the unique index keeping the SKUs of variants that are not deleted distinct
+	VariantSKUConstraint = "product_variants_sku_key"
+	// VariantOptionsConstraint is the unique index keeping the options of a product's variants distinct
+	VariantOptionsConstraint = "product_variants_options_key"
+)
+
+// ErrVariantNotFound is returned when the product exists but has no such variant
+var ErrVariantNotFound = errors.New("variant not found")
+
+func scanVariant(row interface{ Scan(dest ...any) error }, variant *models.ProductVariant) error {
+	return row.Scan(&variant.ID, &variant.ProductID, &variant.SKU, &variant.Options, &variant.Price, &variant.Available,
+		&variant.CreatedAt, &variant.UpdatedAt)
+}
+
+// ListProductVariants returns the variants of a product ordered by id,
+// sql.ErrNoRows when the product doesn't exist or is deleted
+func (d *PGConnector) ListProductVariants(ctx context.Context, productID int) (variants []*models.ProductVariant, err error) {
+	slog.DebugContext(ctx, "Entering ListProductVariants DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery("ListProductVariants", start, queryError(err))
+	}()
+
+	query := "SELECT " + variantColumns + " FROM product_variants WHERE product_id = $1 AND deleted_at IS NULL ORDER BY id"
+	rows, err := d.Conn.QueryContext(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants = []*models.ProductVariant{}
+	for rows.Next() {
+		var variant models.ProductVariant
+		if err = scanVariant(rows, &variant); err != nil {
+			return nil, err
+		}
+		variants = append(variants, &variant)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(variants) == 0 {
+		// tell a product without variants apart from a missing one
+		var exists bool
+		if exists, err = productExists(ctx, d.Conn, productID); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, sql.ErrNoRows
+		}
+	}
+	slog.DebugContext(ctx, "Exiting ListProductVariants DB Function")
+	return variants, nil
+}
+
+// GetProductVariant returns one variant of a product, sql.ErrNoRows when the product doesn't exist
+// or is deleted and ErrVariantNotFound when the product has no such variant
+func (d *PGConnector) GetProductVariant(ctx context.Context, productID int, variantID int) (*models.ProductVariant, error) {
+	slog.DebugContext(ctx, "Entering GetProductVariant DB Function")
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	query := "SELECT " + variantColumns + " FROM product_variants WHERE id = $1 AND product_id = $2 AND deleted_at IS NULL"
+	var variant models.ProductVariant
+	start := time.Now()
+	err := scanVariant(d.Conn.QueryRowContext(ctx, query, variantID, productID), &variant)
+	metrics.ObserveQuery("GetProductVariant", start, queryError(err))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, d.missedVariant(ctx, d.Conn, productID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting GetProductVariant DB Function")
+	return &variant, nil
+}
+
+// CreateProductVariant adds a variant to the product and returns the stored row
+func (d *PGConnector) CreateProductVariant(ctx context.Context, productID int, request *models.VariantRequest) (*models.ProductVariant, error) {
+	slog.DebugContext(ctx, "Entering CreateProductVariant DB Function")
+	var variant models.ProductVariant
+	err := d.writeVariant(ctx, "CreateProductVariant", productID, func(ctx context.Context, tx *sql.Tx) error {
+		query := "INSERT INTO product_variants (product_id, sku, options, price, available) VALUES ($1, $2, $3, $4, $5) RETURNING " + variantColumns
+		return scanVariant(tx.QueryRowContext(ctx, query, productID, request.SKU, request.Options, request.Price, request.Available), &variant)
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting CreateProductVariant DB Function")
+	return &variant, nil
+}
+
+// UpdateProductVariant replaces every writable column of the variant and returns the stored row
+func (d *PGConnector) UpdateProductVariant(ctx context.Context, productID int, variantID int, request *models.VariantRequest) (*models.ProductVariant, error) {
+	slog.DebugContext(ctx, "Entering UpdateProductVariant DB Function")
+	var variant models.ProductVariant
+	err := d.writeVariant(ctx, "UpdateProductVariant", productID, func(ctx context.Context, tx *sql.Tx) error {
+		query := "UPDATE product_variants SET sku = $1, options = $2, price = $3, available = $4, updated_at = now() " +
+			"WHERE id = $5 AND product_id = $6 AND deleted_at IS NULL RETURNING " + variantColumns
+		err := scanVariant(tx.QueryRowContext(ctx, query, request.SKU, request.Options, request.Price, request.Available, variantID, productID), &variant)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVariantNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Exiting UpdateProductVariant DB Function")
+	return &variant, nil
+}
+
+// DeleteProductVariant removes the variant for good, only variants deleted along with their product can be restored
+func (d *PGConnector) DeleteProductVariant(ctx context.Context, productID int, variantID int) error {
+	slog.DebugContext(ctx, "Entering DeleteProductVariant DB Function")
+	err := d.writeVariant(ctx, "DeleteProductVariant", productID, func(ctx context.Context, tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM product_variants WHERE id = $1 AND product_id = $2 AND deleted_at IS NULL", variantID, productID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return ErrVariantNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "Exiting DeleteProductVariant DB Function")
+	return nil
+}
+
+// writeVariant runs a variant write in a transaction that also bumps the product's version, so the
+// product's ETag changes with its variants. It returns sql.ErrNoRows when the product doesn't exist or is deleted.
+func (d *PGConnector) writeVariant(ctx context.Context, operation string, productID int, write func(ctx context.Context, tx *sql.Tx) error) (err error) {
+	ctx, cancel := withTimeout(ctx, d.QueryTimeout)
+	defer cancel()
+	start := time.Now()
+	defer func() {
+		metrics.ObserveQuery(operation, start, queryError(err))
+	}()
+
+	tx, err := d.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the update also locks the product row, so a concurrent delete can't miss the variant being written
+	result, err := tx.ExecContext(ctx, "UPDATE products SET version = version + 1, updated_at = now() WHERE id = $1 AND deleted_at IS NULL", productID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err = write(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// missedVariant explains why a variant read matched no rows: the product is gone or deleted (sql.ErrNoRows)
+// or the product has no such variant (ErrVariantNotFound)
+func (d *PGConnector) missedVariant(ctx context.Context, q querier, productID int) error {
+	exists, err := productExists(ctx, q, productID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+	return ErrVariantNotFound
+}
@@ -0,0 +1,28 @@
+package metrics_test
+
+import (
+	"ProductService/metrics"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_ExposesRecordedMetrics(t *testing.T) {
+	metrics.ObserveRequest("/products/{id}", "GET", http.StatusNotFound, 10*time.Millisecond)
+	metrics.ObserveCache("GetProductByID", metrics.CacheMiss)
+	metrics.ObserveQuery("GetProductByID", time.Now(), errors.New("db error"))
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, body, `productservice_http_requests_total{method="GET",route="/products/{id}",status="404"} 1`)
+	assert.Contains(t, body, `productservice_http_request_duration_seconds_count{method="GET",route="/products/{id}"} 1`)
+	assert.Contains(t, body, `productservice_cache_operations_total{operation="GetProductByID",result="miss"} 1`)
+	assert.Contains(t, body, `productservice_db_errors_total{operation="GetProductByID"} 1`)
+	assert.Contains(t, body, `productservice_db_query_duration_seconds_count{operation="GetProductByID"} 1`)
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Limits checked by the attribute_key and attribute_value validation rules,
+// the number of attributes and the tags are limited in the request struct tags
+const (
+	MaxAttributeKeyLength   = 64
+	MaxAttributeValueLength = 500
+)
+
+// Attributes are free-form key/value pairs stored as a JSONB object.
+// Values are strings, numbers or booleans, numbers decode as float64.
+type Attributes map[string]interface{}
+
+// Value stores the attributes as a JSON object, nil is stored as an empty object
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string]interface{}(a))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads a JSONB object
+func (a *Attributes) Scan(src interface{}) error {
+	attributes := Attributes{}
+	if err := scanJSONObject(src, &attributes); err != nil {
+		return err
+	}
+	*a = attributes
+	return nil
+}
+
+// scanJSONObject decodes a JSONB column into dest, NULL leaves dest untouched
+func scanJSONObject(src interface{}, dest interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	case nil:
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into %T", src, dest)
+}
@@ -0,0 +1,77 @@
+package models
+
+// Operations accepted by POST /products:batch
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchRequest is the body of POST /products:batch
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation is one item of a batch. Update and delete need the ID, create and update carry the
+// product fields, which are validated like the body of POST /products and PUT /products/{id}.
+// A non zero Version makes an update or delete conditional, like If-Match does for single requests.
+type BatchOperation struct {
+	Op          string     `json:"op" validate:"required,oneof=create update delete"`
+	ID          int        `json:"id,omitempty" validate:"required_unless=Op create,gte=0"`
+	Version     int        `json:"version,omitempty" validate:"gte=0"`
+	SKU         string     `json:"sku,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Price       float64    `json:"price,omitempty"`
+	Currency    string     `json:"currency,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	Attributes  Attributes `json:"attributes,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+}
+
+// CreateRequest is the product a create operation inserts
+func (o BatchOperation) CreateRequest() *CreateProductRequest {
+	return &CreateProductRequest{
+		SKU:         o.SKU,
+		Name:        o.Name,
+		Description: o.Description,
+		Price:       o.Price,
+		Currency:    o.Currency,
+		Status:      o.Status,
+		Attributes:  o.Attributes,
+		Tags:        o.Tags,
+	}
+}
+
+// UpdateRequest is the replacement an update operation writes
+func (o BatchOperation) UpdateRequest() *UpdateProductRequest {
+	return &UpdateProductRequest{
+		ID:          o.ID,
+		SKU:         o.SKU,
+		Name:        o.Name,
+		Description: o.Description,
+		Price:       o.Price,
+		Currency:    o.Currency,
+		Status:      o.Status,
+		Attributes:  o.Attributes,
+		Tags:        o.Tags,
+	}
+}
+
+// BatchResponse is the ResponseBody of POST /products:batch, Results has one entry per operation in request order
+type BatchResponse struct {
+	Mode      string            `json:"mode"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
+
+// BatchItemResult reports what happened to one operation, Status is an HTTP status code
+type BatchItemResult struct {
+	Index   int    `json:"index"`
+	Op      string `json:"op"`
+	ID      int    `json:"id,omitempty"`
+	Version int    `json:"version,omitempty"`
+	Status  int    `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Category groups products, categories form a tree through ParentID
+type Category struct {
+	ID int `json:"id"`
+	// ParentID is nil for top level categories
+	ParentID  *int      `json:"parent_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CategoryNode is a category with its subtree, it is what GET /categories returns and what is cached
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// CategoryRequest is the body of POST /categories and PUT /categories/{id},
+// a PUT without parent_id moves the category to the top level
+type CategoryRequest struct {
+	Name     string `json:"name" validate:"required,max=200"`
+	ParentID *int   `json:"parent_id" validate:"omitempty,gt=0"`
+}
+
+// ProductCategoriesRequest is the body of PUT /products/{id}/categories, it replaces the product's categories
+type ProductCategoriesRequest struct {
+	CategoryIDs []int `json:"category_ids" validate:"required,max=100,unique,dive,gt=0"`
+}
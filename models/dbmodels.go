@@ -1,7 +1,43 @@
 package models
 
+import "time"
+
+// Product lifecycle statuses
+const (
+	StatusDraft    = "draft"
+	StatusActive   = "active"
+	StatusArchived = "archived"
+)
+
+// Defaults of a new product when the create request leaves them out
+const (
+	DefaultCurrency = "USD"
+	DefaultStatus   = StatusDraft
+)
+
 type Product struct {
-	ID    int     `json:"id"`
-	Name  string  `json:"name"`
+	ID int `json:"id"`
+	// SKU is unique among products that are not deleted
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Price is stored as NUMERIC so amounts like 19.99 are kept exactly
 	Price float64 `json:"price"`
+	// Currency is the ISO 4217 code of the base currency the price is stored in
+	Currency string `json:"currency"`
+	// BasePrice and BaseCurrency are only set when the price was converted with ?currency, they hold the stored price
+	BasePrice    *float64 `json:"base_price,omitempty"`
+	BaseCurrency string   `json:"base_currency,omitempty"`
+	Status       string   `json:"status"`
+	// Attributes and Tags are free-form, they can be filtered on with attr.<key> and tag
+	Attributes Attributes `json:"attributes"`
+	Tags       []string   `json:"tags"`
+	// Version is bumped on every write, it is sent to clients as the ETag
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt is set once the product has been soft deleted, only listings with include_deleted return such products
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Variants are only loaded when a single product is read or written, listings leave them out
+	Variants []*ProductVariant `json:"variants,omitempty"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import "net/http"
+
+// IdempotencyRecord is kept in the cache for every Idempotency-Key sent with a POST.
+// It starts without a response while the first request is in flight, once that request
+// is done it holds the encoded response replayed to retries with the same key.
+type IdempotencyRecord struct {
+	Fingerprint string      `json:"fingerprint"`
+	Completed   bool        `json:"completed"`
+	StatusCode  int         `json:"status_code,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// ResponseHeader returns the headers of the original response, marked as a replay
+func (r IdempotencyRecord) ResponseHeader() http.Header {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Idempotent-Replayed", "true")
+	return header
+}
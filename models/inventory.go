@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// DefaultWarehouse holds the stock of requests that don't name a warehouse
+const DefaultWarehouse = "default"
+
+// Reservation limits, the default TTL is overridden from the config in the app
+const (
+	MaxReservationQuantity = 1000000
+	MaxReservationTTL      = 24 * time.Hour
+)
+
+// StockLevel is the stock of a product in one warehouse
+type StockLevel struct {
+	Warehouse string `json:"warehouse"`
+	OnHand    int    `json:"on_hand"`
+	// Reserved is held by reservations that are neither committed, released nor expired yet
+	Reserved int `json:"reserved"`
+	// Available is OnHand minus Reserved, what can still be reserved
+	Available int       `json:"available"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Inventory is the stock of a product summed over its warehouses, Warehouses is empty for a product without stock
+type Inventory struct {
+	ProductID  int           `json:"product_id"`
+	OnHand     int           `json:"on_hand"`
+	Reserved   int           `json:"reserved"`
+	Available  int           `json:"available"`
+	Warehouses []*StockLevel `json:"warehouses"`
+}
+
+// StockLevelRequest is the body of PUT /products/{id}/inventory/{warehouse}, it sets the on hand stock after a count
+type StockLevelRequest struct {
+	OnHand *int `json:"on_hand" validate:"required,gte=0,lte=1000000000"`
+}
+
+// StockAdjustmentRequest is the body of POST /products/{id}/inventory/adjustments, a positive delta receives
+// stock and a negative one writes it off. Warehouse defaults to DefaultWarehouse.
+type StockAdjustmentRequest struct {
+	Warehouse string `json:"warehouse,omitempty" validate:"omitempty,warehouse"`
+	Delta     int    `json:"delta" validate:"required,gte=-1000000000,lte=1000000000"`
+}
+
+// ApplyDefaults fills in the optional fields the client left out
+func (r *StockAdjustmentRequest) ApplyDefaults() {
+	if r.Warehouse == "" {
+		r.Warehouse = DefaultWarehouse
+	}
+}
+
+// Reservation holds stock of one warehouse for a checkout until it is committed, released or it expires
+type Reservation struct {
+	ID        int       `json:"id"`
+	ProductID int       `json:"product_id"`
+	Warehouse string    `json:"warehouse"`
+	Quantity  int       `json:"quantity"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReservationRequest is the body of POST /products/{id}/reservations. Without a warehouse the stock is
+// reserved in the warehouse with the most available stock that can cover the whole quantity.
+// TTLSeconds falls back to the configured reservation TTL.
+type ReservationRequest struct {
+	Quantity   int    `json:"quantity" validate:"required,gt=0,lte=1000000"`
+	Warehouse  string `json:"warehouse,omitempty" validate:"omitempty,warehouse"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty" validate:"omitempty,gt=0,lte=86400"`
+}
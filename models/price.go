@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// MaxPriceDecimals is the scale of the NUMERIC(19, 4) price columns, the price_decimals validation rule
+// rejects amounts Postgres would have to round
+const MaxPriceDecimals = 4
+
+// PriceChange is one change of a product's price or currency, GET /products/{id}/prices lists them newest first
+type PriceChange struct {
+	ID          int       `json:"id"`
+	ProductID   int       `json:"product_id"`
+	OldPrice    float64   `json:"old_price"`
+	NewPrice    float64   `json:"new_price"`
+	OldCurrency string    `json:"old_currency"`
+	NewCurrency string    `json:"new_currency"`
+	ChangedAt   time.Time `json:"changed_at"`
+	// Actor is the X-Actor of the request that made the change, empty when it had none
+	Actor string `json:"actor,omitempty"`
+}
+
+// EffectivePrice is the price a product had at a point in time
+type EffectivePrice struct {
+	Price    float64 `json:"price"`
+	Currency string  `json:"currency"`
+}
+
+// Scheduled price statuses
+const (
+	// ScheduleStatusPending changes wait for their effective_from
+	ScheduleStatusPending = "pending"
+	// ScheduleStatusActive changes have been applied and are reverted at their effective_to
+	ScheduleStatusActive = "active"
+	// ScheduleStatusCompleted changes are permanent ones that have been applied or windows that have been reverted
+	ScheduleStatusCompleted = "completed"
+	// ScheduleStatusCanceled changes were canceled by a client, or their product was deleted before they fired
+	ScheduleStatusCanceled = "canceled"
+	// ScheduleStatusSkipped changes had their whole window pass while the scheduler wasn't running
+	ScheduleStatusSkipped = "skipped"
+)
+
+// ScheduledPrice is a price change queued by POST /products/{id}/scheduled-prices. The price scheduler applies it
+// at EffectiveFrom and puts PreviousPrice back at EffectiveTo, a change without EffectiveTo is permanent.
+type ScheduledPrice struct {
+	ID        int     `json:"id"`
+	ProductID int     `json:"product_id"`
+	Price     float64 `json:"price"`
+	// Currency is empty until the change is applied when the request kept the product's currency
+	Currency      string     `json:"currency,omitempty"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	Status        string     `json:"status"`
+	// PreviousPrice and PreviousCurrency are the price the change replaced, set once it has been applied
+	PreviousPrice    *float64  `json:"previous_price,omitempty"`
+	PreviousCurrency string    `json:"previous_currency,omitempty"`
+	Actor            string    `json:"actor,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ScheduledPriceRequest is the body of POST /products/{id}/scheduled-prices. Without a currency the product
+// keeps the one it has when the change is applied, without effective_to the change is permanent.
+type ScheduledPriceRequest struct {
+	Price         float64    `json:"price" validate:"required,gt=0,lt=1000000000000000,price_decimals"`
+	Currency      string     `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	EffectiveFrom time.Time  `json:"effective_from" validate:"required"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+}
@@ -1,12 +1,71 @@
 package models
 
+import "encoding/json"
+
+// CreateProductRequest is the body of POST /products, Currency and Status fall back to
+// DefaultCurrency and DefaultStatus when they are left out
 type CreateProductRequest struct {
-	Name  string  `json:"name" validate:"required"`
-	Price float64 `json:"price" validate:"required"`
+	SKU         string     `json:"sku" validate:"required,max=64,printascii"`
+	Name        string     `json:"name" validate:"required"`
+	Description string     `json:"description,omitempty" validate:"max=4000"`
+	Price       float64    `json:"price" validate:"required,gt=0,lt=1000000000000000,price_decimals"`
+	Currency    string     `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	Status      string     `json:"status,omitempty" validate:"omitempty,oneof=draft active archived"`
+	Attributes  Attributes `json:"attributes,omitempty" validate:"omitempty,max=50,dive,keys,attribute_key,endkeys,attribute_value"`
+	Tags        []string   `json:"tags,omitempty" validate:"omitempty,max=50,unique,dive,required,max=64,printascii"`
+}
+
+// ApplyDefaults fills in the optional fields the client left out
+func (r *CreateProductRequest) ApplyDefaults() {
+	if r.Currency == "" {
+		r.Currency = DefaultCurrency
+	}
+	if r.Status == "" {
+		r.Status = DefaultStatus
+	}
 }
 
+// UpdateProductRequest is the body of PUT /products/{id}. It replaces the whole product,
+// so unlike on create every field except the description, attributes and tags is required
+// and leaving those out clears them.
 type UpdateProductRequest struct {
-	ID    int     `json:"id"`
-	Name  string  `json:"name" validate:"required"`
-	Price float64 `json:"price" validate:"required"`
+	ID          int        `json:"id"`
+	SKU         string     `json:"sku" validate:"required,max=64,printascii"`
+	Name        string     `json:"name" validate:"required"`
+	Description string     `json:"description" validate:"max=4000"`
+	Price       float64    `json:"price" validate:"required,gt=0,lt=1000000000000000,price_decimals"`
+	Currency    string     `json:"currency" validate:"required,iso4217"`
+	Status      string     `json:"status" validate:"required,oneof=draft active archived"`
+	Attributes  Attributes `json:"attributes" validate:"omitempty,max=50,dive,keys,attribute_key,endkeys,attribute_value"`
+	Tags        []string   `json:"tags" validate:"omitempty,max=50,unique,dive,required,max=64,printascii"`
+}
+
+// ProductFilter holds the optional search, price range and sort parameters of the product list
+type ProductFilter struct {
+	Query    string
+	MinPrice *float64
+	MaxPrice *float64
+	Sort     string
+	// IncludeDeleted also lists soft deleted products
+	IncludeDeleted bool
+	// CategoryIDs limits the list to products assigned to any of these categories
+	CategoryIDs []int
+	// Tags limits the list to products carrying all of these tags
+	Tags []string
+	// Attributes limits the list to products whose attributes have these values
+	Attributes map[string]string
+}
+
+// ProductCursor is the decoded form of the opaque cursor used for keyset pagination.
+// Key holds the sort column value of the last product returned, ID breaks ties.
+type ProductCursor struct {
+	Sort string      `json:"s"`
+	Key  interface{} `json:"k"`
+	ID   int         `json:"id"`
+}
+
+// PatchProductRequest carries the raw patch document of a PATCH request,
+// it is applied to the stored product according to the request Content-Type
+type PatchProductRequest struct {
+	Patch json.RawMessage
 }
@@ -1,10 +1,14 @@
 package models
 
+import "net/http"
+
 type Result struct {
 	ResponseCode        string      `json:"response_code" validate:"required"`
 	ResponseStatus      string      `json:"response_status"`
 	ResponseDescription string      `json:"response_description"`
 	ResponseBody        interface{} `json:"response_body"`
+	// Header holds extra response headers such as the ETag, it is not part of the body
+	Header http.Header `json:"-"`
 }
 
 type PaginatedResponse struct {
@@ -22,3 +26,68 @@ type PaginationProductResponse struct {
 	Offset     int         `json:"offset"`
 	Products   interface{} `json:"products"`
 }
+
+// PaginatedPriceResponse is the paged price history of GET /products/{id}/prices
+type PaginatedPriceResponse struct {
+	ResponseCode        string                  `json:"response_code" validate:"required"`
+	ResponseStatus      string                  `json:"response_status"`
+	ResponseDescription string                  `json:"response_description"`
+	ResponseBody        PaginationPriceResponse `json:"response_body"`
+}
+
+type PaginationPriceResponse struct {
+	PageNo     int            `json:"page_no"`
+	PageSize   int            `json:"page_size"`
+	TotalCount int            `json:"total_count"`
+	TotalPages int            `json:"total_pages"`
+	Offset     int            `json:"offset"`
+	Prices     []*PriceChange `json:"prices"`
+}
+
+type CursorPaginatedResponse struct {
+	ResponseCode        string                          `json:"response_code" validate:"required"`
+	ResponseStatus      string                          `json:"response_status"`
+	ResponseDescription string                          `json:"response_description"`
+	ResponseBody        CursorPaginationProductResponse `json:"response_body"`
+}
+
+// CursorPaginationProductResponse is returned when the list is paged with a cursor instead of a page number
+type CursorPaginationProductResponse struct {
+	PaginationProductResponse
+	Cursor     string `json:"cursor"`
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
+
+type HealthResponse struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyStatus `json:"checks,omitempty"`
+}
+
+type DependencyStatus struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Description returns the ResponseDescription, it lets the controller read it from any response type
+func (r Result) Description() string {
+	return r.ResponseDescription
+}
+
+func (r PaginatedResponse) Description() string {
+	return r.ResponseDescription
+}
+
+func (r CursorPaginatedResponse) Description() string {
+	return r.ResponseDescription
+}
+
+func (r PaginatedPriceResponse) Description() string {
+	return r.ResponseDescription
+}
+
+// ResponseHeader returns the extra headers the controller has to send with the response
+func (r Result) ResponseHeader() http.Header {
+	return r.Header
+}
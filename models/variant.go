@@ -0,0 +1,68 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// MaxVariantOptions is the most options a variant can be told apart by, e.g. size and color
+const MaxVariantOptions = 10
+
+// VariantOptions are the option values that set a variant apart from its siblings, e.g. {"size": "M"}.
+// They are stored as a JSONB object and no two variants of a product have the same options.
+type VariantOptions map[string]string
+
+// Value stores the options as a JSON object, nil is stored as an empty object
+func (o VariantOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string]string(o))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads a JSONB object
+func (o *VariantOptions) Scan(src interface{}) error {
+	options := VariantOptions{}
+	if err := scanJSONObject(src, &options); err != nil {
+		return err
+	}
+	*o = options
+	return nil
+}
+
+// ProductVariant is a sellable version of a product, e.g. one size of a shirt
+type ProductVariant struct {
+	ID        int `json:"id"`
+	ProductID int `json:"product_id"`
+	// SKU is unique among variants of products that are not deleted
+	SKU     string         `json:"sku"`
+	Options VariantOptions `json:"options"`
+	// Price overrides the product price, nil means the variant sells at the product price
+	Price     *float64  `json:"price"`
+	Available bool      `json:"available"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// VariantRequest is the body of POST /products/{id}/variants and PUT /products/{id}/variants/{variantId}.
+// A PUT replaces the variant, so leaving out the price makes it sell at the product price again.
+// Available defaults to true.
+type VariantRequest struct {
+	SKU       string         `json:"sku" validate:"required,max=64,printascii"`
+	Options   VariantOptions `json:"options" validate:"required,min=1,max=10,dive,keys,attribute_key,endkeys,required,max=100"`
+	Price     *float64       `json:"price" validate:"omitempty,gt=0,lt=1000000000000000,price_decimals"`
+	Available *bool          `json:"available"`
+}
+
+// ApplyDefaults fills in the optional fields the client left out
+func (r *VariantRequest) ApplyDefaults() {
+	if r.Available == nil {
+		available := true
+		r.Available = &available
+	}
+}
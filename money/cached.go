@@ -0,0 +1,52 @@
+package money
+
+import (
+	"ProductService/db"
+	"context"
+	"log/slog"
+	"math/big"
+	"time"
+)
+
+// DefaultRateCacheTTL is how long a rate stays in the cache unless the config says otherwise
+const DefaultRateCacheTTL = time.Hour
+
+// CachedProvider serves rates from the cache and asks Source on a miss, storing what it got for TTL.
+// The rates are shared by every instance through Redis, so Source is asked about once per pair and TTL.
+// Cache failures fall back to Source.
+type CachedProvider struct {
+	Source ExchangeRateProvider
+	Cache  db.CacheInterface
+	TTL    time.Duration
+}
+
+func NewCachedProvider(source ExchangeRateProvider, cache db.CacheInterface) *CachedProvider {
+	return &CachedProvider{
+		Source: source,
+		Cache:  cache,
+		TTL:    DefaultRateCacheTTL,
+	}
+}
+
+func (p *CachedProvider) Rate(ctx context.Context, from string, to string) (*big.Rat, error) {
+	cached, err := p.Cache.GetExchangeRate(ctx, from, to)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read exchange rate from cache", "from", from, "to", to, "error", err)
+	}
+	if cached != "" {
+		if rate, ok := new(big.Rat).SetString(cached); ok {
+			return rate, nil
+		}
+		slog.WarnContext(ctx, "Invalid exchange rate in cache", "from", from, "to", to, "rate", cached)
+	}
+
+	rate, err := p.Source.Rate(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// the rate is kept as an exact fraction such as 23/25, a decimal could lose digits
+	if err := p.Cache.SetExchangeRate(ctx, from, to, rate.RatString(), p.TTL); err != nil {
+		slog.WarnContext(ctx, "Failed to store exchange rate in cache", "from", from, "to", to, "error", err)
+	}
+	return rate, nil
+}
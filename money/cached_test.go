@@ -0,0 +1,51 @@
+package money_test
+
+import (
+	"ProductService/db/mocks"
+	"ProductService/money"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestCachedProvider_Rate(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	source := money.NewStaticProvider(map[string]*big.Rat{"USD": big.NewRat(1, 1), "EUR": big.NewRat(23, 25)})
+	provider := money.NewCachedProvider(source, cache)
+
+	rate, err := provider.Rate(context.Background(), "EUR", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "25/23", rate.String())
+
+	cached, _ := cache.GetExchangeRate(context.Background(), "EUR", "USD")
+	assert.Equal(t, "25/23", cached)
+
+	// a cached rate is served without asking the source
+	provider.Source = money.NewStaticProvider(nil)
+	rate, err = provider.Rate(context.Background(), "EUR", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "25/23", rate.String())
+
+	_, err = provider.Rate(context.Background(), "USD", "EUR")
+	assert.True(t, errors.Is(err, money.ErrRateNotFound))
+}
+
+func TestCachedProvider_Rate_CacheFailure(t *testing.T) {
+	cache := new(mocks.MockCacheInterface)
+	source := money.NewStaticProvider(map[string]*big.Rat{"USD": big.NewRat(1, 1), "EUR": big.NewRat(23, 25)})
+	provider := money.NewCachedProvider(source, cache)
+	provider.TTL = time.Minute
+
+	cache.On("GetExchangeRate", mock.Anything, "USD", "EUR").Return("", errors.New("redis down"))
+	cache.On("SetExchangeRate", mock.Anything, "USD", "EUR", "23/25", time.Minute).Return(errors.New("redis down"))
+
+	rate, err := provider.Rate(context.Background(), "USD", "EUR")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "23/25", rate.String())
+	cache.AssertExpectations(t)
+}
@@ -0,0 +1,46 @@
+package money
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+)
+
+// Converter converts prices with the rates of Provider and rounds them with Rounding
+type Converter struct {
+	Provider ExchangeRateProvider
+	Rounding *Rounding
+}
+
+func NewConverter(provider ExchangeRateProvider, rounding *Rounding) *Converter {
+	return &Converter{
+		Provider: provider,
+		Rounding: rounding,
+	}
+}
+
+// Rate returns the rate from one currency to another, it is 1 for the same currency
+func (c *Converter) Rate(ctx context.Context, from string, to string) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+	return c.Provider.Rate(ctx, from, to)
+}
+
+// Apply converts amount with rate and rounds the result by the rule of currency. The amount is taken
+// as the shortest decimal that prints as the float, so 19.99 is converted as exactly 19.99.
+func (c *Converter) Apply(amount float64, rate *big.Rat, currency string) float64 {
+	value, _ := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', -1, 64))
+	value.Mul(value, rate)
+	result, _ := c.Rounding.Round(value, currency).Float64()
+	return result
+}
+
+// Convert converts amount from one currency to another
+func (c *Converter) Convert(ctx context.Context, amount float64, from string, to string) (float64, error) {
+	rate, err := c.Rate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return c.Apply(amount, rate, to), nil
+}
@@ -0,0 +1,18 @@
+// Package money converts prices between currencies. Amounts are converted with exact rational arithmetic
+// and only rounded once, by the rules of the target currency.
+package money
+
+import (
+	"context"
+	"errors"
+	"math/big"
+)
+
+// ErrRateNotFound is returned when a provider has no rate for a pair of currencies
+var ErrRateNotFound = errors.New("no exchange rate")
+
+// ExchangeRateProvider looks up how much one unit of a currency is worth in another
+type ExchangeRateProvider interface {
+	// Rate returns the rate from one currency to another, ErrRateNotFound when the pair is unknown
+	Rate(ctx context.Context, from string, to string) (*big.Rat, error)
+}
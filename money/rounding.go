@@ -0,0 +1,134 @@
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode decides which way an amount between two representable values goes
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds to the nearest value, ties away from zero
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds to the nearest value, ties to the even one
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundDown truncates towards zero
+	RoundDown RoundingMode = "down"
+	// RoundUp rounds away from zero
+	RoundUp RoundingMode = "up"
+)
+
+// MaxDecimals is the most decimals a rounding rule can keep
+const MaxDecimals = 8
+
+// RoundingRule is how amounts in a currency are rounded, an empty Mode falls back to the default rule's
+type RoundingRule struct {
+	Decimals int
+	Mode     RoundingMode
+}
+
+// Rounding holds the rounding rule of every currency, currencies without one use Default
+type Rounding struct {
+	Default    RoundingRule
+	Currencies map[string]RoundingRule
+}
+
+// DefaultRounding rounds half up to the minor unit of the currency per ISO 4217, two decimals unless listed here
+func DefaultRounding() *Rounding {
+	rounding := &Rounding{
+		Default:    RoundingRule{Decimals: 2, Mode: RoundHalfUp},
+		Currencies: map[string]RoundingRule{},
+	}
+	for _, currency := range []string{"BIF", "CLP", "DJF", "GNF", "ISK", "JPY", "KMF", "KRW", "PYG", "RWF", "UGX", "VND", "VUV", "XAF", "XOF", "XPF"} {
+		rounding.Currencies[currency] = RoundingRule{Decimals: 0}
+	}
+	for _, currency := range []string{"BHD", "IQD", "JOD", "KWD", "LYD", "OMR", "TND"} {
+		rounding.Currencies[currency] = RoundingRule{Decimals: 3}
+	}
+	return rounding
+}
+
+// ParseRounding applies comma separated CODE:decimals[:mode] rules on top of DefaultRounding,
+// e.g. "CHF:2:half_even,HUF:0". The code * changes the default rule.
+func ParseRounding(spec string) (*Rounding, error) {
+	rounding := DefaultRounding()
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("rounding rule %q must be CODE:decimals[:mode]", entry)
+		}
+		decimals, err := strconv.Atoi(parts[1])
+		if err != nil || decimals < 0 || decimals > MaxDecimals {
+			return nil, fmt.Errorf("rounding rule %q must keep 0 to %d decimals", entry, MaxDecimals)
+		}
+		rule := RoundingRule{Decimals: decimals}
+		if len(parts) == 3 {
+			rule.Mode = RoundingMode(parts[2])
+			switch rule.Mode {
+			case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+			default:
+				return nil, fmt.Errorf("rounding rule %q must use half_up, half_even, down or up", entry)
+			}
+		}
+
+		if parts[0] == "*" {
+			rounding.Default.Decimals = rule.Decimals
+			if rule.Mode != "" {
+				rounding.Default.Mode = rule.Mode
+			}
+			continue
+		}
+		if err := checkCurrency(parts[0]); err != nil {
+			return nil, fmt.Errorf("rounding rule %q: %w", entry, err)
+		}
+		rounding.Currencies[parts[0]] = rule
+	}
+	return rounding, nil
+}
+
+// Rule returns the rule amounts in currency are rounded with
+func (r *Rounding) Rule(currency string) RoundingRule {
+	rule, ok := r.Currencies[currency]
+	if !ok {
+		return r.Default
+	}
+	if rule.Mode == "" {
+		rule.Mode = r.Default.Mode
+	}
+	return rule
+}
+
+// Round rounds amount to the decimals of currency
+func (r *Rounding) Round(amount *big.Rat, currency string) *big.Rat {
+	rule := r.Rule(currency)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(rule.Decimals)), nil)
+	scaled := new(big.Rat).Mul(amount, new(big.Rat).SetInt(scale))
+
+	// QuoRem truncates towards zero, the remainder decides whether to step away from it
+	quotient, remainder := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if remainder.Sign() != 0 {
+		step := big.NewInt(int64(scaled.Sign()))
+		// twice the remainder against the denominator tells below, at or above the half
+		half := new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(scaled.Denom())
+		switch rule.Mode {
+		case RoundUp:
+			quotient.Add(quotient, step)
+		case RoundHalfUp:
+			if half >= 0 {
+				quotient.Add(quotient, step)
+			}
+		case RoundHalfEven:
+			if half > 0 || (half == 0 && quotient.Bit(0) == 1) {
+				quotient.Add(quotient, step)
+			}
+		}
+	}
+	return new(big.Rat).SetFrac(quotient, scale)
+}
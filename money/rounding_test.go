@@ -0,0 +1,53 @@
+package money_test
+
+import (
+	"ProductService/money"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestRounding_Round(t *testing.T) {
+	rounding, err := money.ParseRounding("EUR:2:half_even,CHF:1:up,SEK:0:down")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		amount   string
+		currency string
+		want     string
+	}{
+		{"19.995", "USD", "20"},
+		{"19.994", "USD", "19.99"},
+		{"-19.995", "USD", "-20"},
+		{"0.125", "EUR", "0.12"},
+		{"0.135", "EUR", "0.14"},
+		{"0.1251", "EUR", "0.13"},
+		{"1.01", "CHF", "1.1"},
+		{"-1.01", "CHF", "-1.1"},
+		{"99.99", "SEK", "99"},
+		{"151.5", "JPY", "152"},
+		{"1.2345", "KWD", "1.235"},
+		{"1/3", "USD", "0.33"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			amount, _ := new(big.Rat).SetString(tt.amount)
+			want, _ := new(big.Rat).SetString(tt.want)
+			got := rounding.Round(amount, tt.currency)
+			assert.Equal(t, want.String(), got.String())
+		})
+	}
+}
+
+func TestParseRounding(t *testing.T) {
+	rounding, err := money.ParseRounding("*:3:half_even, JPY:0")
+	assert.NoError(t, err)
+	assert.Equal(t, money.RoundingRule{Decimals: 3, Mode: money.RoundHalfEven}, rounding.Rule("USD"))
+	assert.Equal(t, money.RoundingRule{Decimals: 0, Mode: money.RoundHalfEven}, rounding.Rule("JPY"))
+
+	for _, spec := range []string{"USD", "USD:two", "USD:9", "USD:2:nearest", "usd:2", "XXY:2", "USD:2:up:extra"} {
+		_, err := money.ParseRounding(spec)
+		assert.Error(t, err, spec)
+	}
+}
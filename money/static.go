@@ -0,0 +1,135 @@
+package money
+
+import (
+	"ProductService/utils"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StaticProvider serves rates that are all quoted against one base currency, any pair of the
+// known currencies is converted through it
+type StaticProvider struct {
+	rates map[string]*big.Rat
+}
+
+// NewStaticProvider serves the given rates, each is the worth of one unit of the base currency,
+// which itself has to be listed with a rate of 1 to be convertible
+func NewStaticProvider(rates map[string]*big.Rat) *StaticProvider {
+	return &StaticProvider{rates: rates}
+}
+
+// LoadStaticProvider reads the rates from a .json or .csv file.
+//
+// A JSON file names the base currency and quotes the others against it, as numbers or strings:
+//
+//	{"base": "USD", "rates": {"EUR": 0.92, "JPY": "151.37"}}
+//
+// A CSV file has a currency,rate header and one row per currency, the base currency listed with rate 1.
+func LoadStaticProvider(path string) (*StaticProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rates map[string]*big.Rat
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		rates, err = readJSONRates(file)
+	case ".csv":
+		rates, err = readCSVRates(file)
+	default:
+		return nil, fmt.Errorf("exchange rates file %v must be .json or .csv", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("exchange rates file %v: %w", path, err)
+	}
+	return NewStaticProvider(rates), nil
+}
+
+func (p *StaticProvider) Rate(ctx context.Context, from string, to string) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+	fromRate, fromOK := p.rates[from]
+	toRate, toOK := p.rates[to]
+	if !fromOK || !toOK {
+		return nil, fmt.Errorf("%w from %v to %v", ErrRateNotFound, from, to)
+	}
+	return new(big.Rat).Quo(toRate, fromRate), nil
+}
+
+// Currencies returns how many currencies have a rate
+func (p *StaticProvider) Currencies() int {
+	return len(p.rates)
+}
+
+func readJSONRates(r io.Reader) (map[string]*big.Rat, error) {
+	var file struct {
+		Base  string                 `json:"base"`
+		Rates map[string]json.Number `json:"rates"`
+	}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+	if err := checkCurrency(file.Base); err != nil {
+		return nil, fmt.Errorf("base: %w", err)
+	}
+	rates := map[string]*big.Rat{file.Base: big.NewRat(1, 1)}
+	for currency, value := range file.Rates {
+		if err := addRate(rates, currency, value.String()); err != nil {
+			return nil, err
+		}
+	}
+	if rates[file.Base].Cmp(big.NewRat(1, 1)) != 0 {
+		return nil, fmt.Errorf("the base currency %v must have rate 1", file.Base)
+	}
+	return rates, nil
+}
+
+func readCSVRates(r io.Reader) (map[string]*big.Rat, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || !strings.EqualFold(records[0][0], "currency") || !strings.EqualFold(records[0][1], "rate") {
+		return nil, errors.New("the first line must be the currency,rate header")
+	}
+	rates := map[string]*big.Rat{}
+	for _, record := range records[1:] {
+		if err := addRate(rates, record[0], record[1]); err != nil {
+			return nil, err
+		}
+	}
+	return rates, nil
+}
+
+func addRate(rates map[string]*big.Rat, currency string, value string) error {
+	if err := checkCurrency(currency); err != nil {
+		return err
+	}
+	rate, ok := new(big.Rat).SetString(value)
+	if !ok || rate.Sign() <= 0 {
+		return fmt.Errorf("the rate of %v must be a positive number, got %q", currency, value)
+	}
+	rates[currency] = rate
+	return nil
+}
+
+func checkCurrency(currency string) error {
+	if err := utils.NewValidator().Var(currency, "iso4217"); err != nil {
+		return fmt.Errorf("%q is not an ISO 4217 currency code", currency)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package money_test
+
+import (
+	"ProductService/money"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeRates(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadStaticProvider(t *testing.T) {
+	files := map[string]string{
+		"rates.json": `{"base": "USD", "rates": {"EUR": 0.92, "GBP": "0.8"}}`,
+		"rates.csv":  "currency,rate\nUSD,1\nEUR,0.92\nGBP,0.8\n",
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			provider, err := money.LoadStaticProvider(writeRates(t, name, content))
+			assert.NoError(t, err)
+			assert.Equal(t, 3, provider.Currencies())
+
+			for _, tt := range []struct {
+				from, to string
+				want     *big.Rat
+			}{
+				{"USD", "EUR", big.NewRat(23, 25)},
+				{"EUR", "USD", big.NewRat(25, 23)},
+				{"EUR", "GBP", big.NewRat(20, 23)},
+				{"GBP", "GBP", big.NewRat(1, 1)},
+			} {
+				rate, err := provider.Rate(context.Background(), tt.from, tt.to)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.String(), rate.String(), tt.from+" to "+tt.to)
+			}
+
+			_, err = provider.Rate(context.Background(), "USD", "JPY")
+			assert.True(t, errors.Is(err, money.ErrRateNotFound))
+		})
+	}
+}
+
+func TestLoadStaticProvider_Invalid(t *testing.T) {
+	files := map[string]string{
+		"rates.txt":  "currency,rate\nUSD,1\n",
+		"rates.json": `{"base": "USD", "rates": {"EUR": -1}}`,
+		"base.json":  `{"base": "USD", "rates": {"USD": 2}}`,
+		"code.json":  `{"base": "USD", "rates": {"EURO": 0.9}}`,
+		"rates.csv":  "USD,1\nEUR,0.92\n",
+		"value.csv":  "currency,rate\nEUR,abc\n",
+	}
+
+	for name, content := range files {
+		_, err := money.LoadStaticProvider(writeRates(t, name, content))
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// AdjustStock serves POST /products/{id}/inventory/adjustments, it adds stock to a warehouse or writes it off.
+// Stock can't be written off below what is reserved.
+type AdjustStock struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewAdjustStock(redis db.CacheInterface, pgdb db.DBOperations) *AdjustStock {
+	return &AdjustStock{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *AdjustStock) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered AdjustStock Decode")
+	var format *models.StockAdjustmentRequest
+	err := json.Unmarshal(data, &format)
+	if err != nil {
+		slog.Warn("Error in decoding request", "error", err)
+		return nil, err
+	}
+	slog.Debug("Exit AdjustStock Decode")
+	return format, nil
+}
+
+func (b *AdjustStock) Validate(v interface{}) error {
+	slog.Debug("Entered AdjustStock Validate")
+	var validate = utils.NewValidator()
+	if e := validate.Struct(v); e != nil {
+		slog.Warn("Error in validating request", "error", e)
+		return e
+	}
+	slog.Debug("Exit AdjustStock Validate")
+	return nil
+}
+
+func (b *AdjustStock) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered AdjustStock ProcessMsg")
+	productId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return inventoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+	request := v.(*models.StockAdjustmentRequest)
+	request.ApplyDefaults()
+
+	level, err := b.PGDBConnector.AdjustStockLevel(ctx, productId, request.Warehouse, request.Delta)
+	if err != nil {
+		return inventoryFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting AdjustStock ProcessMsg")
+	return inventoryResult(enum.SuccessCode, enum.SuccessMessage, "Stock adjusted successfully", level), nil
+}
+
+func (b *AdjustStock) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered AdjustStock Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit AdjustStock Encode")
+	return data, statusCode, nil
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// Batch modes, selected with the mode query parameter of POST /products:batch
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+)
+
+// DefaultMaxBatchSize is the largest number of operations accepted in one batch
+const DefaultMaxBatchSize = 1000
+
+// BatchProducts runs a list of create, update and delete operations. In atomic mode (the default)
+// they run in one transaction and either all of them apply or none does, in best_effort mode every
+// operation is applied on its own. Each operation gets its own status in the response.
+type BatchProducts struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+	CacheSync      *db.CacheSync
+	MaxBatchSize   int
+}
+
+func NewBatchProducts(redis db.CacheInterface, pgdb db.DBOperations) *BatchProducts {
+	return &BatchProducts{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+		CacheSync:      db.NewCacheSync(redis),
+		MaxBatchSize:   DefaultMaxBatchSize,
+	}
+}
+
+func (b *BatchProducts) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered BatchProducts Decode")
+	var format *models.BatchRequest
+	err := json.Unmarshal(data, &format)
+	if err != nil {
+		slog.Warn("Error in decoding request", "error", err)
+		return nil, err
+	}
+	slog.Debug("Exit BatchProducts Decode")
+	return format, nil
+}
+
+// Validate only checks the batch itself, operations are validated one by one in ProcessMsg
+// so that a bad item only fails on its own in best_effort mode
+func (b *BatchProducts) Validate(v interface{}) error {
+	slog.Debug("Entered BatchProducts Validate")
+	format, ok := v.(*models.BatchRequest)
+	if !ok || format == nil || len(format.Operations) == 0 {
+		return errors.New("operations must contain at least one operation")
+	}
+	slog.Debug("Exit BatchProducts Validate")
+	return nil
+}
+
+func (b *BatchProducts) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered BatchProducts ProcessMsg")
+	batch := v.(*models.BatchRequest)
+
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = BatchModeAtomic
+	}
+	if mode != BatchModeAtomic && mode != BatchModeBestEffort {
+		description := fmt.Sprintf("mode must be %v or %v", BatchModeAtomic, BatchModeBestEffort)
+		return batchResult(enum.FailureCode400, enum.FailureMessage400, description, nil), nil
+	}
+	atomic := mode == BatchModeAtomic
+
+	if len(batch.Operations) > b.MaxBatchSize {
+		description := fmt.Sprintf("A batch can contain at most %d operations", b.MaxBatchSize)
+		return batchResult(enum.FailureCode413, enum.FailureMessage413, description, nil), nil
+	}
+
+	response := &models.BatchResponse{Mode: mode, Results: make([]models.BatchItemResult, len(batch.Operations))}
+	validate := utils.NewValidator()
+	var operations []models.BatchOperation
+	var indexes []int
+	for i, operation := range batch.Operations {
+		response.Results[i] = models.BatchItemResult{Index: i, Op: operation.Op, ID: operation.ID}
+		if err := validateBatchOperation(validate, &operation); err != nil {
+			response.Results[i].Status = http.StatusBadRequest
+			response.Results[i].Error = err.Error()
+			continue
+		}
+		operations = append(operations, operation)
+		indexes = append(indexes, i)
+	}
+
+	if atomic && len(operations) < len(batch.Operations) {
+		failed := firstFailure(response.Results)
+		markNotApplied(response.Results)
+		return b.batchFailure(response, failed, enum.FailureCode400, enum.FailureMessage400), nil
+	}
+
+	var outcomes []db.BatchOutcome
+	var err error
+	if len(operations) > 0 {
+		outcomes, err = b.PGDBConnector.ExecBatch(ctx, operations, atomic)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Batch transaction failed", "error", err)
+		markNotApplied(response.Results)
+		code, status, description := dbFailure(err)
+		response.Failed = len(response.Results)
+		return batchResult(code, status, description, response), nil
+	}
+
+	var evict []string
+	failed := -1
+	for j, outcome := range outcomes {
+		item := &response.Results[indexes[j]]
+		if outcome.Err != nil {
+			item.Status, item.Error = batchItemFailure(outcome.Err)
+			failed = indexes[j]
+			continue
+		}
+		switch item.Op {
+		case models.BatchCreate:
+			item.Status = http.StatusCreated
+			item.ID = outcome.Product.ID
+			item.Version = outcome.Product.Version
+		case models.BatchUpdate:
+			item.Status = http.StatusOK
+			item.Version = outcome.Product.Version
+			evict = append(evict, strconv.Itoa(item.ID))
+		case models.BatchDelete:
+			item.Status = http.StatusOK
+			evict = append(evict, strconv.Itoa(item.ID))
+		}
+	}
+
+	if atomic && failed >= 0 {
+		// the transaction was rolled back, nothing was written
+		markNotApplied(response.Results)
+		code := strconv.Itoa(response.Results[failed].Status)
+		return b.batchFailure(response, failed, code, http.StatusText(response.Results[failed].Status)), nil
+	}
+
+	// the writes are committed, the cache has to follow even if the client has gone away
+	b.CacheSync.EvictAll(context.WithoutCancel(ctx), evict)
+
+	for _, item := range response.Results {
+		if item.Status < http.StatusBadRequest {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+	slog.DebugContext(ctx, "Exiting BatchProducts ProcessMsg", "succeeded", response.Succeeded, "failed", response.Failed)
+	description := fmt.Sprintf("%d of %d operations applied", response.Succeeded, len(response.Results))
+	return batchResult(enum.SuccessCode, enum.SuccessMessage, description, response), nil
+}
+
+func (b *BatchProducts) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered BatchProducts Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit BatchProducts Encode")
+	return data, statusCode, nil
+}
+
+// validateBatchOperation checks one operation, the product fields are checked with the rules of the
+// matching single request. Defaults of a create are filled in on operation.
+func validateBatchOperation(validate *validator.Validate, operation *models.BatchOperation) error {
+	if err := validate.Struct(operation); err != nil {
+		return err
+	}
+	switch operation.Op {
+	case models.BatchCreate:
+		request := operation.CreateRequest()
+		request.ApplyDefaults()
+		operation.Currency, operation.Status = request.Currency, request.Status
+		return validate.Struct(request)
+	case models.BatchUpdate:
+		return validate.Struct(operation.UpdateRequest())
+	}
+	return nil
+}
+
+// batchFailure answers an atomic batch that was not applied, the status is the one of the failed operation
+func (b *BatchProducts) batchFailure(response *models.BatchResponse, failed int, code string, status string) models.Result {
+	response.Failed = len(response.Results)
+	description := fmt.Sprintf("Operation %d failed, no operation was applied: %v", failed, response.Results[failed].Error)
+	return batchResult(code, status, description, response)
+}
+
+// batchItemFailure maps the error of one operation to its status and message
+func batchItemFailure(err error) (int, string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound, "Product not found"
+	case errors.Is(err, db.ErrVersionMismatch):
+		return http.StatusPreconditionFailed, errPreconditionFailed.Error()
+	}
+	code, _, description := dbFailure(err)
+	return httpStatus(code), description
+}
+
+func firstFailure(results []models.BatchItemResult) int {
+	for i, item := range results {
+		if item.Status >= http.StatusBadRequest {
+			return i
+		}
+	}
+	return -1
+}
+
+// markNotApplied marks every operation that did not fail itself as not applied, for an atomic batch that was rolled back
+func markNotApplied(results []models.BatchItemResult) {
+	for i := range results {
+		if results[i].Status >= http.StatusBadRequest {
+			continue
+		}
+		results[i].Status = http.StatusFailedDependency
+		results[i].Error = "not applied, the batch was rolled back"
+		results[i].Version = 0
+		if results[i].Op == models.BatchCreate {
+			results[i].ID = 0
+		}
+	}
+}
+
+func batchResult(code string, status string, description string, body interface{}) models.Result {
+	return models.Result{
+		ResponseCode:        code,
+		ResponseStatus:      status,
+		ResponseDescription: description,
+		ResponseBody:        body,
+	}
+}
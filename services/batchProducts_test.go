@@ -0,0 +1,191 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"database/sql"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func decodeBatch(t *testing.T, service *services.BatchProducts, body string) *models.BatchRequest {
+	format, err := service.Decode([]byte(body))
+	assert.NoError(t, err)
+	assert.NoError(t, service.Validate(format))
+	return format.(*models.BatchRequest)
+}
+
+func batchBody(t *testing.T, resp interface{}) *models.BatchResponse {
+	body, ok := resp.(models.Result).ResponseBody.(*models.BatchResponse)
+	assert.True(t, ok)
+	return body
+}
+
+func batchStatuses(body *models.BatchResponse) []int {
+	var statuses []int
+	for _, item := range body.Results {
+		statuses = append(statuses, item.Status)
+	}
+	return statuses
+}
+
+const mixedBatch = `{"operations":[
+	{"op":"create","sku":"PH-1","name":"Phone","price":100},
+	{"op":"update","id":2,"sku":"TB-1","name":"Tablet","price":200,"currency":"EUR","status":"active"},
+	{"op":"delete","id":3}
+]}`
+
+func TestBatchProducts_Validate_Empty(t *testing.T) {
+	service := services.NewBatchProducts(new(mocks.MockCacheInterface), new(mocks.MockDBOperations))
+
+	format, err := service.Decode([]byte(`{"operations":[]}`))
+
+	assert.NoError(t, err)
+	assert.Error(t, service.Validate(format))
+}
+
+func TestBatchProducts_ProcessMsg_AtomicSuccess(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewBatchProducts(mockCache, mockDB)
+
+	mockDB.On("ExecBatch", mock.Anything, mock.Anything, true).Return([]db.BatchOutcome{
+		{Product: &models.Product{ID: 10, Name: "Phone", Price: 100, Version: 1}},
+		{Product: &models.Product{ID: 2, Name: "Tablet", Price: 200, Version: 5}},
+		{},
+	}, nil)
+	mockCache.On("DeleteProductsFromCache", mock.Anything, []string{"2", "3"}).Return(nil).Once()
+
+	resp, err := service.ProcessMsg(decodeBatch(t, service, mixedBatch), httptest.NewRequest("POST", "/products:batch", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, resp.(models.Result).ResponseCode)
+	body := batchBody(t, resp)
+	assert.Equal(t, services.BatchModeAtomic, body.Mode)
+	assert.Equal(t, 3, body.Succeeded)
+	assert.Equal(t, []models.BatchItemResult{
+		{Index: 0, Op: "create", ID: 10, Version: 1, Status: http.StatusCreated},
+		{Index: 1, Op: "update", ID: 2, Version: 5, Status: http.StatusOK},
+		{Index: 2, Op: "delete", ID: 3, Status: http.StatusOK},
+	}, body.Results)
+	mockCache.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+}
+
+func TestBatchProducts_ProcessMsg_AtomicRollback(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewBatchProducts(mockCache, mockDB)
+
+	// the update fails, so the transaction stops there and the delete is never attempted
+	mockDB.On("ExecBatch", mock.Anything, mock.Anything, true).Return([]db.BatchOutcome{
+		{Product: &models.Product{ID: 10, Name: "Phone", Price: 100, Version: 1}},
+		{Err: sql.ErrNoRows},
+	}, nil)
+
+	resp, err := service.ProcessMsg(decodeBatch(t, service, mixedBatch), httptest.NewRequest("POST", "/products:batch", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode404, resp.(models.Result).ResponseCode)
+	body := batchBody(t, resp)
+	assert.Equal(t, []int{http.StatusFailedDependency, http.StatusNotFound, http.StatusFailedDependency}, batchStatuses(body))
+	assert.Zero(t, body.Results[0].ID)
+	assert.Equal(t, 0, body.Succeeded)
+	mockCache.AssertNotCalled(t, "DeleteProductsFromCache", mock.Anything, mock.Anything)
+}
+
+func TestBatchProducts_ProcessMsg_AtomicInvalidItemSkipsDB(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewBatchProducts(new(mocks.MockCacheInterface), mockDB)
+
+	body := `{"operations":[
+		{"op":"create","sku":"PH-1","name":"Phone","price":100},
+		{"op":"update","sku":"TB-1","name":"Tablet","price":200,"currency":"EUR","status":"active"},
+		{"op":"rename","id":1}
+	]}`
+	resp, err := service.ProcessMsg(decodeBatch(t, service, body), httptest.NewRequest("POST", "/products:batch", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode400, resp.(models.Result).ResponseCode)
+	assert.Equal(t, []int{http.StatusFailedDependency, http.StatusBadRequest, http.StatusBadRequest}, batchStatuses(batchBody(t, resp)))
+	mockDB.AssertNotCalled(t, "ExecBatch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBatchProducts_ProcessMsg_BestEffort(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewBatchProducts(mockCache, mockDB)
+
+	body := `{"operations":[
+		{"op":"create","sku":"PH-1","name":"Phone","price":-1},
+		{"op":"update","id":2,"sku":"TB-1","name":"Tablet","price":200,"currency":"EUR","status":"active","version":4},
+		{"op":"delete","id":3}
+	]}`
+	valid := []models.BatchOperation{
+		{Op: "update", ID: 2, SKU: "TB-1", Name: "Tablet", Price: 200, Currency: "EUR", Status: "active", Version: 4},
+		{Op: "delete", ID: 3},
+	}
+	mockDB.On("ExecBatch", mock.Anything, valid, false).Return([]db.BatchOutcome{
+		{Err: db.ErrVersionMismatch},
+		{},
+	}, nil)
+	mockCache.On("DeleteProductsFromCache", mock.Anything, []string{"3"}).Return(nil).Once()
+
+	resp, err := service.ProcessMsg(decodeBatch(t, service, body), httptest.NewRequest("POST", "/products:batch?mode=best_effort", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, resp.(models.Result).ResponseCode)
+	result := batchBody(t, resp)
+	assert.Equal(t, []int{http.StatusBadRequest, http.StatusPreconditionFailed, http.StatusOK}, batchStatuses(result))
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+	mockCache.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+}
+
+func TestBatchProducts_ProcessMsg_CommitFails(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewBatchProducts(mockCache, mockDB)
+
+	mockDB.On("ExecBatch", mock.Anything, mock.Anything, true).Return([]db.BatchOutcome{{}, {}, {}}, errors.New("commit failed"))
+
+	resp, err := service.ProcessMsg(decodeBatch(t, service, mixedBatch), httptest.NewRequest("POST", "/products:batch", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode500, resp.(models.Result).ResponseCode)
+	assert.Equal(t, []int{http.StatusFailedDependency, http.StatusFailedDependency, http.StatusFailedDependency}, batchStatuses(batchBody(t, resp)))
+	mockCache.AssertNotCalled(t, "DeleteProductsFromCache", mock.Anything, mock.Anything)
+}
+
+func TestBatchProducts_ProcessMsg_Limits(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		code string
+	}{
+		{"too many operations", "/products:batch", enums.FailureCode413},
+		{"unknown mode", "/products:batch?mode=sometimes", enums.FailureCode400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewBatchProducts(new(mocks.MockCacheInterface), mockDB)
+			service.MaxBatchSize = 2
+
+			resp, err := service.ProcessMsg(decodeBatch(t, service, mixedBatch), httptest.NewRequest("POST", tt.url, nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, resp.(models.Result).ResponseCode)
+			mockDB.AssertNotCalled(t, "ExecBatch", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
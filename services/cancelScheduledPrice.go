@@ -0,0 +1,87 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// CancelScheduledPrice serves DELETE /products/{id}/scheduled-prices/{scheduleId}. Only pending changes can be
+// canceled, the scheduled price is kept with the canceled status.
+type CancelScheduledPrice struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewCancelScheduledPrice(redis db.CacheInterface, pgdb db.DBOperations) *CancelScheduledPrice {
+	return &CancelScheduledPrice{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *CancelScheduledPrice) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered CancelScheduledPrice Decode")
+	slog.Debug("Exit CancelScheduledPrice Decode")
+	return nil, nil
+}
+
+func (b *CancelScheduledPrice) Validate(v interface{}) error {
+	slog.Debug("Entered CancelScheduledPrice Validate")
+	slog.Debug("Exit CancelScheduledPrice Validate")
+	return nil
+}
+
+func (b *CancelScheduledPrice) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered CancelScheduledPrice ProcessMsg")
+	vars := mux.Vars(r)
+	productId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return scheduleResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+	scheduleId, err := strconv.Atoi(vars["scheduleId"])
+	if err != nil {
+		return scheduleResult(enum.FailureCode400, enum.FailureMessage400, "Invalid scheduled price ID", nil), nil
+	}
+
+	if err := b.PGDBConnector.CancelScheduledPrice(ctx, productId, scheduleId); err != nil {
+		return scheduleFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting CancelScheduledPrice ProcessMsg")
+	return scheduleResult(enum.SuccessCode, enum.SuccessMessage, "Scheduled price canceled successfully", nil), nil
+}
+
+func (b *CancelScheduledPrice) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered CancelScheduledPrice Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit CancelScheduledPrice Encode")
+	return data, statusCode, nil
+}
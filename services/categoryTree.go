@@ -0,0 +1,113 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// CategoryTreeTTL bounds how long a cached tree can be stale if an invalidation is lost
+const CategoryTreeTTL = 10 * time.Minute
+
+// BuildCategoryTree nests the flat category list under the parents, keeping the order of the list.
+// Categories whose parent is missing from the list are treated as top level.
+func BuildCategoryTree(categories []*models.Category) []*models.CategoryNode {
+	nodes := make(map[int]*models.CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &models.CategoryNode{Category: *category, Children: []*models.CategoryNode{}}
+	}
+
+	tree := []*models.CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID != nil {
+			if parent, ok := nodes[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		tree = append(tree, node)
+	}
+	return tree
+}
+
+// loadCategoryTree returns the tree from the cache, building and caching it from postgres on a miss.
+// Cache failures only cost the extra query, the tree is still served from the DB.
+func loadCategoryTree(ctx context.Context, cache db.CacheInterface, pgdb db.DBOperations) ([]*models.CategoryNode, error) {
+	if tree := cachedCategoryTree(ctx, cache); tree != nil {
+		return tree, nil
+	}
+	return rebuildCategoryTree(ctx, cache, pgdb)
+}
+
+// loadCategory returns the node of the category, nil when there is no such category. A reader that rebuilt the tree
+// just before a category was created can cache it after the invalidation, so a miss in a cached tree is checked
+// once against postgres before the category is reported missing.
+func loadCategory(ctx context.Context, cache db.CacheInterface, pgdb db.DBOperations, id int) (*models.CategoryNode, error) {
+	if tree := cachedCategoryTree(ctx, cache); tree != nil {
+		if node := findCategory(tree, id); node != nil {
+			return node, nil
+		}
+		slog.DebugContext(ctx, "Category not in cached tree, reloading", "id", id)
+	}
+	tree, err := rebuildCategoryTree(ctx, cache, pgdb)
+	if err != nil {
+		return nil, err
+	}
+	return findCategory(tree, id), nil
+}
+
+// cachedCategoryTree returns the cached tree, nil when it isn't cached or the cache failed
+func cachedCategoryTree(ctx context.Context, cache db.CacheInterface) []*models.CategoryNode {
+	tree, err := cache.GetCategoryTree(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read category tree from cache", "error", err)
+		return nil
+	}
+	return tree
+}
+
+// rebuildCategoryTree builds the tree from postgres and caches it
+func rebuildCategoryTree(ctx context.Context, cache db.CacheInterface, pgdb db.DBOperations) ([]*models.CategoryNode, error) {
+	categories, err := pgdb.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tree := BuildCategoryTree(categories)
+	if err := cache.SetCategoryTree(ctx, tree, CategoryTreeTTL); err != nil {
+		slog.WarnContext(ctx, "Failed to store category tree in cache", "error", err)
+	}
+	return tree, nil
+}
+
+// invalidateCategoryTree drops the cached tree after a category write so the next read rebuilds it
+func invalidateCategoryTree(ctx context.Context, cache db.CacheInterface) {
+	// the write is done, the cache has to follow even if the client has gone away
+	if err := cache.DeleteCategoryTree(context.WithoutCancel(ctx)); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete category tree from cache", "error", err)
+	}
+}
+
+// findCategory returns the node with the given id anywhere in the tree, nil if there is none
+func findCategory(tree []*models.CategoryNode, id int) *models.CategoryNode {
+	for _, node := range tree {
+		if node.ID == id {
+			return node
+		}
+		if found := findCategory(node.Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// descendantIDs returns the id of node followed by the ids of its whole subtree
+func descendantIDs(node *models.CategoryNode) []int {
+	ids := []int{node.ID}
+	for _, child := range node.Children {
+		ids = append(ids, descendantIDs(child)...)
+	}
+	return ids
+}
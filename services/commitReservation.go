@@ -0,0 +1,87 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// CommitReservation serves POST /products/{id}/reservations/{reservationId}:commit, it takes the reserved
+// stock out of the warehouse once the order is placed
+type CommitReservation struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewCommitReservation(redis db.CacheInterface, pgdb db.DBOperations) *CommitReservation {
+	return &CommitReservation{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *CommitReservation) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered CommitReservation Decode")
+	slog.Debug("Exit CommitReservation Decode")
+	return nil, nil
+}
+
+func (b *CommitReservation) Validate(v interface{}) error {
+	slog.Debug("Entered CommitReservation Validate")
+	slog.Debug("Exit CommitReservation Validate")
+	return nil
+}
+
+func (b *CommitReservation) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered CommitReservation ProcessMsg")
+	vars := mux.Vars(r)
+	productId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return inventoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+	reservationId, err := strconv.Atoi(vars["reservationId"])
+	if err != nil {
+		return inventoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid reservation ID", nil), nil
+	}
+
+	if err := b.PGDBConnector.CommitReservation(ctx, productId, reservationId); err != nil {
+		return inventoryFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting CommitReservation ProcessMsg")
+	return inventoryResult(enum.SuccessCode, enum.SuccessMessage, "Reservation committed successfully", nil), nil
+}
+
+func (b *CommitReservation) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered CommitReservation Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit CommitReservation Encode")
+	return data, statusCode, nil
+}
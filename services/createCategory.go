@@ -0,0 +1,107 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// CreateCategory adds a category, at the top level or below an existing parent
+type CreateCategory struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewCreateCategory(redis db.CacheInterface, pgdb db.DBOperations) *CreateCategory {
+	return &CreateCategory{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *CreateCategory) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered CreateCategory Decode")
+	var format *models.CategoryRequest
+	err := json.Unmarshal(data, &format)
+	if err != nil {
+		slog.Warn("Error in decoding request", "error", err)
+		return nil, err
+	}
+	slog.Debug("Exit CreateCategory Decode")
+	return format, nil
+}
+
+func (b *CreateCategory) Validate(v interface{}) error {
+	slog.Debug("Entered CreateCategory Validate")
+	var validate = utils.NewValidator()
+	if e := validate.Struct(v); e != nil {
+		slog.Warn("Error in validating request", "error", e)
+		return e
+	}
+	slog.Debug("Exit CreateCategory Validate")
+	return nil
+}
+
+func (b *CreateCategory) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered CreateCategory ProcessMsg")
+	request := v.(*models.CategoryRequest)
+
+	created, err := b.PGDBConnector.CreateCategory(ctx, request)
+	if err != nil {
+		if errors.Is(err, db.ErrCategoryNotFound) {
+			return categoryResult(enum.FailureCode422, enum.FailureMessage422, "Parent category not found", nil), nil
+		}
+		code, status, description := dbFailure(err)
+		return categoryResult(code, status, description, nil), nil
+	}
+	invalidateCategoryTree(ctx, b.RedisConnector)
+
+	slog.DebugContext(ctx, "Exiting CreateCategory ProcessMsg")
+	msg := categoryResult(enum.CreatedCode, enum.CreatedMessage, "Category created successfully", created)
+	msg.Header = http.Header{}
+	msg.Header.Set("Location", fmt.Sprintf("/categories/%d", created.ID))
+	return msg, nil
+}
+
+func (b *CreateCategory) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered CreateCategory Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit CreateCategory Encode")
+	return data, statusCode, nil
+}
+
+func categoryResult(code string, status string, description string, body interface{}) models.Result {
+	return models.Result{
+		ResponseCode:        code,
+		ResponseStatus:      status,
+		ResponseDescription: description,
+		ResponseBody:        body,
+	}
+}
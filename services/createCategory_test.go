@@ -0,0 +1,76 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"context"
+	"errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateCategory_Validate(t *testing.T) {
+	service := services.NewCreateCategory(new(mocks.MockCacheInterface), new(mocks.MockDBOperations))
+
+	for _, body := range []string{`{}`, `{"name":"Phones","parent_id":0}`} {
+		format, err := service.Decode([]byte(body))
+		assert.NoError(t, err)
+		assert.Error(t, service.Validate(format), body)
+	}
+}
+
+func TestCreateCategory_ProcessMsg_Success(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	_ = cache.SetCategoryTree(context.Background(), services.BuildCategoryTree(categoryFixture()), services.CategoryTreeTTL)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewCreateCategory(cache, mockDB)
+
+	request := &models.CategoryRequest{Name: "Tablets", ParentID: parentID(1)}
+	created := &models.Category{ID: 6, ParentID: parentID(1), Name: "Tablets"}
+	mockDB.On("CreateCategory", mock.Anything, request).Return(created, nil)
+
+	resp, err := service.ProcessMsg(request, httptest.NewRequest("POST", "/categories", nil))
+
+	assert.NoError(t, err)
+	result := resp.(models.Result)
+	assert.Equal(t, enums.CreatedCode, result.ResponseCode)
+	assert.Equal(t, created, result.ResponseBody)
+	assert.Equal(t, "/categories/6", result.ResponseHeader().Get("Location"))
+	tree, _ := cache.GetCategoryTree(context.Background())
+	assert.Nil(t, tree)
+	mockDB.AssertExpectations(t)
+}
+
+func TestCreateCategory_ProcessMsg_Failures(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"unknown parent", db.ErrCategoryNotFound, enums.FailureCode422},
+		{"duplicate name", &pq.Error{Code: "23505", Constraint: db.CategoryNameConstraint}, enums.FailureCode409},
+		{"db error", errors.New("db error"), enums.FailureCode500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewCreateCategory(mockCache, mockDB)
+
+			mockDB.On("CreateCategory", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			resp, err := service.ProcessMsg(&models.CategoryRequest{Name: "Phones"}, httptest.NewRequest("POST", "/categories", nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, resp.(models.Result).ResponseCode)
+			mockCache.AssertNotCalled(t, "DeleteCategoryTree", mock.Anything)
+		})
+	}
+}
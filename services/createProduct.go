@@ -3,46 +3,53 @@ package services
 import (
 	"ProductService/db"
 	"ProductService/models"
+	"ProductService/utils"
 	enum "ProductService/utils/enums"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/go-playground/validator/v10"
-	"log"
+	"log/slog"
 	"net/http"
+	"time"
 )
 
 type CreateProduct struct {
 	RedisConnector db.CacheInterface
 	PGDBConnector  db.DBOperations
+	// IdempotencyTTL is how long the response to a request with an Idempotency-Key is replayed
+	IdempotencyTTL time.Duration
 }
 
 func NewCreateProduct(redis db.CacheInterface, pgdb db.DBOperations) *CreateProduct {
 	return &CreateProduct{
 		RedisConnector: redis,
 		PGDBConnector:  pgdb,
+		IdempotencyTTL: DefaultIdempotencyTTL,
 	}
 }
 
 func (b *CreateProduct) Decode(data []byte) (interface{}, error) {
-	log.Printf("Entered CreateProduct Decode")
+	slog.Debug("Entered CreateProduct Decode")
 	var format *models.CreateProductRequest
 	err := json.Unmarshal(data, &format)
 	if err != nil {
-		log.Println(err)
+		slog.Warn("Error in decoding request", "error", err)
 		return nil, err
 	}
-	log.Printf("Exit CreateProduct Decode")
+	if format != nil {
+		format.ApplyDefaults()
+	}
+	slog.Debug("Exit CreateProduct Decode")
 	return format, nil
 }
 
 func (b *CreateProduct) Validate(v interface{}) error {
-	log.Printf("Entered CreateProduct Validate")
+	slog.Debug("Entered CreateProduct Validate")
 	format := v.(*models.CreateProductRequest)
-	var validate = validator.New()
+	var validate = utils.NewValidator()
 	e := validate.Struct(v)
 	if e != nil {
-		log.Println(e)
+		slog.Warn("Error in validating request", "error", e)
 		return e
 	}
 
@@ -50,68 +57,79 @@ func (b *CreateProduct) Validate(v interface{}) error {
 		err := errors.New("mandatory fields are missing in request")
 		return err
 	}
-	log.Printf("Exit CreateProduct Validate")
+	slog.Debug("Exit CreateProduct Validate")
 	return nil
 }
 
 func (b *CreateProduct) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
-	log.Println("Entered CreateProduct ProcessMsg")
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered CreateProduct ProcessMsg")
 
 	product := v.(*models.CreateProductRequest)
 
+	// a retry with the same Idempotency-Key gets the first response instead of a second product
+	idempotent, response := beginIdempotent(ctx, b.RedisConnector, r, product, b.IdempotencyTTL)
+	if response != nil {
+		return response, nil
+	}
+
 	// Create product in database
-	_, err := b.PGDBConnector.CreateProduct(product)
+	created, err := b.PGDBConnector.CreateProduct(ctx, product)
 	if err != nil {
+		code, status, description := dbFailure(err)
 		msg := models.Result{
-			ResponseCode:        enum.FailureCode500,
-			ResponseStatus:      enum.FailureMessage500,
-			ResponseDescription: "Database Error",
+			ResponseCode:        code,
+			ResponseStatus:      status,
+			ResponseDescription: description,
 			ResponseBody:        nil,
 		}
+		// a conflict is replayed like a success, timeouts and server errors release the key
+		idempotent.complete(ctx, msg, b.Encode)
 		return msg, nil
 	}
 
+	header := etagHeader(ETag(created.Version))
+	header.Set("Location", fmt.Sprintf("/products/%d", created.ID))
 	msg := models.Result{
-		ResponseCode:        enum.SuccessCode,
-		ResponseStatus:      enum.SuccessMessage,
+		ResponseCode:        enum.CreatedCode,
+		ResponseStatus:      enum.CreatedMessage,
 		ResponseDescription: "Product created successfully",
-		ResponseBody:        nil,
+		ResponseBody:        created,
+		Header:              header,
 	}
-	log.Println("Exiting CreateProduct ProcessMsg")
+	idempotent.complete(ctx, msg, b.Encode)
+	slog.DebugContext(ctx, "Exiting CreateProduct ProcessMsg")
 	return msg, nil
 }
 
 func (b *CreateProduct) Encode(v interface{}) ([]byte, int, error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Panic occurred: %v", r)
+			slog.Error("Panic occurred", "panic", r)
 		}
 	}()
-	log.Printf("Entered CreateProduct Encode")
+	slog.Debug("Entered CreateProduct Encode")
+
+	if replay, ok := v.(models.IdempotencyRecord); ok {
+		slog.Debug("Exit CreateProduct Encode")
+		return replay.Body, replay.StatusCode, nil
+	}
 
 	format, ok := v.(models.Result)
 	if !ok {
-		log.Printf("Type assertion failed: expected models.Result but got %T", v)
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
 		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
 	}
 
 	data, err := json.Marshal(&format)
 	if err != nil {
-		log.Println("Error in Marshal", err)
+		slog.Error("Error in Marshal", "error", err)
 		return nil, http.StatusInternalServerError, err
 	}
 
 	// Decide HTTP status code based on ResponseCode
-	statusCode := http.StatusOK // default 200
-	switch format.ResponseCode {
-	case "400":
-		statusCode = http.StatusBadRequest
-	case "404":
-		statusCode = http.StatusNotFound
-	case "500":
-		statusCode = http.StatusInternalServerError
-	}
+	statusCode := httpStatus(format.ResponseCode)
 
-	log.Printf("Exit CreateProduct Encode")
+	slog.Debug("Exit CreateProduct Encode")
 	return data, statusCode, nil
 }
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// CreateProductVariant adds a variant to a product, the product's cached copy is evicted since it embeds the variants
+type CreateProductVariant struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+	CacheSync      *db.CacheSync
+}
+
+func NewCreateProductVariant(redis db.CacheInterface, pgdb db.DBOperations) *CreateProductVariant {
+	return &CreateProductVariant{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+		CacheSync:      db.NewCacheSync(redis),
+	}
+}
+
+func (b *CreateProductVariant) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered CreateProductVariant Decode")
+	var format *models.VariantRequest
+	err := json.Unmarshal(data, &format)
+	if err != nil {
+		slog.Warn("Error in decoding request", "error", err)
+		return nil, err
+	}
+	slog.Debug("Exit CreateProductVariant Decode")
+	return format, nil
+}
+
+func (b *CreateProductVariant) Validate(v interface{}) error {
+	slog.Debug("Entered CreateProductVariant Validate")
+	var validate = utils.NewValidator()
+	if e := validate.Struct(v); e != nil {
+		slog.Warn("Error in validating request", "error", e)
+		return e
+	}
+	slog.Debug("Exit CreateProductVariant Validate")
+	return nil
+}
+
+func (b *CreateProductVariant) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered CreateProductVariant ProcessMsg")
+	productId, _, _, failure := variantPath(r)
+	if failure != nil {
+		return *failure, nil
+	}
+	request := v.(*models.VariantRequest)
+	request.ApplyDefaults()
+
+	var created *models.ProductVariant
+	err := b.CacheSync.WriteAndEvict(ctx, func() error {
+		var err error
+		created, err = b.PGDBConnector.CreateProductVariant(ctx, productId, request)
+		return err
+	}, strconv.Itoa(productId))
+	if err != nil {
+		return variantFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting CreateProductVariant ProcessMsg")
+	msg := variantResult(enum.CreatedCode, enum.CreatedMessage, "Variant created successfully", created)
+	msg.Header = http.Header{}
+	msg.Header.Set("Location", fmt.Sprintf("/products/%d/variants/%d", productId, created.ID))
+	return msg, nil
+}
+
+func (b *CreateProductVariant) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered CreateProductVariant Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit CreateProductVariant Encode")
+	return data, statusCode, nil
+}
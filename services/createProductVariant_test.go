@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"context"
+	"database/sql"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateProductVariant_Validate(t *testing.T) {
+	service := services.NewCreateProductVariant(new(mocks.MockCacheInterface), new(mocks.MockDBOperations))
+
+	for body, valid := range map[string]bool{
+		`{"sku":"SHIRT-M","options":{"size":"M"}}`:                            true,
+		`{"sku":"SHIRT-M","options":{"size":"M","color":"red"},"price":12.5}`: true,
+		`{"sku":"SHIRT-M","options":{"size":"M"},"available":false}`:          true,
+		`{"options":{"size":"M"}}`:                                            false,
+		`{"sku":"SHIRT-M"}`:                                                   false,
+		`{"sku":"SHIRT-M","options":{}}`:                                      false,
+		`{"sku":"SHIRT-M","options":{"size":""}}`:                             false,
+		`{"sku":"SHIRT-M","options":{"si ze":"M"}}`:                           false,
+		`{"sku":"SHIRT-M","options":{"size":"M"},"price":0}`:                  false,
+		`{"sku":"SHIRT-M","options":{"size":"M"},"price":-1}`:                 false,
+		`{"sku":"SHIRT Mé","options":{"size":"M"}}`:                           false,
+		`{"sku":"SHIRT-M","options":{"a":"1","b":"1","c":"1","d":"1","e":"1","f":"1","g":"1","h":"1","i":"1","j":"1","k":"1"}}`: false,
+	} {
+		format, err := service.Decode([]byte(body))
+		assert.NoError(t, err)
+		assert.Equal(t, valid, service.Validate(format) == nil, body)
+	}
+}
+
+func TestCreateProductVariant_ProcessMsg_Success(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	_ = cache.SetProductByID(context.Background(), "7", &models.Product{ID: 7}, db.ProductCacheTTL)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewCreateProductVariant(cache, mockDB)
+
+	request := &models.VariantRequest{SKU: "SHIRT-M", Options: models.VariantOptions{"size": "M"}}
+	created := &models.ProductVariant{ID: 4, ProductID: 7, SKU: "SHIRT-M", Options: request.Options, Available: true}
+	mockDB.On("CreateProductVariant", mock.Anything, 7, request).Return(created, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest("POST", "/products/7/variants", nil), map[string]string{"id": "7"})
+	resp, err := service.ProcessMsg(request, req)
+
+	assert.NoError(t, err)
+	result := resp.(models.Result)
+	assert.Equal(t, enums.CreatedCode, result.ResponseCode)
+	assert.Equal(t, created, result.ResponseBody)
+	assert.Equal(t, "/products/7/variants/4", result.Header.Get("Location"))
+	// available defaults to true
+	assert.True(t, *request.Available)
+	// the cached product embeds its variants, so it must be read from the DB again
+	cached, _ := cache.GetProductByID(context.Background(), "7")
+	assert.Nil(t, cached)
+}
+
+func TestCreateProductVariant_ProcessMsg_Failures(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		code        string
+		description string
+	}{
+		{"product not found", sql.ErrNoRows, enums.FailureCode404, "Product not found"},
+		{"duplicate sku", &pq.Error{Code: "23505", Constraint: db.VariantSKUConstraint}, enums.FailureCode409, "A variant with this SKU already exists"},
+		{"duplicate options", &pq.Error{Code: "23505", Constraint: db.VariantOptionsConstraint}, enums.FailureCode409, "The product already has a variant with these options"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := mocks.NewMemoryCache()
+			_ = cache.SetProductByID(context.Background(), "7", &models.Product{ID: 7}, db.ProductCacheTTL)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewCreateProductVariant(cache, mockDB)
+
+			mockDB.On("CreateProductVariant", mock.Anything, 7, mock.Anything).Return(nil, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("POST", "/products/7/variants", nil), map[string]string{"id": "7"})
+			resp, err := service.ProcessMsg(&models.VariantRequest{SKU: "SHIRT-M", Options: models.VariantOptions{"size": "M"}}, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			assert.Equal(t, tt.description, result.ResponseDescription)
+			// nothing was written, the cached product stays
+			cached, _ := cache.GetProductByID(context.Background(), "7")
+			assert.NotNil(t, cached)
+		})
+	}
+}
@@ -1,14 +1,19 @@
 package services_test
 
 import (
+	"ProductService/db"
 	"ProductService/db/mocks"
 	"ProductService/models"
 	"ProductService/services"
 	enum "ProductService/utils/enums"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -16,13 +21,19 @@ import (
 func TestCreateProduct_Decode(t *testing.T) {
 	service := services.NewCreateProduct(nil, nil)
 
-	validPayload := `{"name": "Test Product", "price": 99.99}`
+	validPayload := `{"sku": "TP-1", "name": "Test Product", "price": 99.99}`
 	invalidPayload := `{"name": "Test Product", "price":}`
 
-	// Valid Decode
+	// Valid Decode, currency and status get their defaults
 	v, err := service.Decode([]byte(validPayload))
 	assert.NoError(t, err)
-	assert.NotNil(t, v)
+	assert.Equal(t, &models.CreateProductRequest{
+		SKU:      "TP-1",
+		Name:     "Test Product",
+		Price:    99.99,
+		Currency: models.DefaultCurrency,
+		Status:   models.DefaultStatus,
+	}, v)
 
 	// Invalid Decode
 	v, err = service.Decode([]byte(invalidPayload))
@@ -35,14 +46,19 @@ func TestCreateProduct_Validate(t *testing.T) {
 
 	// Valid input
 	validInput := &models.CreateProductRequest{
-		Name:  "Valid Product",
-		Price: 100.0,
+		SKU:         "VP-1",
+		Name:        "Valid Product",
+		Description: "A valid product",
+		Price:       100.0,
+		Currency:    "EUR",
+		Status:      models.StatusActive,
 	}
 	err := service.Validate(validInput)
 	assert.NoError(t, err)
 
 	// Invalid input - Missing Name
 	invalidInput := &models.CreateProductRequest{
+		SKU:   "VP-1",
 		Name:  "",
 		Price: 100.0,
 	}
@@ -51,11 +67,58 @@ func TestCreateProduct_Validate(t *testing.T) {
 
 	// Invalid input - Price <= 0
 	invalidInput2 := &models.CreateProductRequest{
+		SKU:   "VP-1",
 		Name:  "Invalid Product",
 		Price: 0.0,
 	}
 	err = service.Validate(invalidInput2)
 	assert.Error(t, err)
+
+	// Invalid input - Missing SKU
+	err = service.Validate(&models.CreateProductRequest{Name: "Invalid Product", Price: 10})
+	assert.Error(t, err)
+
+	// Invalid input - Unknown currency and status
+	err = service.Validate(&models.CreateProductRequest{SKU: "VP-1", Name: "Invalid Product", Price: 10, Currency: "EURO"})
+	assert.Error(t, err)
+	err = service.Validate(&models.CreateProductRequest{SKU: "VP-1", Name: "Invalid Product", Price: 10, Status: "deleted"})
+	assert.Error(t, err)
+}
+
+func TestCreateProduct_Validate_AttributesAndTags(t *testing.T) {
+	tooMany := `{`
+	for i := 0; i < 51; i++ {
+		tooMany += fmt.Sprintf(`"k%d": %d,`, i, i)
+	}
+	tooMany = strings.TrimSuffix(tooMany, ",") + `}`
+
+	tests := []struct {
+		name   string
+		fields string
+		valid  bool
+	}{
+		{"scalar values", `"attributes": {"color": "red", "size_cm": 42.5, "wireless": true}, "tags": ["sale", "new"]`, true},
+		{"empty", `"attributes": {}, "tags": []`, true},
+		{"nested object", `"attributes": {"dimensions": {"w": 1}}`, false},
+		{"array value", `"attributes": {"colors": ["red"]}`, false},
+		{"null value", `"attributes": {"color": null}`, false},
+		{"invalid key", `"attributes": {"co lor": "red"}`, false},
+		{"value too long", `"attributes": {"color": "` + strings.Repeat("r", 501) + `"}`, false},
+		{"too many attributes", `"attributes": ` + tooMany, false},
+		{"duplicate tags", `"tags": ["sale", "sale"]`, false},
+		{"empty tag", `"tags": [""]`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewCreateProduct(nil, nil)
+
+			format, err := service.Decode([]byte(`{"sku": "VP-1", "name": "Valid Product", "price": 10, ` + tt.fields + `}`))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.valid, service.Validate(format) == nil)
+		})
+	}
 }
 
 func TestCreateProduct_ProcessMsg_Success(t *testing.T) {
@@ -67,16 +130,24 @@ func TestCreateProduct_ProcessMsg_Success(t *testing.T) {
 		Price: 50.5,
 	}
 
-	mockDB.On("CreateProduct", mock.Anything).Return(1, nil)
+	created := &models.Product{ID: 7, Name: "New Product", Price: 50.5, Version: 1}
+	mockDB.On("CreateProduct", mock.Anything, mockRequest).Return(created, nil)
 
-	resp, err := service.ProcessMsg(mockRequest, nil)
+	resp, err := service.ProcessMsg(mockRequest, httptest.NewRequest("POST", "/products", nil))
 
 	assert.NoError(t, err)
 
 	result, ok := resp.(models.Result)
 	assert.True(t, ok)
-	assert.Equal(t, enum.SuccessCode, result.ResponseCode)
+	assert.Equal(t, enum.CreatedCode, result.ResponseCode)
 	assert.Equal(t, "Product created successfully", result.ResponseDescription)
+	assert.Equal(t, created, result.ResponseBody)
+	assert.Equal(t, "/products/7", result.Header.Get("Location"))
+	assert.Equal(t, `"1"`, result.Header.Get("ETag"))
+
+	_, statusCode, err := service.Encode(result)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
 
 	mockDB.AssertExpectations(t)
 }
@@ -90,9 +161,9 @@ func TestCreateProduct_ProcessMsg_DBError(t *testing.T) {
 		Price: 50.5,
 	}
 
-	mockDB.On("CreateProduct", mock.Anything).Return(0, errors.New("db error"))
+	mockDB.On("CreateProduct", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
 
-	resp, err := service.ProcessMsg(mockRequest, nil)
+	resp, err := service.ProcessMsg(mockRequest, httptest.NewRequest("POST", "/products", nil))
 
 	assert.NoError(t, err)
 
@@ -104,6 +175,22 @@ func TestCreateProduct_ProcessMsg_DBError(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestCreateProduct_ProcessMsg_DuplicateSKU(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewCreateProduct(nil, mockDB)
+
+	mockDB.On("CreateProduct", mock.Anything, mock.Anything).Return(nil, &pq.Error{Code: "23505", Constraint: db.ProductSKUConstraint})
+
+	resp, err := service.ProcessMsg(&models.CreateProductRequest{SKU: "NP-1", Name: "New Product", Price: 50.5}, httptest.NewRequest("POST", "/products", nil))
+
+	assert.NoError(t, err)
+	result := resp.(models.Result)
+	assert.Equal(t, enum.FailureCode409, result.ResponseCode)
+	assert.Equal(t, "A product with this SKU already exists", result.ResponseDescription)
+
+	mockDB.AssertExpectations(t)
+}
+
 func TestCreateProduct_Encode(t *testing.T) {
 	service := services.NewCreateProduct(nil, nil)
 
@@ -130,3 +217,141 @@ func TestCreateProduct_Encode_TypeAssertionFail(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, statusCode)
 	assert.Nil(t, data)
 }
+
+func newIdempotentRequest(key string) *http.Request {
+	req := httptest.NewRequest("POST", "/products", nil)
+	req.Header.Set(services.IdempotencyKeyHeader, key)
+	return req
+}
+
+func TestCreateProduct_ProcessMsg_IdempotencyKey_ReplaysResponse(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewCreateProduct(cache, mockDB)
+
+	mockDB.On("CreateProduct", mock.Anything, mock.Anything).Return(&models.Product{ID: 1, Name: "New Product", Price: 50.5, Version: 1}, nil).Once()
+
+	first, err := service.ProcessMsg(&models.CreateProductRequest{Name: "New Product", Price: 50.5}, newIdempotentRequest("key-1"))
+	assert.NoError(t, err)
+	firstData, firstStatus, _ := service.Encode(first)
+
+	retry, err := service.ProcessMsg(&models.CreateProductRequest{Name: "New Product", Price: 50.5}, newIdempotentRequest("key-1"))
+	assert.NoError(t, err)
+	retryData, retryStatus, err := service.Encode(retry)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, firstStatus)
+	assert.Equal(t, firstStatus, retryStatus)
+	assert.Equal(t, firstData, retryData)
+	replay, ok := retry.(models.IdempotencyRecord)
+	assert.True(t, ok)
+	assert.Equal(t, "true", replay.ResponseHeader().Get("Idempotent-Replayed"))
+	assert.Equal(t, "/products/1", replay.ResponseHeader().Get("Location"))
+	mockDB.AssertNumberOfCalls(t, "CreateProduct", 1)
+}
+
+func TestCreateProduct_ProcessMsg_IdempotencyKey_DifferentBody(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewCreateProduct(cache, mockDB)
+
+	mockDB.On("CreateProduct", mock.Anything, mock.Anything).Return(&models.Product{ID: 1, Name: "New Product", Price: 50.5, Version: 1}, nil).Once()
+
+	_, err := service.ProcessMsg(&models.CreateProductRequest{Name: "New Product", Price: 50.5}, newIdempotentRequest("key-1"))
+	assert.NoError(t, err)
+
+	resp, err := service.ProcessMsg(&models.CreateProductRequest{Name: "New Product", Price: 60}, newIdempotentRequest("key-1"))
+
+	assert.NoError(t, err)
+	result := resp.(models.Result)
+	assert.Equal(t, enum.FailureCode422, result.ResponseCode)
+	_, statusCode, _ := service.Encode(result)
+	assert.Equal(t, http.StatusUnprocessableEntity, statusCode)
+	mockDB.AssertNumberOfCalls(t, "CreateProduct", 1)
+}
+
+func TestCreateProduct_ProcessMsg_IdempotencyKey_InFlight(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewCreateProduct(cache, mockDB)
+
+	// the retry arrives while the first request is still inserting
+	var retry interface{}
+	mockDB.On("CreateProduct", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		retry, _ = service.ProcessMsg(&models.CreateProductRequest{Name: "New Product", Price: 50.5}, newIdempotentRequest("key-1"))
+	}).Return(&models.Product{ID: 1, Name: "New Product", Price: 50.5, Version: 1}, nil).Once()
+
+	_, err := service.ProcessMsg(&models.CreateProductRequest{Name: "New Product", Price: 50.5}, newIdempotentRequest("key-1"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, enum.FailureCode409, retry.(models.Result).ResponseCode)
+	mockDB.AssertNumberOfCalls(t, "CreateProduct", 1)
+}
+
+func TestCreateProduct_ProcessMsg_IdempotencyKey_ReleasedOnDBError(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewCreateProduct(cache, mockDB)
+
+	mockDB.On("CreateProduct", mock.Anything, mock.Anything).Return(nil, errors.New("db error")).Once()
+	mockDB.On("CreateProduct", mock.Anything, mock.Anything).Return(&models.Product{ID: 1, Name: "New Product", Price: 50.5, Version: 1}, nil).Once()
+
+	resp, err := service.ProcessMsg(&models.CreateProductRequest{Name: "New Product", Price: 50.5}, newIdempotentRequest("key-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, enum.FailureCode500, resp.(models.Result).ResponseCode)
+
+	resp, err = service.ProcessMsg(&models.CreateProductRequest{Name: "New Product", Price: 50.5}, newIdempotentRequest("key-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, enum.CreatedCode, resp.(models.Result).ResponseCode)
+	mockDB.AssertNumberOfCalls(t, "CreateProduct", 2)
+}
+
+func TestCreateProduct_ProcessMsg_IdempotencyKey_ReplaysConflict(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewCreateProduct(cache, mockDB)
+
+	mockDB.On("CreateProduct", mock.Anything, mock.Anything).Return(nil, &pq.Error{Code: "23505", Constraint: db.ProductSKUConstraint}).Once()
+
+	first, err := service.ProcessMsg(&models.CreateProductRequest{SKU: "MOU-001", Name: "New Product", Price: 50.5}, newIdempotentRequest("key-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, enum.FailureCode409, first.(models.Result).ResponseCode)
+	firstData, _, _ := service.Encode(first)
+
+	retry, err := service.ProcessMsg(&models.CreateProductRequest{SKU: "MOU-001", Name: "New Product", Price: 50.5}, newIdempotentRequest("key-1"))
+	assert.NoError(t, err)
+	retryData, retryStatus, err := service.Encode(retry)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, retryStatus)
+	assert.Equal(t, firstData, retryData)
+	replay, ok := retry.(models.IdempotencyRecord)
+	assert.True(t, ok)
+	assert.Equal(t, "true", replay.ResponseHeader().Get("Idempotent-Replayed"))
+	mockDB.AssertNumberOfCalls(t, "CreateProduct", 1)
+}
+
+func TestCreateProduct_ProcessMsg_IdempotencyKey_CacheDown(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewCreateProduct(mockCache, mockDB)
+
+	mockCache.On("ReserveIdempotencyKey", mock.Anything, "key-1", mock.Anything, mock.Anything).Return(nil, false, errors.New("redis down"))
+
+	resp, err := service.ProcessMsg(&models.CreateProductRequest{Name: "New Product", Price: 50.5}, newIdempotentRequest("key-1"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, enum.FailureCode503, resp.(models.Result).ResponseCode)
+	mockDB.AssertNotCalled(t, "CreateProduct", mock.Anything, mock.Anything)
+}
+
+func TestCreateProduct_ProcessMsg_IdempotencyKey_Invalid(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewCreateProduct(new(mocks.MockCacheInterface), mockDB)
+
+	resp, err := service.ProcessMsg(&models.CreateProductRequest{Name: "New Product", Price: 50.5}, newIdempotentRequest("key with spaces"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, enum.FailureCode400, resp.(models.Result).ResponseCode)
+	mockDB.AssertNotCalled(t, "CreateProduct", mock.Anything, mock.Anything)
+}
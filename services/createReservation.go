@@ -0,0 +1,107 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultReservationTTL is how long a reservation holds stock unless the config or the request says otherwise
+const DefaultReservationTTL = 15 * time.Minute
+
+// CreateReservation serves POST /products/{id}/reservations, it holds available stock for a checkout
+// until the reservation is committed, released or expires. Asking for more than is available returns 409.
+type CreateReservation struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+	// TTL is how long a reservation holds the stock when the request doesn't say
+	TTL time.Duration
+}
+
+func NewCreateReservation(redis db.CacheInterface, pgdb db.DBOperations) *CreateReservation {
+	return &CreateReservation{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+		TTL:            DefaultReservationTTL,
+	}
+}
+
+func (b *CreateReservation) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered CreateReservation Decode")
+	var format *models.ReservationRequest
+	err := json.Unmarshal(data, &format)
+	if err != nil {
+		slog.Warn("Error in decoding request", "error", err)
+		return nil, err
+	}
+	slog.Debug("Exit CreateReservation Decode")
+	return format, nil
+}
+
+func (b *CreateReservation) Validate(v interface{}) error {
+	slog.Debug("Entered CreateReservation Validate")
+	var validate = utils.NewValidator()
+	if e := validate.Struct(v); e != nil {
+		slog.Warn("Error in validating request", "error", e)
+		return e
+	}
+	slog.Debug("Exit CreateReservation Validate")
+	return nil
+}
+
+func (b *CreateReservation) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered CreateReservation ProcessMsg")
+	productId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return inventoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+	request := v.(*models.ReservationRequest)
+	ttl := b.TTL
+	if request.TTLSeconds > 0 {
+		ttl = time.Duration(request.TTLSeconds) * time.Second
+	}
+
+	reservation, err := b.PGDBConnector.ReserveStock(ctx, productId, request.Warehouse, request.Quantity, time.Now().Add(ttl))
+	if err != nil {
+		return inventoryFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting CreateReservation ProcessMsg")
+	return inventoryResult(enum.CreatedCode, enum.CreatedMessage, "Stock reserved successfully", reservation), nil
+}
+
+func (b *CreateReservation) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered CreateReservation Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit CreateReservation Encode")
+	return data, statusCode, nil
+}
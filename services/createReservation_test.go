@@ -0,0 +1,107 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"database/sql"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateReservation_Validate(t *testing.T) {
+	service := services.NewCreateReservation(new(mocks.MockCacheInterface), new(mocks.MockDBOperations))
+
+	for body, valid := range map[string]bool{
+		`{"quantity":2}`: true,
+		`{"quantity":2,"warehouse":"berlin","ttl_seconds":60}`: true,
+		`{}`:                                   false,
+		`{"quantity":0}`:                       false,
+		`{"quantity":2,"ttl_seconds":86401}`:   false,
+		`{"quantity":2,"warehouse":"ber lin"}`: false,
+	} {
+		format, err := service.Decode([]byte(body))
+		assert.NoError(t, err)
+		assert.Equal(t, valid, service.Validate(format) == nil, body)
+	}
+}
+
+func TestCreateReservation_ProcessMsg(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *models.ReservationRequest
+		ttl     time.Duration
+		err     error
+		code    string
+	}{
+		{"default ttl", &models.ReservationRequest{Quantity: 2}, 5 * time.Minute, nil, enums.CreatedCode},
+		{"requested ttl", &models.ReservationRequest{Quantity: 2, Warehouse: "berlin", TTLSeconds: 60}, time.Minute, nil, enums.CreatedCode},
+		{"insufficient stock", &models.ReservationRequest{Quantity: 2}, 5 * time.Minute, db.ErrInsufficientStock, enums.FailureCode409},
+		{"product not found", &models.ReservationRequest{Quantity: 2}, 5 * time.Minute, sql.ErrNoRows, enums.FailureCode404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewCreateReservation(new(mocks.MockCacheInterface), mockDB)
+			service.TTL = 5 * time.Minute
+
+			reservation := &models.Reservation{ID: 3, ProductID: 7, Warehouse: "berlin", Quantity: 2}
+			start := time.Now()
+			expiresAt := mock.MatchedBy(func(at time.Time) bool {
+				return !at.Before(start.Add(tt.ttl)) && !at.After(time.Now().Add(tt.ttl))
+			})
+			mockDB.On("ReserveStock", mock.Anything, 7, tt.request.Warehouse, 2, expiresAt).Return(reservation, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("POST", "/products/7/reservations", nil), map[string]string{"id": "7"})
+			resp, err := service.ProcessMsg(tt.request, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			if tt.err == nil {
+				assert.Equal(t, reservation, result.ResponseBody)
+			}
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEndReservation_ProcessMsg(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		err    error
+		code   string
+	}{
+		{"committed", "CommitReservation", nil, enums.SuccessCode},
+		{"commit expired", "CommitReservation", db.ErrReservationNotFound, enums.FailureCode404},
+		{"released", "ReleaseReservation", nil, enums.SuccessCode},
+		{"release unknown", "ReleaseReservation", db.ErrReservationNotFound, enums.FailureCode404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			var service services.ProductMsgProc = services.NewReleaseReservation(new(mocks.MockCacheInterface), mockDB)
+			if tt.method == "CommitReservation" {
+				service = services.NewCommitReservation(new(mocks.MockCacheInterface), mockDB)
+			}
+
+			mockDB.On(tt.method, mock.Anything, 7, 3).Return(tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("POST", "/products/7/reservations/3", nil), map[string]string{"id": "7", "reservationId": "3"})
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, resp.(models.Result).ResponseCode)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CreateScheduledPrice serves POST /products/{id}/scheduled-prices, it queues a price change that the
+// price scheduler applies at effective_from and reverts at effective_to. A window overlapping another
+// pending or active one of the product returns 409.
+type CreateScheduledPrice struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewCreateScheduledPrice(redis db.CacheInterface, pgdb db.DBOperations) *CreateScheduledPrice {
+	return &CreateScheduledPrice{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *CreateScheduledPrice) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered CreateScheduledPrice Decode")
+	var format *models.ScheduledPriceRequest
+	err := json.Unmarshal(data, &format)
+	if err != nil {
+		slog.Warn("Error in decoding request", "error", err)
+		return nil, err
+	}
+	slog.Debug("Exit CreateScheduledPrice Decode")
+	return format, nil
+}
+
+func (b *CreateScheduledPrice) Validate(v interface{}) error {
+	slog.Debug("Entered CreateScheduledPrice Validate")
+	var validate = utils.NewValidator()
+	if e := validate.Struct(v); e != nil {
+		slog.Warn("Error in validating request", "error", e)
+		return e
+	}
+	slog.Debug("Exit CreateScheduledPrice Validate")
+	return nil
+}
+
+func (b *CreateScheduledPrice) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered CreateScheduledPrice ProcessMsg")
+	productId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return scheduleResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+	request := v.(*models.ScheduledPriceRequest)
+	if request.EffectiveTo != nil && !request.EffectiveTo.After(request.EffectiveFrom) {
+		return scheduleResult(enum.FailureCode400, enum.FailureMessage400, "effective_to must be after effective_from", nil), nil
+	}
+	// a window that has already ended would only ever be skipped, one that has started is applied on the next run
+	if request.EffectiveTo != nil && !request.EffectiveTo.After(time.Now()) {
+		return scheduleResult(enum.FailureCode400, enum.FailureMessage400, "effective_to must be in the future", nil), nil
+	}
+
+	schedule, err := b.PGDBConnector.CreateScheduledPrice(ctx, productId, request)
+	if err != nil {
+		return scheduleFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting CreateScheduledPrice ProcessMsg")
+	msg := scheduleResult(enum.CreatedCode, enum.CreatedMessage, "Price change scheduled successfully", schedule)
+	msg.Header = http.Header{}
+	msg.Header.Set("Location", fmt.Sprintf("/products/%d/scheduled-prices/%d", productId, schedule.ID))
+	return msg, nil
+}
+
+func (b *CreateScheduledPrice) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered CreateScheduledPrice Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit CreateScheduledPrice Encode")
+	return data, statusCode, nil
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"database/sql"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateScheduledPrice_Validate(t *testing.T) {
+	service := services.NewCreateScheduledPrice(new(mocks.MockCacheInterface), new(mocks.MockDBOperations))
+
+	for body, valid := range map[string]bool{
+		`{"price":9.99,"effective_from":"2026-11-20T00:00:00Z"}`:                                                        true,
+		`{"price":9.99,"currency":"EUR","effective_from":"2026-11-20T00:00:00Z","effective_to":"2026-11-27T00:00:00Z"}`: true,
+		`{"price":9.99}`: false,
+		`{"price":0,"effective_from":"2026-11-20T00:00:00Z"}`:                      false,
+		`{"price":9.99,"currency":"EURO","effective_from":"2026-11-20T00:00:00Z"}`: false,
+	} {
+		format, err := service.Decode([]byte(body))
+		assert.NoError(t, err)
+		assert.Equal(t, valid, service.Validate(format) == nil, body)
+	}
+}
+
+func TestCreateScheduledPrice_ProcessMsg(t *testing.T) {
+	future := time.Now().Add(7 * 24 * time.Hour)
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name        string
+		request     *models.ScheduledPriceRequest
+		err         error
+		code        string
+		description string
+	}{
+		{"window", &models.ScheduledPriceRequest{Price: 80, EffectiveFrom: past.Add(-time.Hour), EffectiveTo: &future}, nil, enums.CreatedCode, "Price change scheduled successfully"},
+		{"permanent", &models.ScheduledPriceRequest{Price: 80, Currency: "EUR", EffectiveFrom: future}, nil, enums.CreatedCode, "Price change scheduled successfully"},
+		{"overlap", &models.ScheduledPriceRequest{Price: 80, EffectiveFrom: future}, db.ErrScheduleOverlap, enums.FailureCode409, "The product already has a scheduled price in this window"},
+		{"product not found", &models.ScheduledPriceRequest{Price: 80, EffectiveFrom: future}, sql.ErrNoRows, enums.FailureCode404, "Product not found"},
+		{"window ends before it starts", &models.ScheduledPriceRequest{Price: 80, EffectiveFrom: future, EffectiveTo: &future}, nil, enums.FailureCode400, "effective_to must be after effective_from"},
+		{"window already ended", &models.ScheduledPriceRequest{Price: 80, EffectiveFrom: past.Add(-time.Hour), EffectiveTo: &past}, nil, enums.FailureCode400, "effective_to must be in the future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewCreateScheduledPrice(new(mocks.MockCacheInterface), mockDB)
+
+			schedule := &models.ScheduledPrice{ID: 3, ProductID: 7, Price: 80, Status: models.ScheduleStatusPending}
+			mockDB.On("CreateScheduledPrice", mock.Anything, 7, tt.request).Return(schedule, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("POST", "/products/7/scheduled-prices", nil), map[string]string{"id": "7"})
+			resp, err := service.ProcessMsg(tt.request, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			assert.Equal(t, tt.description, result.ResponseDescription)
+			if tt.code == enums.CreatedCode {
+				assert.Equal(t, schedule, result.ResponseBody)
+				assert.Equal(t, "/products/7/scheduled-prices/3", result.Header.Get("Location"))
+			}
+			if tt.code == enums.FailureCode400 {
+				mockDB.AssertNotCalled(t, "CreateScheduledPrice", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCancelScheduledPrice_ProcessMsg(t *testing.T) {
+	tests := []struct {
+		name string
+		vars map[string]string
+		err  error
+		code string
+	}{
+		{"canceled", map[string]string{"id": "7", "scheduleId": "3"}, nil, enums.SuccessCode},
+		{"already applied", map[string]string{"id": "7", "scheduleId": "3"}, db.ErrScheduleNotPending, enums.FailureCode409},
+		{"schedule not found", map[string]string{"id": "7", "scheduleId": "3"}, db.ErrScheduledPriceNotFound, enums.FailureCode404},
+		{"product not found", map[string]string{"id": "7", "scheduleId": "3"}, sql.ErrNoRows, enums.FailureCode404},
+		{"invalid schedule id", map[string]string{"id": "7", "scheduleId": "abc"}, nil, enums.FailureCode400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewCancelScheduledPrice(new(mocks.MockCacheInterface), mockDB)
+			mockDB.On("CancelScheduledPrice", mock.Anything, 7, 3).Return(tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("DELETE", "/products/7/scheduled-prices/3", nil), tt.vars)
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, resp.(models.Result).ResponseCode)
+		})
+	}
+}
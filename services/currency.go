@@ -0,0 +1,87 @@
+package services
+
+import (
+	"ProductService/models"
+	"ProductService/money"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/url"
+)
+
+// noConversion is the converter the product readers start with until the app sets the configured one,
+// it only converts a currency to itself
+func noConversion() *money.Converter {
+	return money.NewConverter(money.NewStaticProvider(nil), money.DefaultRounding())
+}
+
+// ParseCurrency reads the currency query parameter, empty when the prices are to stay in the products' own currencies
+func ParseCurrency(query url.Values) (string, error) {
+	currency := query.Get("currency")
+	if currency == "" {
+		return "", nil
+	}
+	if err := utils.NewValidator().Var(currency, "iso4217"); err != nil {
+		return "", fmt.Errorf("invalid currency: %v", currency)
+	}
+	return currency, nil
+}
+
+// convertProducts returns the products with their prices in currency. Converted products are copies carrying
+// the stored price and currency in base_price and base_currency, products already in currency are returned as they are.
+// Each rate is looked up once per call.
+func convertProducts(ctx context.Context, converter *money.Converter, products []*models.Product, currency string) ([]*models.Product, error) {
+	rates := map[string]*big.Rat{}
+	converted := make([]*models.Product, len(products))
+	for i, product := range products {
+		if product.Currency == currency {
+			converted[i] = product
+			continue
+		}
+		rate, ok := rates[product.Currency]
+		if !ok {
+			var err error
+			if rate, err = converter.Rate(ctx, product.Currency, currency); err != nil {
+				return nil, err
+			}
+			rates[product.Currency] = rate
+		}
+		converted[i] = convertProduct(converter, product, rate, currency)
+	}
+	return converted, nil
+}
+
+func convertProduct(converter *money.Converter, product *models.Product, rate *big.Rat, currency string) *models.Product {
+	copied := *product
+	basePrice := product.Price
+	copied.BasePrice = &basePrice
+	copied.BaseCurrency = product.Currency
+	copied.Price = converter.Apply(product.Price, rate, currency)
+	copied.Currency = currency
+	if product.Variants != nil {
+		copied.Variants = make([]*models.ProductVariant, len(product.Variants))
+		for i, variant := range product.Variants {
+			v := *variant
+			if variant.Price != nil {
+				price := converter.Apply(*variant.Price, rate, currency)
+				v.Price = &price
+			}
+			copied.Variants[i] = &v
+		}
+	}
+	return &copied
+}
+
+// conversionFailure maps a failed conversion to the response code, status and description sent to the client,
+// a currency without a rate is the client's mistake
+func conversionFailure(ctx context.Context, err error) (string, string, string) {
+	if errors.Is(err, money.ErrRateNotFound) {
+		return enum.FailureCode400, enum.FailureMessage400, err.Error()
+	}
+	slog.ErrorContext(ctx, "Failed to convert prices", "error", err)
+	return enum.FailureCode500, enum.FailureMessage500, "Exchange rate lookup failed"
+}
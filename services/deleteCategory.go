@@ -0,0 +1,92 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// DeleteCategory removes a category that has no subcategories, products assigned to it keep their other categories
+type DeleteCategory struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewDeleteCategory(redis db.CacheInterface, pgdb db.DBOperations) *DeleteCategory {
+	return &DeleteCategory{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *DeleteCategory) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered DeleteCategory Decode")
+	slog.Debug("Exit DeleteCategory Decode")
+	return nil, nil
+}
+
+func (b *DeleteCategory) Validate(v interface{}) error {
+	slog.Debug("Entered DeleteCategory Validate")
+	slog.Debug("Exit DeleteCategory Validate")
+	return nil
+}
+
+func (b *DeleteCategory) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered DeleteCategory ProcessMsg")
+	categoryId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return categoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid category ID", nil), nil
+	}
+
+	err = b.PGDBConnector.DeleteCategory(ctx, categoryId)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return categoryResult(enum.FailureCode404, enum.FailureMessage404, "Category not found", nil), nil
+		case errors.Is(err, db.ErrCategoryHasChildren):
+			return categoryResult(enum.FailureCode409, enum.FailureMessage409, "Category has subcategories", nil), nil
+		}
+		code, status, description := dbFailure(err)
+		return categoryResult(code, status, description, nil), nil
+	}
+	invalidateCategoryTree(ctx, b.RedisConnector)
+
+	slog.DebugContext(ctx, "Exiting DeleteCategory ProcessMsg")
+	return categoryResult(enum.SuccessCode, enum.SuccessMessage, "Category deleted successfully", nil), nil
+}
+
+func (b *DeleteCategory) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered DeleteCategory Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit DeleteCategory Encode")
+	return data, statusCode, nil
+}
@@ -0,0 +1,52 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"database/sql"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeleteCategory_ProcessMsg(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		code        string
+		invalidated bool
+	}{
+		{"deleted", nil, enums.SuccessCode, true},
+		{"not found", sql.ErrNoRows, enums.FailureCode404, false},
+		{"has subcategories", db.ErrCategoryHasChildren, enums.FailureCode409, false},
+		{"db error", errors.New("db error"), enums.FailureCode500, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewDeleteCategory(mockCache, mockDB)
+
+			mockDB.On("DeleteCategory", mock.Anything, 3).Return(tt.err)
+			mockCache.On("DeleteCategoryTree", mock.Anything).Return(nil)
+
+			req := mux.SetURLVars(httptest.NewRequest("DELETE", "/categories/3", nil), map[string]string{"id": "3"})
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, resp.(models.Result).ResponseCode)
+			if tt.invalidated {
+				mockCache.AssertCalled(t, "DeleteCategoryTree", mock.Anything)
+			} else {
+				mockCache.AssertNotCalled(t, "DeleteCategoryTree", mock.Anything)
+			}
+		})
+	}
+}
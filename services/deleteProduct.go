@@ -6,9 +6,10 @@ import (
 	enum "ProductService/utils/enums"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gorilla/mux"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 )
@@ -16,33 +17,38 @@ import (
 type DeleteProd struct {
 	RedisConnector db.CacheInterface
 	PGDBConnector  db.DBOperations
+	CacheSync      *db.CacheSync
+	// RequireIfMatch rejects deletes without an If-Match header with 428
+	RequireIfMatch bool
 }
 
 func NewDeleteProd(redis db.CacheInterface, pgdb db.DBOperations) *DeleteProd {
 	return &DeleteProd{
 		RedisConnector: redis,
 		PGDBConnector:  pgdb,
+		CacheSync:      db.NewCacheSync(redis),
 	}
 }
 
 func (b *DeleteProd) Decode(data []byte) (interface{}, error) {
-	log.Printf("Entered DeleteProd Decode")
-	log.Printf("Exit DeleteProd Decode")
+	slog.Debug("Entered DeleteProd Decode")
+	slog.Debug("Exit DeleteProd Decode")
 	return nil, nil
 }
 
 func (b *DeleteProd) Validate(v interface{}) error {
-	log.Printf("Entered DeleteProd Validate")
-	log.Printf("Exit DeleteProd Validate")
+	slog.Debug("Entered DeleteProd Validate")
+	slog.Debug("Exit DeleteProd Validate")
 	return nil
 }
 
 func (b *DeleteProd) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
-	log.Printf("Entered DeleteProd ProcessMsg")
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered DeleteProd ProcessMsg")
 
 	vars := mux.Vars(r)
 	productIdStr := vars["id"]
-	log.Println("Product ID:", productIdStr)
+	slog.DebugContext(ctx, "Product ID", "id", productIdStr)
 	// Validate product ID
 	productId, err := strconv.Atoi(productIdStr)
 	if err != nil {
@@ -55,9 +61,19 @@ func (b *DeleteProd) ProcessMsg(v interface{}, r *http.Request) (interface{}, er
 		return msg, nil
 	}
 
-	// Delete product from database
-	err = b.PGDBConnector.DeleteProduct(productId)
+	versions, failure := writePrecondition(r, b.RequireIfMatch)
+	if failure != nil {
+		return *failure, nil
+	}
+
+	// Delete product from database and evict it from the cache
+	err = b.CacheSync.WriteAndEvict(ctx, func() error {
+		return b.PGDBConnector.DeleteProduct(ctx, productId, versions)
+	}, productIdStr)
 	if err != nil {
+		if errors.Is(err, db.ErrVersionMismatch) {
+			return *preconditionFailed(), nil
+		}
 		if err == sql.ErrNoRows {
 			msg := models.Result{
 				ResponseCode:        enum.FailureCode404,
@@ -68,21 +84,16 @@ func (b *DeleteProd) ProcessMsg(v interface{}, r *http.Request) (interface{}, er
 			return msg, nil
 		}
 
+		code, status, description := dbFailure(err)
 		msg := models.Result{
-			ResponseCode:        enum.FailureCode500,
-			ResponseStatus:      enum.FailureMessage500,
-			ResponseDescription: "Database Error",
+			ResponseCode:        code,
+			ResponseStatus:      status,
+			ResponseDescription: description,
 			ResponseBody:        nil,
 		}
 		return msg, nil
 	}
 
-	// Also, optionally delete from Redis cache
-	err = b.RedisConnector.DeleteProductFromCache(productIdStr)
-	if err != nil {
-		log.Printf("Failed to delete product from cache: %v", err)
-	}
-
 	msg := models.Result{
 		ResponseCode:        enum.SuccessCode,
 		ResponseStatus:      enum.SuccessMessage,
@@ -95,34 +106,26 @@ func (b *DeleteProd) ProcessMsg(v interface{}, r *http.Request) (interface{}, er
 func (b *DeleteProd) Encode(v interface{}) ([]byte, int, error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Panic occurred: %v", r)
+			slog.Error("Panic occurred", "panic", r)
 		}
 	}()
-	log.Printf("Entered CreateProduct Encode")
+	slog.Debug("Entered CreateProduct Encode")
 
 	format, ok := v.(models.Result)
 	if !ok {
-		log.Printf("Type assertion failed: expected models.Result but got %T", v)
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
 		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
 	}
 
 	data, err := json.Marshal(&format)
 	if err != nil {
-		log.Println("Error in Marshal", err)
+		slog.Error("Error in Marshal", "error", err)
 		return nil, http.StatusInternalServerError, err
 	}
 
 	// Decide HTTP status code based on ResponseCode
-	statusCode := http.StatusOK // default 200
-	switch format.ResponseCode {
-	case "400":
-		statusCode = http.StatusBadRequest
-	case "404":
-		statusCode = http.StatusNotFound
-	case "500":
-		statusCode = http.StatusInternalServerError
-	}
+	statusCode := httpStatus(format.ResponseCode)
 
-	log.Printf("Exit CreateProduct Encode")
+	slog.Debug("Exit CreateProduct Encode")
 	return data, statusCode, nil
 }
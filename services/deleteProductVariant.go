@@ -0,0 +1,85 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// DeleteProductVariant removes a variant of a product, the product's cached copy is evicted since it embeds the variants
+type DeleteProductVariant struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+	CacheSync      *db.CacheSync
+}
+
+func NewDeleteProductVariant(redis db.CacheInterface, pgdb db.DBOperations) *DeleteProductVariant {
+	return &DeleteProductVariant{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+		CacheSync:      db.NewCacheSync(redis),
+	}
+}
+
+func (b *DeleteProductVariant) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered DeleteProductVariant Decode")
+	slog.Debug("Exit DeleteProductVariant Decode")
+	return nil, nil
+}
+
+func (b *DeleteProductVariant) Validate(v interface{}) error {
+	slog.Debug("Entered DeleteProductVariant Validate")
+	slog.Debug("Exit DeleteProductVariant Validate")
+	return nil
+}
+
+func (b *DeleteProductVariant) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered DeleteProductVariant ProcessMsg")
+	productId, variantId, _, failure := variantPath(r)
+	if failure != nil {
+		return *failure, nil
+	}
+
+	err := b.CacheSync.WriteAndEvict(ctx, func() error {
+		return b.PGDBConnector.DeleteProductVariant(ctx, productId, variantId)
+	}, strconv.Itoa(productId))
+	if err != nil {
+		return variantFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting DeleteProductVariant ProcessMsg")
+	return variantResult(enum.SuccessCode, enum.SuccessMessage, "Variant deleted successfully", nil), nil
+}
+
+func (b *DeleteProductVariant) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered DeleteProductVariant Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit DeleteProductVariant Encode")
+	return data, statusCode, nil
+}
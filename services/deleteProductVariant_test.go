@@ -0,0 +1,48 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"context"
+	"database/sql"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeleteProductVariant_ProcessMsg(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    string
+		evicted bool
+	}{
+		{"deleted", nil, enums.SuccessCode, true},
+		{"product not found", sql.ErrNoRows, enums.FailureCode404, false},
+		{"variant not found", db.ErrVariantNotFound, enums.FailureCode404, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := mocks.NewMemoryCache()
+			_ = cache.SetProductByID(context.Background(), "7", &models.Product{ID: 7}, db.ProductCacheTTL)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewDeleteProductVariant(cache, mockDB)
+
+			mockDB.On("DeleteProductVariant", mock.Anything, 7, 4).Return(tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("DELETE", "/products/7/variants/4", nil), map[string]string{"id": "7", "variantId": "4"})
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, resp.(models.Result).ResponseCode)
+			cached, _ := cache.GetProductByID(context.Background(), "7")
+			assert.Equal(t, tt.evicted, cached == nil)
+		})
+	}
+}
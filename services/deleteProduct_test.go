@@ -1,16 +1,20 @@
 package services_test
 
 import (
+	"ProductService/db"
 	"ProductService/db/mocks"
 	"ProductService/models"
 	"ProductService/services"
 	"ProductService/utils/enums"
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestDeleteProd_ProcessMsg_InvalidProductID(t *testing.T) {
@@ -37,7 +41,7 @@ func TestDeleteProd_ProcessMsg_ProductNotFoundInDB(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewDeleteProd(mockCache, mockDB)
 
-	mockDB.On("DeleteProduct", 1).Return(sql.ErrNoRows)
+	mockDB.On("DeleteProduct", mock.Anything, 1, []int(nil)).Return(sql.ErrNoRows)
 
 	req := httptest.NewRequest("DELETE", "/products/1", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
@@ -58,7 +62,7 @@ func TestDeleteProd_ProcessMsg_DBError(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewDeleteProd(mockCache, mockDB)
 
-	mockDB.On("DeleteProduct", 1).Return(errors.New("db failure"))
+	mockDB.On("DeleteProduct", mock.Anything, 1, []int(nil)).Return(errors.New("db failure"))
 
 	req := httptest.NewRequest("DELETE", "/products/1", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
@@ -79,8 +83,8 @@ func TestDeleteProd_ProcessMsg_SuccessfulDelete(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewDeleteProd(mockCache, mockDB)
 
-	mockDB.On("DeleteProduct", 1).Return(nil)
-	mockCache.On("DeleteProductFromCache", "1").Return(nil)
+	mockDB.On("DeleteProduct", mock.Anything, 1, []int(nil)).Return(nil)
+	mockCache.On("DeleteProductFromCache", mock.Anything, "1").Return(nil)
 
 	req := httptest.NewRequest("DELETE", "/products/1", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
@@ -101,8 +105,8 @@ func TestDeleteProd_ProcessMsg_SuccessfulDelete_RedisError(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewDeleteProd(mockCache, mockDB)
 
-	mockDB.On("DeleteProduct", 1).Return(nil)
-	mockCache.On("DeleteProductFromCache", "1").Return(errors.New("redis delete failure"))
+	mockDB.On("DeleteProduct", mock.Anything, 1, []int(nil)).Return(nil)
+	mockCache.On("DeleteProductFromCache", mock.Anything, "1").Return(errors.New("redis delete failure"))
 
 	req := httptest.NewRequest("DELETE", "/products/1", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
@@ -117,3 +121,44 @@ func TestDeleteProd_ProcessMsg_SuccessfulDelete_RedisError(t *testing.T) {
 	mockCache.AssertExpectations(t)
 	mockDB.AssertExpectations(t)
 }
+
+func TestDeleteProd_ReadAfterDelete_NotStale(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	deleteService := services.NewDeleteProd(cache, mockDB)
+	getService := services.NewGetProdById(cache, mockDB)
+
+	_ = cache.SetProductByID(context.Background(), "1", &models.Product{ID: 1, Name: "Old Product", Price: 50}, time.Minute)
+	mockDB.On("DeleteProduct", mock.Anything, 1, []int(nil)).Return(nil)
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(nil, nil)
+
+	delReq := mux.SetURLVars(httptest.NewRequest("DELETE", "/products/1", nil), map[string]string{"id": "1"})
+	_, err := deleteService.ProcessMsg(nil, delReq)
+	assert.NoError(t, err)
+
+	getReq := mux.SetURLVars(httptest.NewRequest("GET", "/products/1", nil), map[string]string{"id": "1"})
+	resp, _ := getProduct(getService, getReq)
+	assert.Equal(t, enums.FailureCode404, resp.ResponseCode)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestDeleteProd_ProcessMsg_IfMatch(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewDeleteProd(mockCache, mockDB)
+
+	mockDB.On("DeleteProduct", mock.Anything, 1, []int{2}).Return(db.ErrVersionMismatch)
+
+	req := httptest.NewRequest("DELETE", "/products/1", nil)
+	req.Header.Set("If-Match", `"2"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode412, result.ResponseCode)
+	mockCache.AssertNotCalled(t, "DeleteProductFromCache", mock.Anything, mock.Anything)
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var errPreconditionFailed = errors.New("If-Match does not match the current product")
+
+// ETag formats a product version as a strong entity tag
+func ETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// ifMatchVersions reads the If-Match header of a write, a comma separated list of ETags of which any may match.
+// present is false when the header is missing, "*" matches any version and gives no versions.
+// Weak and unknown tags can never match the strong comparison If-Match requires and are skipped,
+// a list without any strong tag naming a version returns errPreconditionFailed.
+func ifMatchVersions(r *http.Request) (versions []int, present bool, err error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return nil, false, nil
+	}
+	if header == "*" {
+		return nil, true, nil
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) < 2 {
+			continue
+		}
+		version, err := strconv.Atoi(tag[1 : len(tag)-1])
+		if err != nil || version <= 0 {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return nil, true, errPreconditionFailed
+	}
+	return versions, true, nil
+}
+
+// ifNoneMatch reports whether the If-None-Match header of a read matches etag.
+// It uses the weak comparison required for If-None-Match, so W/"3" matches "3".
+func ifNoneMatch(r *http.Request, etag string) bool {
+	header := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writePrecondition evaluates If-Match before a write and returns the versions the write may match,
+// none means any version. A non nil result is the 412 or 428 response to send instead of writing.
+func writePrecondition(r *http.Request, requireIfMatch bool) ([]int, *models.Result) {
+	versions, present, err := ifMatchVersions(r)
+	if err != nil {
+		return nil, preconditionFailed()
+	}
+	if !present && requireIfMatch {
+		return nil, &models.Result{
+			ResponseCode:        enum.FailureCode428,
+			ResponseStatus:      enum.FailureMessage428,
+			ResponseDescription: "If-Match header is required",
+			ResponseBody:        nil,
+		}
+	}
+	return versions, nil
+}
+
+func preconditionFailed() *models.Result {
+	return &models.Result{
+		ResponseCode:        enum.FailureCode412,
+		ResponseStatus:      enum.FailureMessage412,
+		ResponseDescription: errPreconditionFailed.Error(),
+		ResponseBody:        nil,
+	}
+}
+
+func etagHeader(etag string) http.Header {
+	header := http.Header{}
+	header.Set("ETag", etag)
+	return header
+}
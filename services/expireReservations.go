@@ -0,0 +1,68 @@
+package services
+
+import (
+	"ProductService/db"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Reservation sweeper defaults, the interval is overridden from the config in the app
+const (
+	DefaultReservationSweepInterval  = time.Minute
+	DefaultReservationSweepBatchSize = 500
+)
+
+// ExpireReservations gives the stock of expired reservations back. It runs every Interval and
+// expires in chunks of BatchSize. Running it on several instances at once is safe, each
+// reservation is only expired once.
+type ExpireReservations struct {
+	PGDBConnector db.DBOperations
+	Interval      time.Duration
+	BatchSize     int
+}
+
+func NewExpireReservations(pgdb db.DBOperations) *ExpireReservations {
+	return &ExpireReservations{
+		PGDBConnector: pgdb,
+		Interval:      DefaultReservationSweepInterval,
+		BatchSize:     DefaultReservationSweepBatchSize,
+	}
+}
+
+// Run sweeps once right away and then on every tick until ctx is canceled
+func (e *ExpireReservations) Run(ctx context.Context) {
+	slog.Info("Started reservation sweeper", "interval", e.Interval)
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := e.Sweep(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Failed to expire reservations", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopped reservation sweeper")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep expires every reservation that expired before now and returns how many were expired
+func (e *ExpireReservations) Sweep(ctx context.Context, now time.Time) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		expired, err := e.PGDBConnector.ExpireReservations(ctx, now, e.BatchSize)
+		total += expired
+		if err != nil {
+			return total, err
+		}
+		if expired < int64(e.BatchSize) {
+			break
+		}
+	}
+	if total > 0 {
+		slog.InfoContext(ctx, "Expired reservations", "count", total)
+	}
+	return total, ctx.Err()
+}
@@ -0,0 +1,41 @@
+package services_test
+
+import (
+	"ProductService/db/mocks"
+	"ProductService/services"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestExpireReservations_Sweep_RepeatsUntilBacklogIsEmpty(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewExpireReservations(mockDB)
+	service.BatchSize = 2
+
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	mockDB.On("ExpireReservations", mock.Anything, now, 2).Return(int64(2), nil).Once()
+	mockDB.On("ExpireReservations", mock.Anything, now, 2).Return(int64(0), nil).Once()
+
+	expired, err := service.Sweep(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), expired)
+	mockDB.AssertExpectations(t)
+}
+
+func TestExpireReservations_Sweep_DBError(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewExpireReservations(mockDB)
+
+	mockDB.On("ExpireReservations", mock.Anything, mock.Anything, services.DefaultReservationSweepBatchSize).Return(int64(0), errors.New("db error")).Once()
+
+	expired, err := service.Sweep(context.Background(), time.Now())
+
+	assert.Error(t, err)
+	assert.Zero(t, expired)
+	mockDB.AssertExpectations(t)
+}
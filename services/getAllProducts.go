@@ -3,53 +3,102 @@ package services
 import (
 	"ProductService/db"
 	"ProductService/models"
+	"ProductService/money"
+	"ProductService/utils"
 	enum "ProductService/utils/enums"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
-	"log"
+	"log/slog"
 	"math"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 )
 
 type GetAllProd struct {
 	RedisConnector db.CacheInterface
 	PGDBConnector  db.DBOperations
+	// Converter converts the prices when the list is asked for with ?currency
+	Converter *money.Converter
+	// AdminToken is the X-Admin-Token include_deleted requires, empty forbids the flag to everyone
+	AdminToken string
 }
 
 func NewGetAllProd(redis db.CacheInterface, pgdb db.DBOperations) *GetAllProd {
 	return &GetAllProd{
 		RedisConnector: redis,
 		PGDBConnector:  pgdb,
+		Converter:      noConversion(),
 	}
 }
 
 func (b *GetAllProd) Decode(data []byte) (interface{}, error) {
-	log.Printf("Entered GetAllProd Decode")
-	log.Printf("Exit GetAllProd Decode")
+	slog.Debug("Entered GetAllProd Decode")
+	slog.Debug("Exit GetAllProd Decode")
 	return nil, nil
 }
 
 func (b *GetAllProd) Validate(v interface{}) error {
-	log.Printf("Entered GetAllProd Validate")
-	log.Printf("Exit GetAllProd Validate")
+	slog.Debug("Entered GetAllProd Validate")
+	slog.Debug("Exit GetAllProd Validate")
 	return nil
 }
 
 func (b *GetAllProd) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
-	log.Println("Entered GetAllProd ProcessMsg")
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered GetAllProd ProcessMsg")
 	pageStr := r.URL.Query().Get("page")
 	pageSizeStr := r.URL.Query().Get("page_size")
 	var emptyResponse models.PaginationProductResponse
 
-	count, err := b.PGDBConnector.GetProductCount()
+	filter, err := ParseProductFilter(r.URL.Query())
 	if err != nil {
+		slog.WarnContext(ctx, "Error in ParseProductFilter", "error", err)
+		msg := models.PaginatedResponse{
+			ResponseCode:        enum.FailureCode400,
+			ResponseStatus:      enum.FailureMessage400,
+			ResponseDescription: err.Error(),
+			ResponseBody:        emptyResponse,
+		}
+		return msg, nil
+	}
+	if filter.IncludeDeleted && !utils.IsAdmin(r, b.AdminToken) {
+		slog.WarnContext(ctx, "include_deleted without admin token")
+		msg := models.PaginatedResponse{
+			ResponseCode:        enum.FailureCode403,
+			ResponseStatus:      enum.FailureMessage403,
+			ResponseDescription: includeDeletedForbidden,
+			ResponseBody:        emptyResponse,
+		}
+		return msg, nil
+	}
+	currency, err := ParseCurrency(r.URL.Query())
+	if err != nil {
+		msg := models.PaginatedResponse{
+			ResponseCode:        enum.FailureCode400,
+			ResponseStatus:      enum.FailureMessage400,
+			ResponseDescription: err.Error(),
+			ResponseBody:        emptyResponse,
+		}
+		return msg, nil
+	}
+
+	if r.URL.Query().Has("cursor") {
+		return b.processCursorPage(ctx, filter, r.URL.Query().Get("cursor"), pageSizeStr, currency), nil
+	}
+
+	count, err := b.PGDBConnector.GetProductCount(ctx, filter)
+	if err != nil {
+		code, status, description := dbFailure(err)
 		msg := models.Result{
-			ResponseCode:        enum.FailureCode500,
-			ResponseStatus:      enum.FailureMessage500,
-			ResponseDescription: "Database Error",
+			ResponseCode:        code,
+			ResponseStatus:      status,
+			ResponseDescription: description,
 			ResponseBody:        nil,
 		}
 		return msg, nil
@@ -67,7 +116,7 @@ func (b *GetAllProd) ProcessMsg(v interface{}, r *http.Request) (interface{}, er
 
 	pageBody, e := PagenationFunction(pageStr, pageSizeStr, count)
 	if e != nil {
-		log.Println("Error in PagenationFunction: ", e)
+		slog.WarnContext(ctx, "Error in PagenationFunction", "error", e)
 		msg := models.PaginatedResponse{
 			ResponseCode:        enum.FailureCode500,
 			ResponseStatus:      enum.FailureMessage500,
@@ -79,16 +128,29 @@ func (b *GetAllProd) ProcessMsg(v interface{}, r *http.Request) (interface{}, er
 
 	pageBodyResp := pageBody.(models.PaginationProductResponse)
 
-	products, err := b.PGDBConnector.GetAllProducts(pageBodyResp.Offset, pageBodyResp.PageSize)
+	products, err := b.PGDBConnector.GetAllProducts(ctx, filter, pageBodyResp.Offset, pageBodyResp.PageSize)
 	if err != nil {
+		code, status, description := dbFailure(err)
 		msg := models.PaginatedResponse{
-			ResponseCode:        enum.FailureCode500,
-			ResponseStatus:      enum.FailureMessage500,
-			ResponseDescription: "Database Error",
+			ResponseCode:        code,
+			ResponseStatus:      status,
+			ResponseDescription: description,
 			ResponseBody:        emptyResponse,
 		}
 		return msg, nil
 	}
+	if currency != "" {
+		if products, err = convertProducts(ctx, b.Converter, products, currency); err != nil {
+			code, status, description := conversionFailure(ctx, err)
+			msg := models.PaginatedResponse{
+				ResponseCode:        code,
+				ResponseStatus:      status,
+				ResponseDescription: description,
+				ResponseBody:        emptyResponse,
+			}
+			return msg, nil
+		}
+	}
 
 	response := models.PaginationProductResponse{
 		PageNo:     pageBodyResp.PageNo,
@@ -108,43 +170,271 @@ func (b *GetAllProd) ProcessMsg(v interface{}, r *http.Request) (interface{}, er
 	return msg, nil
 }
 
+// processCursorPage serves the list in keyset mode, the page starts after the product encoded in cursor.
+// Prices are converted to currency unless it is empty.
+func (b *GetAllProd) processCursorPage(ctx context.Context, filter models.ProductFilter, cursorStr string, pageSizeStr string, currency string) models.CursorPaginatedResponse {
+	slog.Debug("Entered GetAllProd processCursorPage")
+	var emptyResponse models.CursorPaginationProductResponse
+
+	var cursor *models.ProductCursor
+	if cursorStr != "" {
+		var err error
+		cursor, err = DecodeCursor(cursorStr)
+		if err != nil || (filter.Sort != "" && filter.Sort != cursor.Sort) {
+			slog.WarnContext(ctx, "Invalid cursor", "cursor", cursorStr)
+			msg := models.CursorPaginatedResponse{
+				ResponseCode:        enum.FailureCode400,
+				ResponseStatus:      enum.FailureMessage400,
+				ResponseDescription: "Invalid cursor",
+				ResponseBody:        emptyResponse,
+			}
+			return msg
+		}
+		// the cursor is only valid for the order it was issued with
+		filter.Sort = cursor.Sort
+	}
+
+	count, err := b.PGDBConnector.GetProductCount(ctx, filter)
+	if err != nil {
+		code, status, description := dbFailure(err)
+		msg := models.CursorPaginatedResponse{
+			ResponseCode:        code,
+			ResponseStatus:      status,
+			ResponseDescription: description,
+			ResponseBody:        emptyResponse,
+		}
+		return msg
+	}
+
+	pageBody, e := PagenationFunction("", pageSizeStr, count)
+	if e != nil {
+		slog.WarnContext(ctx, "Error in PagenationFunction", "error", e)
+		msg := models.CursorPaginatedResponse{
+			ResponseCode:        enum.FailureCode500,
+			ResponseStatus:      enum.FailureMessage500,
+			ResponseDescription: "Error in PagenationFunction",
+			ResponseBody:        emptyResponse,
+		}
+		return msg
+	}
+	pageBodyResp := pageBody.(models.PaginationProductResponse)
+
+	// fetching one extra row tells us whether there is a next page
+	products, err := b.PGDBConnector.GetProductsAfter(ctx, filter, cursor, pageBodyResp.PageSize+1)
+	if err != nil {
+		code, status, description := dbFailure(err)
+		msg := models.CursorPaginatedResponse{
+			ResponseCode:        code,
+			ResponseStatus:      status,
+			ResponseDescription: description,
+			ResponseBody:        emptyResponse,
+		}
+		return msg
+	}
+
+	hasMore := len(products) > pageBodyResp.PageSize
+	if hasMore {
+		products = products[:pageBodyResp.PageSize]
+	}
+
+	var nextCursor string
+	if hasMore {
+		nextCursor, err = EncodeCursor(filter.Sort, products[len(products)-1])
+		if err != nil {
+			slog.ErrorContext(ctx, "Error in EncodeCursor", "error", err)
+			msg := models.CursorPaginatedResponse{
+				ResponseCode:        enum.FailureCode500,
+				ResponseStatus:      enum.FailureMessage500,
+				ResponseDescription: enum.FailureMessage500,
+				ResponseBody:        emptyResponse,
+			}
+			return msg
+		}
+	}
+	// the cursor above holds the stored price, only the products sent out are converted
+	if currency != "" {
+		if products, err = convertProducts(ctx, b.Converter, products, currency); err != nil {
+			code, status, description := conversionFailure(ctx, err)
+			msg := models.CursorPaginatedResponse{
+				ResponseCode:        code,
+				ResponseStatus:      status,
+				ResponseDescription: description,
+				ResponseBody:        emptyResponse,
+			}
+			return msg
+		}
+	}
+
+	response := models.CursorPaginationProductResponse{
+		PaginationProductResponse: models.PaginationProductResponse{
+			PageSize:   pageBodyResp.PageSize,
+			TotalCount: pageBodyResp.TotalCount,
+			TotalPages: pageBodyResp.TotalPages,
+			Products:   products,
+		},
+		Cursor:     cursorStr,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	}
+
+	msg := models.CursorPaginatedResponse{
+		ResponseCode:        enum.SuccessCode,
+		ResponseStatus:      enum.SuccessMessage,
+		ResponseDescription: "Products fetched successfully",
+		ResponseBody:        response,
+	}
+	slog.Debug("Exiting GetAllProd processCursorPage")
+	return msg
+}
+
 func (b *GetAllProd) Encode(v interface{}) ([]byte, int, error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Panic occurred: %v", r)
+			slog.Error("Panic occurred", "panic", r)
 		}
 	}()
-	log.Printf("Entered GetAllProd Encode")
+	slog.Debug("Entered GetAllProd Encode")
 
-	format, ok := v.(models.PaginatedResponse)
-	if !ok {
-		log.Printf("Type assertion failed: expected models.Result but got %T", v)
-		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	var responseCode string
+	switch format := v.(type) {
+	case models.PaginatedResponse:
+		responseCode = format.ResponseCode
+	case models.CursorPaginatedResponse:
+		responseCode = format.ResponseCode
+	default:
+		slog.Error("Type assertion failed: expected models.PaginatedResponse", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.PaginatedResponse but got %T", v)
 	}
 
-	data, err := json.Marshal(&format)
+	data, err := json.Marshal(v)
 	if err != nil {
-		log.Println("Error in Marshal", err)
+		slog.Error("Error in Marshal", "error", err)
 		return nil, http.StatusInternalServerError, err
 	}
 
 	// Decide HTTP status code based on ResponseCode
-	statusCode := http.StatusOK // default 200
-	switch format.ResponseCode {
-	case "400":
-		statusCode = http.StatusBadRequest
-	case "404":
-		statusCode = http.StatusNotFound
-	case "500":
-		statusCode = http.StatusInternalServerError
-	}
+	statusCode := httpStatus(responseCode)
 
-	log.Printf("Exit GetAllProd Encode")
+	slog.Debug("Exit GetAllProd Encode")
 	return data, statusCode, nil
 }
 
+// EncodeCursor builds the opaque cursor pointing at product for the given sort
+func EncodeCursor(sort string, product *models.Product) (string, error) {
+	column, _, err := db.ParseProductSort(sort)
+	if err != nil {
+		return "", err
+	}
+	cursor := models.ProductCursor{Sort: sort, ID: product.ID}
+	switch column {
+	case "name":
+		cursor.Key = product.Name
+	case "price":
+		cursor.Key = product.Price
+	}
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor
+func DecodeCursor(cursorStr string) (*models.ProductCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursorStr)
+	if err != nil {
+		return nil, err
+	}
+	var cursor models.ProductCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	column, _, err := db.ParseProductSort(cursor.Sort)
+	if err != nil {
+		return nil, err
+	}
+	switch column {
+	case "name":
+		if _, ok := cursor.Key.(string); !ok {
+			return nil, errors.New("invalid cursor key")
+		}
+	case "price":
+		if _, ok := cursor.Key.(float64); !ok {
+			return nil, errors.New("invalid cursor key")
+		}
+	}
+	return &cursor, nil
+}
+
+// includeDeletedForbidden is the description of the 403 returned for include_deleted without the admin token
+const includeDeletedForbidden = "include_deleted requires the admin token"
+
+// ParseProductFilter reads the q, min_price, max_price, sort, include_deleted, tag and attr.<key> query parameters
+// of the list endpoint. tag can be repeated, every tag has to be present on a product.
+func ParseProductFilter(query url.Values) (models.ProductFilter, error) {
+	filter := models.ProductFilter{
+		Query: strings.TrimSpace(query.Get("q")),
+		Sort:  query.Get("sort"),
+	}
+
+	if _, _, err := db.ParseProductSort(filter.Sort); err != nil {
+		return filter, err
+	}
+
+	for _, param := range []struct {
+		name string
+		dest **float64
+	}{
+		{"min_price", &filter.MinPrice},
+		{"max_price", &filter.MaxPrice},
+	} {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil || price < 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+			return filter, fmt.Errorf("invalid %v: %v", param.name, value)
+		}
+		*param.dest = &price
+	}
+
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return filter, errors.New("min_price cannot be greater than max_price")
+	}
+
+	if value := query.Get("include_deleted"); value != "" {
+		includeDeleted, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid include_deleted: %v", value)
+		}
+		filter.IncludeDeleted = includeDeleted
+	}
+
+	for _, tag := range query["tag"] {
+		if tag == "" {
+			return filter, errors.New("tag cannot be empty")
+		}
+		filter.Tags = append(filter.Tags, tag)
+	}
+	for param, values := range query {
+		key, ok := strings.CutPrefix(param, "attr.")
+		if !ok {
+			continue
+		}
+		if !utils.IsAttributeKey(key) {
+			return filter, fmt.Errorf("invalid attribute filter: %v", param)
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = map[string]string{}
+		}
+		filter.Attributes[key] = values[0]
+	}
+	return filter, nil
+}
+
 func PagenationFunction(received_page_str interface{}, received_page_size_str interface{}, received_total_elements_str interface{}) (interface{}, error) {
-	log.Println("Entered PagenationFunction")
+	slog.Debug("Entered PagenationFunction")
 
 	var page, page_size int
 
@@ -154,20 +444,20 @@ func PagenationFunction(received_page_str interface{}, received_page_size_str in
 
 	if page_str == "" {
 		page = 1
-		log.Println("Setting default page to 1")
+		slog.Debug("Setting default page to 1")
 	} else {
 		validate := validator.New()
-		log.Println("Page string is :", page_str)
+		slog.Debug("Page string", "page", page_str)
 		res := validate.Var(page_str, "numeric")
 		if res != nil {
-			log.Println("Error in validating page no")
+			slog.Warn("Error in validating page no")
 			err := errors.New("Error in validating pageno. Page number not numeric")
 			return nil, err
 		} else {
 			var e error
 			page, e = strconv.Atoi(page_str)
 			if e != nil {
-				log.Println("Error in converting page number to integer :", page)
+				slog.Warn("Error in converting page number to integer", "page", page_str)
 				err := errors.New("Error in converting page number to integer")
 				return nil, err
 			}
@@ -179,21 +469,21 @@ func PagenationFunction(received_page_str interface{}, received_page_size_str in
 
 	if page_size_str == "" {
 		page_size = 10
-		log.Println("Setting default page size to 10")
+		slog.Debug("Setting default page size to 10")
 	} else {
 		validate := validator.New()
-		log.Println("Page size string is :", page_size_str)
+		slog.Debug("Page size string", "page_size", page_size_str)
 		res := validate.Var(page_size_str, "numeric")
 		if res != nil {
-			log.Println("Error in validating page size")
+			slog.Warn("Error in validating page size")
 			err := errors.New("Error in validating page size. Page size not numeric")
 			return nil, err
 		} else {
 			var e error
 			page_size, e = strconv.Atoi(page_size_str)
-			log.Println("page size is", page_size)
+			slog.Debug("Page size", "page_size", page_size)
 			if e != nil {
-				log.Println("Error in converting page size to integer :", page)
+				slog.Warn("Error in converting page size to integer", "page_size", page_size_str)
 				err := errors.New("Error in converting page size to integer")
 				return nil, err
 			}
@@ -226,6 +516,6 @@ func PagenationFunction(received_page_str interface{}, received_page_size_str in
 		Offset:     offset,
 	}
 
-	log.Println("Exited PagenationFunction")
+	slog.Debug("Exited PagenationFunction")
 	return resp, nil
 }
@@ -3,12 +3,18 @@ package services_test
 import (
 	"ProductService/db/mocks"
 	"ProductService/models"
+	"ProductService/money"
 	"ProductService/services"
+	"ProductService/utils"
 	"ProductService/utils/enums"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"math/big"
+	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestGetAllProd_ProcessMsg_DBErrorOnCount(t *testing.T) {
@@ -16,7 +22,7 @@ func TestGetAllProd_ProcessMsg_DBErrorOnCount(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewGetAllProd(mockCache, mockDB)
 
-	mockDB.On("GetProductCount").Return(0, errors.New("db error"))
+	mockDB.On("GetProductCount", mock.Anything, models.ProductFilter{}).Return(0, errors.New("db error"))
 
 	req := httptest.NewRequest("GET", "/products", nil)
 
@@ -35,7 +41,7 @@ func TestGetAllProd_ProcessMsg_NoProductsFound(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewGetAllProd(mockCache, mockDB)
 
-	mockDB.On("GetProductCount").Return(0, nil)
+	mockDB.On("GetProductCount", mock.Anything, models.ProductFilter{}).Return(0, nil)
 
 	req := httptest.NewRequest("GET", "/products", nil)
 
@@ -54,7 +60,7 @@ func TestGetAllProd_ProcessMsg_ErrorInPaginationFunction(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewGetAllProd(mockCache, mockDB)
 
-	mockDB.On("GetProductCount").Return(10, nil)
+	mockDB.On("GetProductCount", mock.Anything, models.ProductFilter{}).Return(10, nil)
 
 	req := httptest.NewRequest("GET", "/products?page=invalid", nil)
 	q := req.URL.Query()
@@ -76,8 +82,8 @@ func TestGetAllProd_ProcessMsg_DBErrorOnFetchProducts(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewGetAllProd(mockCache, mockDB)
 
-	mockDB.On("GetProductCount").Return(10, nil)
-	mockDB.On("GetAllProducts", 0, 10).Return(nil, errors.New("db error"))
+	mockDB.On("GetProductCount", mock.Anything, models.ProductFilter{}).Return(10, nil)
+	mockDB.On("GetAllProducts", mock.Anything, models.ProductFilter{}, 0, 10).Return(nil, errors.New("db error"))
 
 	req := httptest.NewRequest("GET", "/products?page=1&page_size=10", nil)
 	q := req.URL.Query()
@@ -94,3 +100,305 @@ func TestGetAllProd_ProcessMsg_DBErrorOnFetchProducts(t *testing.T) {
 
 	mockDB.AssertExpectations(t)
 }
+
+func TestGetAllProd_ProcessMsg_FilterAndSortApplied(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetAllProd(mockCache, mockDB)
+
+	minPrice, maxPrice := 10.0, 100.0
+	filter := models.ProductFilter{Query: "mouse", MinPrice: &minPrice, MaxPrice: &maxPrice, Sort: "-price"}
+	products := []*models.Product{{ID: 1, Name: "Wireless Mouse", Price: 25.99}}
+	mockDB.On("GetProductCount", mock.Anything, filter).Return(1, nil)
+	mockDB.On("GetAllProducts", mock.Anything, filter, 0, 10).Return(products, nil)
+
+	req := httptest.NewRequest("GET", "/products?q=mouse&min_price=10&max_price=100&sort=-price", nil)
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	result := resp.(models.PaginatedResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, 1, result.ResponseBody.TotalCount)
+	assert.Equal(t, 1, result.ResponseBody.TotalPages)
+	assert.Equal(t, products, result.ResponseBody.Products)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetAllProd_ProcessMsg_IncludeDeleted(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetAllProd(mockCache, mockDB)
+	service.AdminToken = "s3cret"
+
+	filter := models.ProductFilter{IncludeDeleted: true}
+	products := []*models.Product{{ID: 1, Name: "Wireless Mouse", Price: 25.99, DeletedAt: &time.Time{}}}
+	mockDB.On("GetProductCount", mock.Anything, filter).Return(1, nil)
+	mockDB.On("GetAllProducts", mock.Anything, filter, 0, 10).Return(products, nil)
+
+	req := httptest.NewRequest("GET", "/products?include_deleted=true", nil)
+	req.Header.Set(utils.AdminTokenHeader, "s3cret")
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	result := resp.(models.PaginatedResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, products, result.ResponseBody.Products)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetAllProd_ProcessMsg_IncludeDeletedForbidden(t *testing.T) {
+	tests := []struct {
+		name       string
+		adminToken string
+		header     string
+	}{
+		{"no token sent", "s3cret", ""},
+		{"wrong token", "s3cret", "guess"},
+		{"no token configured", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetAllProd(mockCache, mockDB)
+			service.AdminToken = tt.adminToken
+
+			req := httptest.NewRequest("GET", "/products?include_deleted=true&cursor=", nil)
+			if tt.header != "" {
+				req.Header.Set(utils.AdminTokenHeader, tt.header)
+			}
+
+			resp, err := service.ProcessMsg(nil, req)
+
+			result := resp.(models.PaginatedResponse)
+			assert.NoError(t, err)
+			assert.Equal(t, enums.FailureCode403, result.ResponseCode)
+			// nothing is read for a forbidden listing
+			mockDB.AssertNotCalled(t, "GetProductCount", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetAllProd_ProcessMsg_TagAndAttributeFilters(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetAllProd(mockCache, mockDB)
+
+	filter := models.ProductFilter{
+		Tags:       []string{"sale", "new"},
+		Attributes: map[string]string{"color": "red", "size": "42"},
+	}
+	products := []*models.Product{{ID: 1, Name: "Red Shoe", Price: 50, Attributes: models.Attributes{"color": "red", "size": 42.0}}}
+	mockDB.On("GetProductCount", mock.Anything, filter).Return(1, nil)
+	mockDB.On("GetAllProducts", mock.Anything, filter, 0, 10).Return(products, nil)
+
+	req := httptest.NewRequest("GET", "/products?tag=sale&tag=new&attr.color=red&attr.size=42", nil)
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	result := resp.(models.PaginatedResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, products, result.ResponseBody.Products)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetAllProd_ProcessMsg_InvalidFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"unknown sort field", "sort=price%3BDROP%20TABLE%20products"},
+		{"non numeric min price", "min_price=abc"},
+		{"negative max price", "max_price=-1"},
+		{"min greater than max", "min_price=50&max_price=10"},
+		{"non boolean include_deleted", "include_deleted=maybe"},
+		{"empty tag", "tag="},
+		{"invalid attribute key", "attr.co%20lor=red"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetAllProd(mockCache, mockDB)
+
+			req := httptest.NewRequest("GET", "/products?"+tt.query, nil)
+
+			resp, err := service.ProcessMsg(nil, req)
+
+			result := resp.(models.PaginatedResponse)
+			assert.NoError(t, err)
+			assert.Equal(t, enums.FailureCode400, result.ResponseCode)
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAllProd_ProcessMsg_CursorFirstPage(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetAllProd(mockCache, mockDB)
+
+	filter := models.ProductFilter{Sort: "price"}
+	products := []*models.Product{
+		{ID: 4, Name: "USB-C Hub", Price: 39.95},
+		{ID: 2, Name: "Mechanical Keyboard", Price: 89.50},
+		{ID: 5, Name: "Noise Cancelling Headphones", Price: 129},
+	}
+	mockDB.On("GetProductCount", mock.Anything, filter).Return(5, nil)
+	mockDB.On("GetProductsAfter", mock.Anything, filter, (*models.ProductCursor)(nil), 3).Return(products, nil)
+
+	req := httptest.NewRequest("GET", "/products?cursor=&page_size=2&sort=price", nil)
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	result := resp.(models.CursorPaginatedResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, products[:2], result.ResponseBody.Products)
+	assert.Equal(t, 5, result.ResponseBody.TotalCount)
+	assert.True(t, result.ResponseBody.HasMore)
+
+	cursor, err := services.DecodeCursor(result.ResponseBody.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ProductCursor{Sort: "price", Key: 89.50, ID: 2}, cursor)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetAllProd_ProcessMsg_CursorLastPage(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetAllProd(mockCache, mockDB)
+
+	cursorStr, _ := services.EncodeCursor("-name", &models.Product{ID: 3, Name: "HD Monitor", Price: 199.99})
+	cursor, _ := services.DecodeCursor(cursorStr)
+	filter := models.ProductFilter{Sort: "-name"}
+	products := []*models.Product{{ID: 1, Name: "Wireless Mouse", Price: 25.99}}
+	mockDB.On("GetProductCount", mock.Anything, filter).Return(5, nil)
+	mockDB.On("GetProductsAfter", mock.Anything, filter, cursor, 11).Return(products, nil)
+
+	// the sort is taken from the cursor when it is not repeated
+	req := httptest.NewRequest("GET", "/products?cursor="+cursorStr, nil)
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	result := resp.(models.CursorPaginatedResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, cursorStr, result.ResponseBody.Cursor)
+	assert.False(t, result.ResponseBody.HasMore)
+	assert.Empty(t, result.ResponseBody.NextCursor)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetAllProd_ProcessMsg_InvalidCursor(t *testing.T) {
+	priceCursor, _ := services.EncodeCursor("price", &models.Product{ID: 1, Price: 10})
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"not base64", "cursor=%25%25%25"},
+		{"not json", "cursor=bm90LWpzb24"},
+		{"sort mismatch", "cursor=" + priceCursor + "&sort=name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetAllProd(mockCache, mockDB)
+
+			req := httptest.NewRequest("GET", "/products?"+tt.query, nil)
+
+			resp, err := service.ProcessMsg(nil, req)
+
+			result := resp.(models.CursorPaginatedResponse)
+			assert.NoError(t, err)
+			assert.Equal(t, enums.FailureCode400, result.ResponseCode)
+
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAllProd_Encode_CursorResponse(t *testing.T) {
+	service := services.NewGetAllProd(nil, nil)
+
+	data, statusCode, err := service.Encode(models.CursorPaginatedResponse{
+		ResponseCode: enums.FailureCode400,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Contains(t, string(data), `"next_cursor"`)
+}
+
+func TestGetAllProd_ProcessMsg_CursorCurrency(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetAllProd(mockCache, mockDB)
+	service.Converter = money.NewConverter(money.NewStaticProvider(map[string]*big.Rat{
+		"USD": big.NewRat(1, 1),
+		"EUR": big.NewRat(1, 2),
+	}), money.DefaultRounding())
+
+	filter := models.ProductFilter{Sort: "price"}
+	products := []*models.Product{
+		{ID: 4, Name: "USB-C Hub", Price: 39.95, Currency: "USD"},
+		{ID: 2, Name: "Mechanical Keyboard", Price: 89.50, Currency: "EUR"},
+		{ID: 5, Name: "Noise Cancelling Headphones", Price: 129, Currency: "USD"},
+	}
+	mockDB.On("GetProductCount", mock.Anything, filter).Return(5, nil)
+	mockDB.On("GetProductsAfter", mock.Anything, filter, (*models.ProductCursor)(nil), 3).Return(products, nil)
+
+	req := httptest.NewRequest("GET", "/products?cursor=&page_size=2&sort=price&currency=EUR", nil)
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	result := resp.(models.CursorPaginatedResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	page := result.ResponseBody.Products.([]*models.Product)
+	assert.Len(t, page, 2)
+	assert.Equal(t, 19.98, page[0].Price)
+	assert.Equal(t, "EUR", page[0].Currency)
+	assert.Equal(t, 39.95, *page[0].BasePrice)
+	assert.Same(t, products[1], page[1])
+
+	// the cursor keeps the stored price the next page is sorted by
+	cursor, err := services.DecodeCursor(result.ResponseBody.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ProductCursor{Sort: "price", Key: 89.50, ID: 2}, cursor)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetAllProd_ProcessMsg_UnknownCurrency(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetAllProd(mockCache, mockDB)
+
+	mockDB.On("GetProductCount", mock.Anything, models.ProductFilter{}).Return(1, nil)
+	mockDB.On("GetAllProducts", mock.Anything, models.ProductFilter{}, 0, 10).Return([]*models.Product{
+		{ID: 1, Name: "Phone", Price: 100, Currency: "USD"},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/products?currency=EUR", nil)
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	result := resp.(models.PaginatedResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode400, result.ResponseCode)
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// GetCategories serves GET /categories with the whole tree and GET /categories/{id} with one subtree,
+// both read from the cached tree
+type GetCategories struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewGetCategories(redis db.CacheInterface, pgdb db.DBOperations) *GetCategories {
+	return &GetCategories{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *GetCategories) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered GetCategories Decode")
+	slog.Debug("Exit GetCategories Decode")
+	return nil, nil
+}
+
+func (b *GetCategories) Validate(v interface{}) error {
+	slog.Debug("Entered GetCategories Validate")
+	slog.Debug("Exit GetCategories Validate")
+	return nil
+}
+
+func (b *GetCategories) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered GetCategories ProcessMsg")
+
+	idStr, single := mux.Vars(r)["id"]
+	categoryId, err := strconv.Atoi(idStr)
+	if single && err != nil {
+		return categoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid category ID", nil), nil
+	}
+
+	if !single {
+		tree, err := loadCategoryTree(ctx, b.RedisConnector, b.PGDBConnector)
+		if err != nil {
+			code, status, description := dbFailure(err)
+			return categoryResult(code, status, description, nil), nil
+		}
+		slog.DebugContext(ctx, "Exiting GetCategories ProcessMsg")
+		return categoryResult(enum.SuccessCode, enum.SuccessMessage, "Categories fetched successfully", tree), nil
+	}
+
+	node, err := loadCategory(ctx, b.RedisConnector, b.PGDBConnector, categoryId)
+	if err != nil {
+		code, status, description := dbFailure(err)
+		return categoryResult(code, status, description, nil), nil
+	}
+	if node == nil {
+		return categoryResult(enum.FailureCode404, enum.FailureMessage404, "Category not found", nil), nil
+	}
+	slog.DebugContext(ctx, "Exiting GetCategories ProcessMsg")
+	return categoryResult(enum.SuccessCode, enum.SuccessMessage, "Category fetched successfully", node), nil
+}
+
+func (b *GetCategories) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered GetCategories Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit GetCategories Encode")
+	return data, statusCode, nil
+}
@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"context"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+)
+
+func parentID(id int) *int {
+	return &id
+}
+
+// categoryFixture is a two level tree: Electronics > Phones > Android, Electronics > Laptops and Books
+func categoryFixture() []*models.Category {
+	return []*models.Category{
+		{ID: 4, ParentID: parentID(2), Name: "Android"},
+		{ID: 5, Name: "Books"},
+		{ID: 1, Name: "Electronics"},
+		{ID: 3, ParentID: parentID(1), Name: "Laptops"},
+		{ID: 2, ParentID: parentID(1), Name: "Phones"},
+	}
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	tree := services.BuildCategoryTree(categoryFixture())
+
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "Books", tree[0].Name)
+	assert.Empty(t, tree[0].Children)
+	electronics := tree[1]
+	assert.Equal(t, "Electronics", electronics.Name)
+	assert.Len(t, electronics.Children, 2)
+	assert.Equal(t, "Laptops", electronics.Children[0].Name)
+	assert.Equal(t, "Phones", electronics.Children[1].Name)
+	assert.Equal(t, "Android", electronics.Children[1].Children[0].Name)
+}
+
+func TestGetCategories_ProcessMsg_CachesTree(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetCategories(cache, mockDB)
+
+	mockDB.On("ListCategories", mock.Anything).Return(categoryFixture(), nil).Once()
+
+	for i := 0; i < 2; i++ {
+		resp, err := service.ProcessMsg(nil, httptest.NewRequest("GET", "/categories", nil))
+
+		assert.NoError(t, err)
+		result := resp.(models.Result)
+		assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+		assert.Len(t, result.ResponseBody, 2)
+	}
+	cached, _ := cache.GetCategoryTree(context.Background())
+	assert.Len(t, cached, 2)
+	mockDB.AssertExpectations(t)
+}
+
+// createdSinceCached is the fixture plus Electronics > Tablets, created after the fixture's tree was cached
+func createdSinceCached() []*models.Category {
+	return append(categoryFixture(), &models.Category{ID: 6, ParentID: parentID(1), Name: "Tablets"})
+}
+
+func TestGetCategories_ProcessMsg_Single(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		code     string
+		category string
+		reloaded bool
+	}{
+		{"nested category", "2", enums.SuccessCode, "Phones", false},
+		{"created since the tree was cached", "6", enums.SuccessCode, "Tablets", true},
+		{"unknown category", "42", enums.FailureCode404, "", true},
+		{"invalid id", "abc", enums.FailureCode400, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := mocks.NewMemoryCache()
+			_ = cache.SetCategoryTree(context.Background(), services.BuildCategoryTree(categoryFixture()), services.CategoryTreeTTL)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetCategories(cache, mockDB)
+
+			mockDB.On("ListCategories", mock.Anything).Return(createdSinceCached(), nil).Maybe()
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/categories/"+tt.id, nil), map[string]string{"id": tt.id})
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			if tt.code == enums.SuccessCode {
+				node := result.ResponseBody.(*models.CategoryNode)
+				assert.Equal(t, tt.category, node.Name)
+			}
+			if !tt.reloaded {
+				mockDB.AssertNotCalled(t, "ListCategories", mock.Anything)
+				return
+			}
+			// a miss in the cached tree is checked once against the DB and the fresh tree is cached
+			mockDB.AssertNumberOfCalls(t, "ListCategories", 1)
+			cached, _ := cache.GetCategoryTree(context.Background())
+			assert.Len(t, cached[1].Children, 3)
+		})
+	}
+}
+
+func TestGetCategories_ProcessMsg_CacheErrorFallsBackToDB(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetCategories(mockCache, mockDB)
+
+	mockCache.On("GetCategoryTree", mock.Anything).Return(nil, errors.New("redis down"))
+	mockCache.On("SetCategoryTree", mock.Anything, mock.Anything, services.CategoryTreeTTL).Return(errors.New("redis down"))
+	mockDB.On("ListCategories", mock.Anything).Return(categoryFixture(), nil)
+
+	resp, err := service.ProcessMsg(nil, httptest.NewRequest("GET", "/categories", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, resp.(models.Result).ResponseCode)
+	mockCache.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+}
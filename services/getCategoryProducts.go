@@ -0,0 +1,160 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/money"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// GetCategoryProducts lists the products of a category with the same filters and paging as GET /products.
+// With include_descendants=true the products of every subcategory are listed too.
+type GetCategoryProducts struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+	// Converter converts the prices when the list is asked for with ?currency
+	Converter *money.Converter
+	// AdminToken is the X-Admin-Token include_deleted requires, empty forbids the flag to everyone
+	AdminToken string
+}
+
+func NewGetCategoryProducts(redis db.CacheInterface, pgdb db.DBOperations) *GetCategoryProducts {
+	return &GetCategoryProducts{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+		Converter:      noConversion(),
+	}
+}
+
+func (b *GetCategoryProducts) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered GetCategoryProducts Decode")
+	slog.Debug("Exit GetCategoryProducts Decode")
+	return nil, nil
+}
+
+func (b *GetCategoryProducts) Validate(v interface{}) error {
+	slog.Debug("Entered GetCategoryProducts Validate")
+	slog.Debug("Exit GetCategoryProducts Validate")
+	return nil
+}
+
+func (b *GetCategoryProducts) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered GetCategoryProducts ProcessMsg")
+	query := r.URL.Query()
+
+	categoryId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return categoryProductsResult(enum.FailureCode400, enum.FailureMessage400, "Invalid category ID", models.PaginationProductResponse{}), nil
+	}
+
+	filter, err := ParseProductFilter(query)
+	if err != nil {
+		slog.WarnContext(ctx, "Error in ParseProductFilter", "error", err)
+		return categoryProductsResult(enum.FailureCode400, enum.FailureMessage400, err.Error(), models.PaginationProductResponse{}), nil
+	}
+	if filter.IncludeDeleted && !utils.IsAdmin(r, b.AdminToken) {
+		slog.WarnContext(ctx, "include_deleted without admin token")
+		return categoryProductsResult(enum.FailureCode403, enum.FailureMessage403, includeDeletedForbidden, models.PaginationProductResponse{}), nil
+	}
+	currency, err := ParseCurrency(query)
+	if err != nil {
+		return categoryProductsResult(enum.FailureCode400, enum.FailureMessage400, err.Error(), models.PaginationProductResponse{}), nil
+	}
+	var includeDescendants bool
+	if value := query.Get("include_descendants"); value != "" {
+		includeDescendants, err = strconv.ParseBool(value)
+		if err != nil {
+			description := fmt.Sprintf("invalid include_descendants: %v", value)
+			return categoryProductsResult(enum.FailureCode400, enum.FailureMessage400, description, models.PaginationProductResponse{}), nil
+		}
+	}
+
+	node, err := loadCategory(ctx, b.RedisConnector, b.PGDBConnector, categoryId)
+	if err != nil {
+		code, status, description := dbFailure(err)
+		return categoryProductsResult(code, status, description, models.PaginationProductResponse{}), nil
+	}
+	if node == nil {
+		return categoryProductsResult(enum.FailureCode404, enum.FailureMessage404, "Category not found", models.PaginationProductResponse{}), nil
+	}
+	filter.CategoryIDs = []int{categoryId}
+	if includeDescendants {
+		filter.CategoryIDs = descendantIDs(node)
+	}
+
+	count, err := b.PGDBConnector.GetProductCount(ctx, filter)
+	if err != nil {
+		code, status, description := dbFailure(err)
+		return categoryProductsResult(code, status, description, models.PaginationProductResponse{}), nil
+	}
+	if count == 0 {
+		return categoryProductsResult(enum.SuccessCode, enum.SuccessMessage, "No Products Found", models.PaginationProductResponse{}), nil
+	}
+
+	pageBody, e := PagenationFunction(query.Get("page"), query.Get("page_size"), count)
+	if e != nil {
+		slog.WarnContext(ctx, "Error in PagenationFunction", "error", e)
+		return categoryProductsResult(enum.FailureCode500, enum.FailureMessage500, "Error in PagenationFunction", models.PaginationProductResponse{}), nil
+	}
+	response := pageBody.(models.PaginationProductResponse)
+
+	products, err := b.PGDBConnector.GetAllProducts(ctx, filter, response.Offset, response.PageSize)
+	if err != nil {
+		code, status, description := dbFailure(err)
+		return categoryProductsResult(code, status, description, models.PaginationProductResponse{}), nil
+	}
+	if currency != "" {
+		if products, err = convertProducts(ctx, b.Converter, products, currency); err != nil {
+			code, status, description := conversionFailure(ctx, err)
+			return categoryProductsResult(code, status, description, models.PaginationProductResponse{}), nil
+		}
+	}
+	response.Products = products
+
+	slog.DebugContext(ctx, "Exiting GetCategoryProducts ProcessMsg")
+	return categoryProductsResult(enum.SuccessCode, enum.SuccessMessage, "Products fetched successfully", response), nil
+}
+
+func (b *GetCategoryProducts) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered GetCategoryProducts Encode")
+
+	format, ok := v.(models.PaginatedResponse)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.PaginatedResponse", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.PaginatedResponse but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit GetCategoryProducts Encode")
+	return data, statusCode, nil
+}
+
+func categoryProductsResult(code string, status string, description string, body models.PaginationProductResponse) models.PaginatedResponse {
+	return models.PaginatedResponse{
+		ResponseCode:        code,
+		ResponseStatus:      status,
+		ResponseDescription: description,
+		ResponseBody:        body,
+	}
+}
@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetCategoryProducts_ProcessMsg(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		categoryIDs []int
+	}{
+		{"category only", "/categories/1/products?page=2&page_size=1", []int{1}},
+		{"with descendants", "/categories/1/products?page=2&page_size=1&include_descendants=true", []int{1, 3, 2, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := mocks.NewMemoryCache()
+			_ = cache.SetCategoryTree(context.Background(), services.BuildCategoryTree(categoryFixture()), services.CategoryTreeTTL)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetCategoryProducts(cache, mockDB)
+
+			filter := models.ProductFilter{CategoryIDs: tt.categoryIDs}
+			products := []*models.Product{{ID: 7, Name: "Phone", Price: 100}}
+			mockDB.On("GetProductCount", mock.Anything, filter).Return(3, nil)
+			mockDB.On("GetAllProducts", mock.Anything, filter, 1, 1).Return(products, nil)
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", tt.url, nil), map[string]string{"id": "1"})
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.PaginatedResponse)
+			assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+			assert.Equal(t, 2, result.ResponseBody.PageNo)
+			assert.Equal(t, 3, result.ResponseBody.TotalPages)
+			assert.Equal(t, products, result.ResponseBody.Products)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetCategoryProducts_ProcessMsg_CategoryCreatedSinceCached(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	_ = cache.SetCategoryTree(context.Background(), services.BuildCategoryTree(categoryFixture()), services.CategoryTreeTTL)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetCategoryProducts(cache, mockDB)
+
+	filter := models.ProductFilter{CategoryIDs: []int{6}}
+	mockDB.On("ListCategories", mock.Anything).Return(createdSinceCached(), nil).Once()
+	mockDB.On("GetProductCount", mock.Anything, filter).Return(0, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/categories/6/products", nil), map[string]string{"id": "6"})
+	resp, err := service.ProcessMsg(nil, req)
+
+	assert.NoError(t, err)
+	result := resp.(models.PaginatedResponse)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, "No Products Found", result.ResponseDescription)
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetCategoryProducts_ProcessMsg_Failures(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		url  string
+		code string
+	}{
+		{"unknown category", "42", "/categories/42/products", enums.FailureCode404},
+		{"invalid id", "abc", "/categories/abc/products", enums.FailureCode400},
+		{"invalid include_descendants", "1", "/categories/1/products?include_descendants=maybe", enums.FailureCode400},
+		{"invalid filter", "1", "/categories/1/products?min_price=-1", enums.FailureCode400},
+		{"include_deleted without admin token", "1", "/categories/1/products?include_deleted=true", enums.FailureCode403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := mocks.NewMemoryCache()
+			_ = cache.SetCategoryTree(context.Background(), services.BuildCategoryTree(categoryFixture()), services.CategoryTreeTTL)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetCategoryProducts(cache, mockDB)
+
+			mockDB.On("ListCategories", mock.Anything).Return(categoryFixture(), nil).Maybe()
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", tt.url, nil), map[string]string{"id": tt.id})
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, resp.(models.PaginatedResponse).ResponseCode)
+			mockDB.AssertNotCalled(t, "GetProductCount", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// GetInventory serves GET /products/{id}/inventory with the stock of the product in every warehouse
+type GetInventory struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewGetInventory(redis db.CacheInterface, pgdb db.DBOperations) *GetInventory {
+	return &GetInventory{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *GetInventory) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered GetInventory Decode")
+	slog.Debug("Exit GetInventory Decode")
+	return nil, nil
+}
+
+func (b *GetInventory) Validate(v interface{}) error {
+	slog.Debug("Entered GetInventory Validate")
+	slog.Debug("Exit GetInventory Validate")
+	return nil
+}
+
+func (b *GetInventory) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered GetInventory ProcessMsg")
+	productId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return inventoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+
+	inventory, err := b.PGDBConnector.GetInventory(ctx, productId)
+	if err != nil {
+		return inventoryFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting GetInventory ProcessMsg")
+	return inventoryResult(enum.SuccessCode, enum.SuccessMessage, "Inventory fetched successfully", inventory), nil
+}
+
+func (b *GetInventory) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered GetInventory Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit GetInventory Encode")
+	return data, statusCode, nil
+}
+
+func inventoryFailure(err error) models.Result {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return inventoryResult(enum.FailureCode404, enum.FailureMessage404, "Product not found", nil)
+	case errors.Is(err, db.ErrInsufficientStock):
+		return inventoryResult(enum.FailureCode409, enum.FailureMessage409, "Insufficient stock", nil)
+	case errors.Is(err, db.ErrReservationNotFound):
+		return inventoryResult(enum.FailureCode404, enum.FailureMessage404, "Reservation not found", nil)
+	}
+	code, status, description := dbFailure(err)
+	return inventoryResult(code, status, description, nil)
+}
+
+func inventoryResult(code string, status string, description string, body interface{}) models.Result {
+	return models.Result{
+		ResponseCode:        code,
+		ResponseStatus:      status,
+		ResponseDescription: description,
+		ResponseBody:        body,
+	}
+}
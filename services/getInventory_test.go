@@ -0,0 +1,127 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"database/sql"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetInventory_ProcessMsg(t *testing.T) {
+	tests := []struct {
+		name      string
+		inventory *models.Inventory
+		err       error
+		code      string
+	}{
+		{"found", &models.Inventory{ProductID: 7, OnHand: 10, Reserved: 4, Available: 6, Warehouses: []*models.StockLevel{
+			{Warehouse: "berlin", OnHand: 10, Reserved: 4, Available: 6},
+		}}, nil, enums.SuccessCode},
+		{"product not found", nil, sql.ErrNoRows, enums.FailureCode404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetInventory(new(mocks.MockCacheInterface), mockDB)
+
+			mockDB.On("GetInventory", mock.Anything, 7).Return(tt.inventory, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/products/7/inventory", nil), map[string]string{"id": "7"})
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			if tt.inventory != nil {
+				assert.Equal(t, tt.inventory, result.ResponseBody)
+			}
+		})
+	}
+}
+
+func TestSetStockLevel_Validate(t *testing.T) {
+	service := services.NewSetStockLevel(new(mocks.MockCacheInterface), new(mocks.MockDBOperations))
+
+	for body, valid := range map[string]bool{
+		`{"on_hand":12}`: true,
+		`{"on_hand":0}`:  true,
+		`{}`:             false,
+		`{"on_hand":-1}`: false,
+	} {
+		format, err := service.Decode([]byte(body))
+		assert.NoError(t, err)
+		assert.Equal(t, valid, service.Validate(format) == nil, body)
+	}
+}
+
+func TestSetStockLevel_ProcessMsg(t *testing.T) {
+	tests := []struct {
+		name      string
+		warehouse string
+		err       error
+		code      string
+	}{
+		{"set", "berlin", nil, enums.SuccessCode},
+		{"below reserved", "berlin", db.ErrInsufficientStock, enums.FailureCode409},
+		{"product not found", "berlin", sql.ErrNoRows, enums.FailureCode404},
+		{"invalid warehouse", "ber.lin", nil, enums.FailureCode400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewSetStockLevel(new(mocks.MockCacheInterface), mockDB)
+
+			level := &models.StockLevel{Warehouse: "berlin", OnHand: 12, Reserved: 2, Available: 10}
+			mockDB.On("SetStockLevel", mock.Anything, 7, "berlin", 12).Return(level, tt.err)
+
+			onHand := 12
+			req := mux.SetURLVars(httptest.NewRequest("PUT", "/products/7/inventory/"+tt.warehouse, nil), map[string]string{"id": "7", "warehouse": tt.warehouse})
+			resp, err := service.ProcessMsg(&models.StockLevelRequest{OnHand: &onHand}, req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, resp.(models.Result).ResponseCode)
+		})
+	}
+}
+
+func TestAdjustStock_ProcessMsg_DefaultWarehouse(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewAdjustStock(new(mocks.MockCacheInterface), mockDB)
+
+	level := &models.StockLevel{Warehouse: models.DefaultWarehouse, OnHand: 3, Available: 3}
+	mockDB.On("AdjustStockLevel", mock.Anything, 7, models.DefaultWarehouse, -2).Return(level, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest("POST", "/products/7/inventory/adjustments", nil), map[string]string{"id": "7"})
+	resp, err := service.ProcessMsg(&models.StockAdjustmentRequest{Delta: -2}, req)
+
+	assert.NoError(t, err)
+	result := resp.(models.Result)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, level, result.ResponseBody)
+	mockDB.AssertExpectations(t)
+}
+
+func TestAdjustStock_Validate(t *testing.T) {
+	service := services.NewAdjustStock(new(mocks.MockCacheInterface), new(mocks.MockDBOperations))
+
+	for body, valid := range map[string]bool{
+		`{"delta":5}`:                       true,
+		`{"delta":-5,"warehouse":"berlin"}`: true,
+		`{"delta":0}`:                       false,
+		`{"delta":5,"warehouse":"a/b"}`:     false,
+		`{"delta":5000000000}`:              false,
+	} {
+		format, err := service.Decode([]byte(body))
+		assert.NoError(t, err)
+		assert.Equal(t, valid, service.Validate(format) == nil, body)
+	}
+}
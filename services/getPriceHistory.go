@@ -0,0 +1,124 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// GetPriceHistory serves GET /products/{id}/prices, the product's price changes newest first,
+// paged with page and page_size like GET /products
+type GetPriceHistory struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewGetPriceHistory(redis db.CacheInterface, pgdb db.DBOperations) *GetPriceHistory {
+	return &GetPriceHistory{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *GetPriceHistory) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered GetPriceHistory Decode")
+	slog.Debug("Exit GetPriceHistory Decode")
+	return nil, nil
+}
+
+func (b *GetPriceHistory) Validate(v interface{}) error {
+	slog.Debug("Entered GetPriceHistory Validate")
+	slog.Debug("Exit GetPriceHistory Validate")
+	return nil
+}
+
+func (b *GetPriceHistory) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered GetPriceHistory ProcessMsg")
+	query := r.URL.Query()
+
+	productId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return priceHistoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", models.PaginationPriceResponse{}), nil
+	}
+
+	count, err := b.PGDBConnector.GetPriceHistoryCount(ctx, productId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return priceHistoryResult(enum.FailureCode404, enum.FailureMessage404, "Product not found", models.PaginationPriceResponse{}), nil
+	}
+	if err != nil {
+		code, status, description := dbFailure(err)
+		return priceHistoryResult(code, status, description, models.PaginationPriceResponse{}), nil
+	}
+	if count == 0 {
+		body := models.PaginationPriceResponse{Prices: []*models.PriceChange{}}
+		return priceHistoryResult(enum.SuccessCode, enum.SuccessMessage, "No Price Changes Found", body), nil
+	}
+
+	pageBody, e := PagenationFunction(query.Get("page"), query.Get("page_size"), count)
+	if e != nil {
+		slog.WarnContext(ctx, "Error in PagenationFunction", "error", e)
+		return priceHistoryResult(enum.FailureCode500, enum.FailureMessage500, "Error in PagenationFunction", models.PaginationPriceResponse{}), nil
+	}
+	page := pageBody.(models.PaginationProductResponse)
+	response := models.PaginationPriceResponse{
+		PageNo:     page.PageNo,
+		PageSize:   page.PageSize,
+		TotalCount: page.TotalCount,
+		TotalPages: page.TotalPages,
+		Offset:     page.Offset,
+	}
+
+	response.Prices, err = b.PGDBConnector.GetPriceHistory(ctx, productId, response.Offset, response.PageSize)
+	if err != nil {
+		code, status, description := dbFailure(err)
+		return priceHistoryResult(code, status, description, models.PaginationPriceResponse{}), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting GetPriceHistory ProcessMsg")
+	return priceHistoryResult(enum.SuccessCode, enum.SuccessMessage, "Price history fetched successfully", response), nil
+}
+
+func (b *GetPriceHistory) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered GetPriceHistory Encode")
+
+	format, ok := v.(models.PaginatedPriceResponse)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.PaginatedPriceResponse", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.PaginatedPriceResponse but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit GetPriceHistory Encode")
+	return data, statusCode, nil
+}
+
+func priceHistoryResult(code string, status string, description string, body models.PaginationPriceResponse) models.PaginatedPriceResponse {
+	return models.PaginatedPriceResponse{
+		ResponseCode:        code,
+		ResponseStatus:      status,
+		ResponseDescription: description,
+		ResponseBody:        body,
+	}
+}
@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"database/sql"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetPriceHistory_ProcessMsg(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetPriceHistory(new(mocks.MockCacheInterface), mockDB)
+
+	changes := []*models.PriceChange{{ID: 2, ProductID: 7, OldPrice: 100, NewPrice: 90, OldCurrency: "USD", NewCurrency: "USD",
+		ChangedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), Actor: "alice"}}
+	mockDB.On("GetPriceHistoryCount", mock.Anything, 7).Return(3, nil)
+	mockDB.On("GetPriceHistory", mock.Anything, 7, 1, 1).Return(changes, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/products/7/prices?page=2&page_size=1", nil), map[string]string{"id": "7"})
+	resp, err := service.ProcessMsg(nil, req)
+
+	assert.NoError(t, err)
+	result := resp.(models.PaginatedPriceResponse)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, 2, result.ResponseBody.PageNo)
+	assert.Equal(t, 3, result.ResponseBody.TotalPages)
+	assert.Equal(t, changes, result.ResponseBody.Prices)
+	mockDB.AssertExpectations(t)
+
+	_, statusCode, err := service.Encode(result)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, statusCode)
+}
+
+func TestGetPriceHistory_ProcessMsg_NoChanges(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetPriceHistory(new(mocks.MockCacheInterface), mockDB)
+	mockDB.On("GetPriceHistoryCount", mock.Anything, 7).Return(0, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/products/7/prices", nil), map[string]string{"id": "7"})
+	resp, err := service.ProcessMsg(nil, req)
+
+	assert.NoError(t, err)
+	result := resp.(models.PaginatedPriceResponse)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Empty(t, result.ResponseBody.Prices)
+	mockDB.AssertNotCalled(t, "GetPriceHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetPriceHistory_ProcessMsg_Failures(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		err  error
+		code string
+	}{
+		{"product not found", "7", sql.ErrNoRows, enums.FailureCode404},
+		{"db error", "7", errors.New("db error"), enums.FailureCode500},
+		{"invalid id", "abc", nil, enums.FailureCode400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetPriceHistory(new(mocks.MockCacheInterface), mockDB)
+			mockDB.On("GetPriceHistoryCount", mock.Anything, 7).Return(0, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/products/"+tt.id+"/prices", nil), map[string]string{"id": tt.id})
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, resp.(models.PaginatedPriceResponse).ResponseCode)
+			mockDB.AssertNotCalled(t, "GetPriceHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
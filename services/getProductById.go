@@ -3,46 +3,56 @@ package services
 import (
 	"ProductService/db"
 	"ProductService/models"
+	"ProductService/money"
 	enum "ProductService/utils/enums"
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gorilla/mux"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
 )
 
+// GetProdById serves GET /products/{id}. With ?as_of=<RFC 3339 timestamp> the product comes with
+// the price and currency it had at that moment, read from the price history, and with ?currency=<ISO 4217 code>
+// its prices are converted to that currency.
 type GetProdById struct {
 	RedisConnector db.CacheInterface
 	PGDBConnector  db.DBOperations
+	Converter      *money.Converter
 }
 
 func NewGetProdById(redis db.CacheInterface, pgdb db.DBOperations) *GetProdById {
 	return &GetProdById{
 		RedisConnector: redis,
 		PGDBConnector:  pgdb,
+		Converter:      noConversion(),
 	}
 }
 
 func (b *GetProdById) Decode(data []byte) (interface{}, error) {
-	log.Printf("Entered GetProdById Decode")
-	log.Printf("Exit GetProdById Decode")
+	slog.Debug("Entered GetProdById Decode")
+	slog.Debug("Exit GetProdById Decode")
 	return nil, nil
 }
 
 func (b *GetProdById) Validate(v interface{}) error {
-	log.Printf("Entered GetProdById Validate")
-	log.Printf("Exit GetProdById Validate")
+	slog.Debug("Entered GetProdById Validate")
+	slog.Debug("Exit GetProdById Validate")
 	return nil
 }
 
 func (b *GetProdById) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
-	log.Printf("Entered GetProdById ProcessMsg")
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered GetProdById ProcessMsg")
 	var product *models.Product
 	vars := mux.Vars(r)
 	productIdStr := vars["id"]
-	log.Println("Product ID:", productIdStr)
+	slog.DebugContext(ctx, "Product ID", "id", productIdStr)
 	// Validate product ID
 	productId, err := strconv.Atoi(productIdStr)
 	if err != nil {
@@ -54,42 +64,59 @@ func (b *GetProdById) ProcessMsg(v interface{}, r *http.Request) (interface{}, e
 		}
 		return msg, nil
 	}
-	log.Printf("done 1")
-	product, err = b.RedisConnector.GetProductByID(productIdStr)
+	var asOf time.Time
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		asOf, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			msg := models.Result{
+				ResponseCode:        enum.FailureCode400,
+				ResponseStatus:      enum.FailureMessage400,
+				ResponseDescription: fmt.Sprintf("invalid as_of: %v", value),
+				ResponseBody:        nil,
+			}
+			return msg, nil
+		}
+	}
+	currency, err := ParseCurrency(r.URL.Query())
 	if err != nil {
 		msg := models.Result{
-			ResponseCode:        enum.FailureCode500,
-			ResponseStatus:      enum.FailureMessage500,
-			ResponseDescription: enum.FailureMessage500,
+			ResponseCode:        enum.FailureCode400,
+			ResponseStatus:      enum.FailureMessage400,
+			ResponseDescription: err.Error(),
 			ResponseBody:        nil,
 		}
 		return msg, nil
 	}
-	log.Printf("done 2")
+	// the cache is only an accelerator, a Redis outage falls back to Postgres
+	product, err = b.RedisConnector.GetProductByID(ctx, productIdStr)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read product from cache", "id", productIdStr, "error", err)
+		product = nil
+	}
 
 	if product == nil {
-		log.Println("Product Not Found in Cache")
-		product, err = b.PGDBConnector.GetProductByID(productId)
+		slog.DebugContext(ctx, "Product Not Found in Cache")
+		product, err = b.PGDBConnector.GetProductByID(ctx, productId)
 		if err != nil {
+			code, status, description := dbFailure(err)
 			msg := models.Result{
-				ResponseCode:        enum.FailureCode500,
-				ResponseStatus:      enum.FailureMessage500,
-				ResponseDescription: "Database Error",
+				ResponseCode:        code,
+				ResponseStatus:      status,
+				ResponseDescription: description,
 				ResponseBody:        nil,
 			}
 			return msg, nil
 		}
 
 		if product != nil {
-			err = b.RedisConnector.SetProductByID(productIdStr, product, time.Minute)
-			if err != nil {
-				return nil, err
+			if err = b.RedisConnector.SetProductByID(ctx, productIdStr, product, db.ProductCacheTTL); err != nil {
+				slog.WarnContext(ctx, "Failed to store product in cache", "id", productIdStr, "error", err)
 			}
 		}
 	}
 
 	if product == nil {
-		log.Println("Product Not Found in DB")
+		slog.DebugContext(ctx, "Product Not Found in DB")
 		msg := models.Result{
 			ResponseCode:        enum.FailureCode404,
 			ResponseStatus:      enum.FailureMessage404,
@@ -99,47 +126,112 @@ func (b *GetProdById) ProcessMsg(v interface{}, r *http.Request) (interface{}, e
 		return msg, nil
 	}
 
+	// the stored representation keeps its ETag, the historical price may be in another currency though
+	if asOf.IsZero() && currency == product.Currency {
+		currency = ""
+	}
+	if !asOf.IsZero() || currency != "" {
+		return b.priceView(ctx, product, asOf, currency), nil
+	}
+
+	etag := ETag(product.Version)
+	if ifNoneMatch(r, etag) {
+		slog.DebugContext(ctx, "Product not modified", "etag", etag)
+		msg := models.Result{
+			ResponseCode:        enum.NotModifiedCode,
+			ResponseStatus:      enum.NotModifiedMessage,
+			ResponseDescription: "Product not modified",
+			ResponseBody:        nil,
+			Header:              etagHeader(etag),
+		}
+		return msg, nil
+	}
+
 	msg := models.Result{
 		ResponseCode:        enum.SuccessCode,
 		ResponseStatus:      enum.SuccessMessage,
 		ResponseDescription: "Product fetched successfully",
 		ResponseBody:        product,
+		Header:              etagHeader(etag),
 	}
-	log.Printf("Exiting GetProdById ProcessMsg")
+	slog.DebugContext(ctx, "Exiting GetProdById ProcessMsg")
 	return msg, nil
 }
 
+// priceView returns a copy of the product carrying the price it had at asOf, unless asOf is zero, converted to
+// currency, unless it is empty. The response has no ETag, the version belongs to the stored product and price.
+func (b *GetProdById) priceView(ctx context.Context, product *models.Product, asOf time.Time, currency string) models.Result {
+	if !asOf.IsZero() {
+		price, err := b.PGDBConnector.GetPriceAt(ctx, product.ID, asOf)
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, db.ErrNoPriceAt) {
+			return models.Result{
+				ResponseCode:        enum.FailureCode404,
+				ResponseStatus:      enum.FailureMessage404,
+				ResponseDescription: "Product had no price at that time",
+				ResponseBody:        nil,
+			}
+		}
+		if err != nil {
+			code, status, description := dbFailure(err)
+			return models.Result{
+				ResponseCode:        code,
+				ResponseStatus:      status,
+				ResponseDescription: description,
+				ResponseBody:        nil,
+			}
+		}
+
+		historical := *product
+		historical.Price = price.Price
+		historical.Currency = price.Currency
+		product = &historical
+	}
+
+	if currency != "" {
+		converted, err := convertProducts(ctx, b.Converter, []*models.Product{product}, currency)
+		if err != nil {
+			code, status, description := conversionFailure(ctx, err)
+			return models.Result{
+				ResponseCode:        code,
+				ResponseStatus:      status,
+				ResponseDescription: description,
+				ResponseBody:        nil,
+			}
+		}
+		product = converted[0]
+	}
+
+	return models.Result{
+		ResponseCode:        enum.SuccessCode,
+		ResponseStatus:      enum.SuccessMessage,
+		ResponseDescription: "Product fetched successfully",
+		ResponseBody:        product,
+	}
+}
+
 func (b *GetProdById) Encode(v interface{}) ([]byte, int, error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Panic occurred: %v", r)
+			slog.Error("Panic occurred", "panic", r)
 		}
 	}()
-	log.Printf("Entered GetProdById Encode")
+	slog.Debug("Entered GetProdById Encode")
 
 	format, ok := v.(models.Result)
 	if !ok {
-		log.Printf("Type assertion failed: expected models.Result but got %T", v)
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
 		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
 	}
 
 	data, err := json.Marshal(&format)
 	if err != nil {
-		log.Println("Error in Marshal", err)
+		slog.Error("Error in Marshal", "error", err)
 		return nil, http.StatusInternalServerError, err
 	}
 
 	// Decide HTTP status code based on ResponseCode
-	statusCode := http.StatusOK // default 200
-	switch format.ResponseCode {
-	case "400":
-		statusCode = http.StatusBadRequest
-	case "404":
-		statusCode = http.StatusNotFound
-	case "500":
-		statusCode = http.StatusInternalServerError
-	}
+	statusCode := httpStatus(format.ResponseCode)
 
-	log.Printf("Exit GetProdById Encode")
+	slog.Debug("Exit GetProdById Encode")
 	return data, statusCode, nil
 }
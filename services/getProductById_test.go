@@ -1,12 +1,18 @@
 package services_test
 
 import (
+	"ProductService/db"
 	"ProductService/db/mocks"
 	"ProductService/models"
+	"ProductService/money"
 	"ProductService/services"
 	enum "ProductService/utils/enums"
+	"ProductService/utils/logger"
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,6 +20,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetProdById_ProcessMsg_CacheHit(t *testing.T) {
@@ -24,7 +31,7 @@ func TestGetProdById_ProcessMsg_CacheHit(t *testing.T) {
 	product := &models.Product{ID: 1, Name: "Cached Product", Price: 100}
 
 	// Set up mocks
-	mockCache.On("GetProductByID", "1").Return(product, nil)
+	mockCache.On("GetProductByID", mock.Anything, "1").Return(product, nil)
 
 	// Create a fake request with ID in URL
 	req := httptest.NewRequest("GET", "/products/1", nil)
@@ -48,9 +55,9 @@ func TestGetProdById_ProcessMsg_CacheMiss_DBHit(t *testing.T) {
 	product := &models.Product{ID: 1, Name: "DB Product", Price: 200}
 
 	// Set up mocks
-	mockCache.On("GetProductByID", "1").Return(nil, nil)
-	mockDB.On("GetProductByID", 1).Return(product, nil)
-	mockCache.On("SetProductByID", "1", product, time.Minute).Return(nil)
+	mockCache.On("GetProductByID", mock.Anything, "1").Return(nil, nil)
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(product, nil)
+	mockCache.On("SetProductByID", mock.Anything, "1", product, time.Minute).Return(nil)
 
 	req := httptest.NewRequest("GET", "/products/1", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
@@ -72,8 +79,8 @@ func TestGetProdById_ProcessMsg_CacheMiss_DBNotFound(t *testing.T) {
 	service := services.NewGetProdById(mockCache, mockDB)
 
 	// Set up mocks
-	mockCache.On("GetProductByID", "1").Return(nil, nil)
-	mockDB.On("GetProductByID", 1).Return(nil, nil)
+	mockCache.On("GetProductByID", mock.Anything, "1").Return(nil, nil)
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(nil, nil)
 
 	req := httptest.NewRequest("GET", "/products/1", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
@@ -108,7 +115,12 @@ func TestGetProdById_ProcessMsg_CacheError(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewGetProdById(mockCache, mockDB)
 
-	mockCache.On("GetProductByID", "1").Return(nil, errors.New("cache error"))
+	product := &models.Product{ID: 1, Name: "DB Product", Price: 200}
+
+	// a Redis outage falls back to the DB
+	mockCache.On("GetProductByID", mock.Anything, "1").Return(nil, errors.New("cache error"))
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(product, nil)
+	mockCache.On("SetProductByID", mock.Anything, "1", product, time.Minute).Return(errors.New("cache error"))
 
 	req := httptest.NewRequest("GET", "/products/1", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
@@ -117,8 +129,11 @@ func TestGetProdById_ProcessMsg_CacheError(t *testing.T) {
 
 	assert.NoError(t, err)
 	result := resp.(models.Result)
-	assert.Equal(t, enum.FailureCode500, result.ResponseCode)
-	assert.Equal(t, enum.FailureMessage500, result.ResponseDescription)
+	assert.Equal(t, enum.SuccessCode, result.ResponseCode)
+	assert.Equal(t, product, result.ResponseBody)
+
+	mockCache.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
 }
 
 func TestGetProdById_ProcessMsg_DBError(t *testing.T) {
@@ -126,8 +141,8 @@ func TestGetProdById_ProcessMsg_DBError(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewGetProdById(mockCache, mockDB)
 
-	mockCache.On("GetProductByID", "1").Return(nil, nil)
-	mockDB.On("GetProductByID", 1).Return(nil, errors.New("db error"))
+	mockCache.On("GetProductByID", mock.Anything, "1").Return(nil, nil)
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(nil, errors.New("db error"))
 
 	req := httptest.NewRequest("GET", "/products/1", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
@@ -143,6 +158,54 @@ func TestGetProdById_ProcessMsg_DBError(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestGetProdById_ProcessMsg_DBTimeout(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetProdById(mockCache, mockDB)
+
+	mockCache.On("GetProductByID", mock.Anything, "1").Return(nil, nil)
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(nil, fmt.Errorf("query failed: %w", context.DeadlineExceeded))
+
+	req := httptest.NewRequest("GET", "/products/1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	assert.NoError(t, err)
+	result := resp.(models.Result)
+	assert.Equal(t, enum.FailureCode504, result.ResponseCode)
+	assert.Equal(t, "Database Timeout", result.ResponseDescription)
+
+	_, statusCode, err := service.Encode(result)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusGatewayTimeout, statusCode)
+
+	mockCache.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetProdById_ProcessMsg_PassesRequestContext(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetProdById(mockCache, mockDB)
+
+	fromRequest := mock.MatchedBy(func(ctx context.Context) bool {
+		return logger.RequestID(ctx) == "req-1"
+	})
+	mockCache.On("GetProductByID", fromRequest, "1").Return(nil, nil)
+	mockDB.On("GetProductByID", fromRequest, 1).Return(nil, nil)
+
+	req := httptest.NewRequest("GET", "/products/1", nil)
+	req = req.WithContext(logger.WithRequestID(req.Context(), "req-1"))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	_, err := service.ProcessMsg(nil, req)
+
+	assert.NoError(t, err)
+	mockCache.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+}
+
 func TestGetProdById_Encode(t *testing.T) {
 	service := services.NewGetProdById(nil, nil)
 
@@ -182,20 +245,199 @@ func TestGetProdById_ProcessMsg_CacheMiss_DBHit_SetProductError(t *testing.T) {
 	product := &models.Product{ID: 1, Name: "DB Product", Price: 200}
 
 	// Set up mocks
-	mockCache.On("GetProductByID", "1").Return(nil, nil)
-	mockDB.On("GetProductByID", 1).Return(product, nil)
-	mockCache.On("SetProductByID", "1", product, time.Minute).Return(errors.New("redis set error"))
+	mockCache.On("GetProductByID", mock.Anything, "1").Return(nil, nil)
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(product, nil)
+	mockCache.On("SetProductByID", mock.Anything, "1", product, time.Minute).Return(errors.New("redis set error"))
 
 	req := httptest.NewRequest("GET", "/products/1", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
 
 	resp, err := service.ProcessMsg(nil, req)
 
-	// In this case, we expect an error to be returned directly
-	assert.Nil(t, resp)
-	assert.Error(t, err)
-	assert.EqualError(t, err, "redis set error")
+	// the product is still served when it can't be cached
+	assert.NoError(t, err)
+	result := resp.(models.Result)
+	assert.Equal(t, enum.SuccessCode, result.ResponseCode)
+	assert.Equal(t, product, result.ResponseBody)
 
 	mockCache.AssertExpectations(t)
 	mockDB.AssertExpectations(t)
 }
+
+func TestGetProdById_ProcessMsg_SetsETag(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetProdById(mockCache, mockDB)
+
+	mockCache.On("GetProductByID", mock.Anything, "1").Return(&models.Product{ID: 1, Name: "Phone", Price: 100, Version: 3}, nil)
+
+	req := httptest.NewRequest("GET", "/products/1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	assert.NoError(t, err)
+	result := resp.(models.Result)
+	assert.Equal(t, enum.SuccessCode, result.ResponseCode)
+	assert.Equal(t, `"3"`, result.Header.Get("ETag"))
+}
+
+func TestGetProdById_ProcessMsg_IfNoneMatch(t *testing.T) {
+	tests := []struct {
+		ifNoneMatch string
+		code        string
+	}{
+		{`"3"`, enum.NotModifiedCode},
+		{`W/"3"`, enum.NotModifiedCode},
+		{`"1", "3"`, enum.NotModifiedCode},
+		{`*`, enum.NotModifiedCode},
+		{`"2"`, enum.SuccessCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ifNoneMatch, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			service := services.NewGetProdById(mockCache, new(mocks.MockDBOperations))
+			mockCache.On("GetProductByID", mock.Anything, "1").Return(&models.Product{ID: 1, Name: "Phone", Price: 100, Version: 3}, nil)
+
+			req := httptest.NewRequest("GET", "/products/1", nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			assert.Equal(t, `"3"`, result.Header.Get("ETag"))
+
+			_, statusCode, err := service.Encode(result)
+			assert.NoError(t, err)
+			if tt.code == enum.NotModifiedCode {
+				assert.Equal(t, http.StatusNotModified, statusCode)
+				assert.Nil(t, result.ResponseBody)
+			}
+		})
+	}
+}
+
+func TestGetProdById_ProcessMsg_CachesVariants(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetProdById(cache, mockDB)
+
+	price := 120.0
+	product := &models.Product{ID: 1, Name: "Shirt", Price: 100, Version: 3, Variants: []*models.ProductVariant{
+		{ID: 4, ProductID: 1, SKU: "SHIRT-M", Options: models.VariantOptions{"size": "M"}, Available: true},
+		{ID: 5, ProductID: 1, SKU: "SHIRT-L", Options: models.VariantOptions{"size": "L"}, Price: &price},
+	}}
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(product, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/products/1", nil), map[string]string{"id": "1"})
+		resp, err := service.ProcessMsg(nil, req)
+
+		assert.NoError(t, err)
+		result := resp.(models.Result)
+		assert.Equal(t, enum.SuccessCode, result.ResponseCode)
+		assert.Equal(t, product.Variants, result.ResponseBody.(*models.Product).Variants)
+	}
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetProdById_ProcessMsg_AsOf(t *testing.T) {
+	asOf := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		query string
+		price *models.EffectivePrice
+		err   error
+		code  string
+	}{
+		{"historical price", "?as_of=2026-03-01T12:00:00Z", &models.EffectivePrice{Price: 80, Currency: "EUR"}, nil, enum.SuccessCode},
+		{"no price yet", "?as_of=2026-03-01T12:00:00Z", nil, db.ErrNoPriceAt, enum.FailureCode404},
+		{"db error", "?as_of=2026-03-01T12:00:00Z", nil, errors.New("db error"), enum.FailureCode500},
+		{"invalid as_of", "?as_of=yesterday", nil, nil, enum.FailureCode400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetProdById(mockCache, mockDB)
+
+			product := &models.Product{ID: 1, Name: "Phone", Price: 100, Currency: "USD", Version: 3}
+			mockCache.On("GetProductByID", mock.Anything, "1").Return(product, nil)
+			mockDB.On("GetPriceAt", mock.Anything, 1, asOf).Return(tt.price, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/products/1"+tt.query, nil), map[string]string{"id": "1"})
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			assert.Empty(t, result.Header.Get("ETag"))
+			if tt.price != nil {
+				body := result.ResponseBody.(*models.Product)
+				assert.Equal(t, tt.price.Price, body.Price)
+				assert.Equal(t, tt.price.Currency, body.Currency)
+				// the cached product must not be touched
+				assert.Equal(t, 100.0, product.Price)
+			}
+		})
+	}
+}
+
+func TestGetProdById_ProcessMsg_Currency(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		code     string
+		price    float64
+		currency string
+	}{
+		{"converted", "?currency=EUR", enum.SuccessCode, 92.5, "EUR"},
+		{"own currency", "?currency=USD", enum.SuccessCode, 100, "USD"},
+		{"unknown rate", "?currency=JPY", enum.FailureCode400, 0, ""},
+		{"invalid currency", "?currency=euro", enum.FailureCode400, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetProdById(mockCache, mockDB)
+			service.Converter = money.NewConverter(money.NewStaticProvider(map[string]*big.Rat{
+				"USD": big.NewRat(1, 1),
+				"EUR": big.NewRat(925, 1000),
+			}), money.DefaultRounding())
+
+			product := &models.Product{ID: 1, Name: "Phone", Price: 100, Currency: "USD", Version: 3}
+			mockCache.On("GetProductByID", mock.Anything, "1").Return(product, nil).Maybe()
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/products/1"+tt.query, nil), map[string]string{"id": "1"})
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			if tt.code != enum.SuccessCode {
+				return
+			}
+			body := result.ResponseBody.(*models.Product)
+			assert.Equal(t, tt.price, body.Price)
+			assert.Equal(t, tt.currency, body.Currency)
+			if tt.currency == product.Currency {
+				assert.Nil(t, body.BasePrice)
+				assert.NotEmpty(t, result.Header.Get("ETag"))
+				return
+			}
+			assert.Equal(t, 100.0, *body.BasePrice)
+			assert.Equal(t, "USD", body.BaseCurrency)
+			assert.Empty(t, result.Header.Get("ETag"))
+			// the cached product must not be touched
+			assert.Equal(t, 100.0, product.Price)
+			assert.Equal(t, "USD", product.Currency)
+		})
+	}
+}
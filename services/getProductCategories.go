@@ -0,0 +1,88 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// GetProductCategories lists the categories a product is assigned to
+type GetProductCategories struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewGetProductCategories(redis db.CacheInterface, pgdb db.DBOperations) *GetProductCategories {
+	return &GetProductCategories{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *GetProductCategories) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered GetProductCategories Decode")
+	slog.Debug("Exit GetProductCategories Decode")
+	return nil, nil
+}
+
+func (b *GetProductCategories) Validate(v interface{}) error {
+	slog.Debug("Entered GetProductCategories Validate")
+	slog.Debug("Exit GetProductCategories Validate")
+	return nil
+}
+
+func (b *GetProductCategories) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered GetProductCategories ProcessMsg")
+	productId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return categoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+
+	categories, err := b.PGDBConnector.GetProductCategories(ctx, productId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return categoryResult(enum.FailureCode404, enum.FailureMessage404, "Product not found", nil), nil
+		}
+		code, status, description := dbFailure(err)
+		return categoryResult(code, status, description, nil), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting GetProductCategories ProcessMsg")
+	return categoryResult(enum.SuccessCode, enum.SuccessMessage, "Product categories fetched successfully", categories), nil
+}
+
+func (b *GetProductCategories) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered GetProductCategories Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit GetProductCategories Encode")
+	return data, statusCode, nil
+}
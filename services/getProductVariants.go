@@ -0,0 +1,133 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// GetProductVariants serves GET /products/{id}/variants with every variant of the product
+// and GET /products/{id}/variants/{variantId} with one variant
+type GetProductVariants struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewGetProductVariants(redis db.CacheInterface, pgdb db.DBOperations) *GetProductVariants {
+	return &GetProductVariants{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *GetProductVariants) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered GetProductVariants Decode")
+	slog.Debug("Exit GetProductVariants Decode")
+	return nil, nil
+}
+
+func (b *GetProductVariants) Validate(v interface{}) error {
+	slog.Debug("Entered GetProductVariants Validate")
+	slog.Debug("Exit GetProductVariants Validate")
+	return nil
+}
+
+func (b *GetProductVariants) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered GetProductVariants ProcessMsg")
+	productId, variantId, single, failure := variantPath(r)
+	if failure != nil {
+		return *failure, nil
+	}
+
+	if !single {
+		variants, err := b.PGDBConnector.ListProductVariants(ctx, productId)
+		if err != nil {
+			return variantFailure(err), nil
+		}
+		slog.DebugContext(ctx, "Exiting GetProductVariants ProcessMsg")
+		return variantResult(enum.SuccessCode, enum.SuccessMessage, "Variants fetched successfully", variants), nil
+	}
+
+	variant, err := b.PGDBConnector.GetProductVariant(ctx, productId, variantId)
+	if err != nil {
+		return variantFailure(err), nil
+	}
+	slog.DebugContext(ctx, "Exiting GetProductVariants ProcessMsg")
+	return variantResult(enum.SuccessCode, enum.SuccessMessage, "Variant fetched successfully", variant), nil
+}
+
+func (b *GetProductVariants) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered GetProductVariants Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit GetProductVariants Encode")
+	return data, statusCode, nil
+}
+
+// variantPath reads the product id and, when the route has one, the variant id from the URL
+func variantPath(r *http.Request) (productId int, variantId int, single bool, failure *models.Result) {
+	vars := mux.Vars(r)
+	productId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		msg := variantResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil)
+		return 0, 0, false, &msg
+	}
+	variantIdStr, single := vars["variantId"]
+	if !single {
+		return productId, 0, false, nil
+	}
+	variantId, err = strconv.Atoi(variantIdStr)
+	if err != nil {
+		msg := variantResult(enum.FailureCode400, enum.FailureMessage400, "Invalid variant ID", nil)
+		return 0, 0, false, &msg
+	}
+	return productId, variantId, true, nil
+}
+
+func variantFailure(err error) models.Result {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return variantResult(enum.FailureCode404, enum.FailureMessage404, "Product not found", nil)
+	case errors.Is(err, db.ErrVariantNotFound):
+		return variantResult(enum.FailureCode404, enum.FailureMessage404, "Variant not found", nil)
+	}
+	code, status, description := dbFailure(err)
+	return variantResult(code, status, description, nil)
+}
+
+func variantResult(code string, status string, description string, body interface{}) models.Result {
+	return models.Result{
+		ResponseCode:        code,
+		ResponseStatus:      status,
+		ResponseDescription: description,
+		ResponseBody:        body,
+	}
+}
@@ -0,0 +1,71 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"database/sql"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetProductVariants_ProcessMsg_List(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetProductVariants(new(mocks.MockCacheInterface), mockDB)
+
+	variants := []*models.ProductVariant{{ID: 4, ProductID: 7, SKU: "SHIRT-M", Options: models.VariantOptions{"size": "M"}, Available: true}}
+	mockDB.On("ListProductVariants", mock.Anything, 7).Return(variants, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/products/7/variants", nil), map[string]string{"id": "7"})
+	resp, err := service.ProcessMsg(nil, req)
+
+	assert.NoError(t, err)
+	result := resp.(models.Result)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, variants, result.ResponseBody)
+	mockDB.AssertNotCalled(t, "GetProductVariant", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetProductVariants_ProcessMsg_Single(t *testing.T) {
+	tests := []struct {
+		name        string
+		vars        map[string]string
+		variant     *models.ProductVariant
+		err         error
+		code        string
+		description string
+	}{
+		{"found", map[string]string{"id": "7", "variantId": "4"}, &models.ProductVariant{ID: 4, ProductID: 7}, nil, enums.SuccessCode, "Variant fetched successfully"},
+		{"product not found", map[string]string{"id": "7", "variantId": "4"}, nil, sql.ErrNoRows, enums.FailureCode404, "Product not found"},
+		{"variant not found", map[string]string{"id": "7", "variantId": "4"}, nil, db.ErrVariantNotFound, enums.FailureCode404, "Variant not found"},
+		{"db error", map[string]string{"id": "7", "variantId": "4"}, nil, errors.New("db error"), enums.FailureCode500, "Database Error"},
+		{"invalid variant id", map[string]string{"id": "7", "variantId": "abc"}, nil, nil, enums.FailureCode400, "Invalid variant ID"},
+		{"invalid product id", map[string]string{"id": "abc", "variantId": "4"}, nil, nil, enums.FailureCode400, "Invalid product ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetProductVariants(new(mocks.MockCacheInterface), mockDB)
+
+			mockDB.On("GetProductVariant", mock.Anything, 7, 4).Return(tt.variant, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/products/7/variants/4", nil), tt.vars)
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			assert.Equal(t, tt.description, result.ResponseDescription)
+			if tt.variant != nil {
+				assert.Equal(t, tt.variant, result.ResponseBody)
+			}
+		})
+	}
+}
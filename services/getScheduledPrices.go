@@ -0,0 +1,124 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// GetScheduledPrices serves GET /products/{id}/scheduled-prices with every scheduled price of the product,
+// including the ones that have ended, ordered by effective_from, and GET /products/{id}/scheduled-prices/{scheduleId}
+// with one scheduled price
+type GetScheduledPrices struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewGetScheduledPrices(redis db.CacheInterface, pgdb db.DBOperations) *GetScheduledPrices {
+	return &GetScheduledPrices{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *GetScheduledPrices) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered GetScheduledPrices Decode")
+	slog.Debug("Exit GetScheduledPrices Decode")
+	return nil, nil
+}
+
+func (b *GetScheduledPrices) Validate(v interface{}) error {
+	slog.Debug("Entered GetScheduledPrices Validate")
+	slog.Debug("Exit GetScheduledPrices Validate")
+	return nil
+}
+
+func (b *GetScheduledPrices) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered GetScheduledPrices ProcessMsg")
+	vars := mux.Vars(r)
+	productId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return scheduleResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+
+	scheduleIdStr, single := vars["scheduleId"]
+	if !single {
+		schedules, err := b.PGDBConnector.ListScheduledPrices(ctx, productId)
+		if err != nil {
+			return scheduleFailure(err), nil
+		}
+		slog.DebugContext(ctx, "Exiting GetScheduledPrices ProcessMsg")
+		return scheduleResult(enum.SuccessCode, enum.SuccessMessage, "Scheduled prices fetched successfully", schedules), nil
+	}
+
+	scheduleId, err := strconv.Atoi(scheduleIdStr)
+	if err != nil {
+		return scheduleResult(enum.FailureCode400, enum.FailureMessage400, "Invalid scheduled price ID", nil), nil
+	}
+	schedule, err := b.PGDBConnector.GetScheduledPrice(ctx, productId, scheduleId)
+	if err != nil {
+		return scheduleFailure(err), nil
+	}
+	slog.DebugContext(ctx, "Exiting GetScheduledPrices ProcessMsg")
+	return scheduleResult(enum.SuccessCode, enum.SuccessMessage, "Scheduled price fetched successfully", schedule), nil
+}
+
+func (b *GetScheduledPrices) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered GetScheduledPrices Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit GetScheduledPrices Encode")
+	return data, statusCode, nil
+}
+
+func scheduleFailure(err error) models.Result {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return scheduleResult(enum.FailureCode404, enum.FailureMessage404, "Product not found", nil)
+	case errors.Is(err, db.ErrScheduledPriceNotFound):
+		return scheduleResult(enum.FailureCode404, enum.FailureMessage404, "Scheduled price not found", nil)
+	case errors.Is(err, db.ErrScheduleOverlap):
+		return scheduleResult(enum.FailureCode409, enum.FailureMessage409, "The product already has a scheduled price in this window", nil)
+	case errors.Is(err, db.ErrScheduleNotPending):
+		return scheduleResult(enum.FailureCode409, enum.FailureMessage409, "The scheduled price is no longer pending", nil)
+	}
+	code, status, description := dbFailure(err)
+	return scheduleResult(code, status, description, nil)
+}
+
+func scheduleResult(code string, status string, description string, body interface{}) models.Result {
+	return models.Result{
+		ResponseCode:        code,
+		ResponseStatus:      status,
+		ResponseDescription: description,
+		ResponseBody:        body,
+	}
+}
@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"database/sql"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetScheduledPrices_ProcessMsg_List(t *testing.T) {
+	tests := []struct {
+		name        string
+		schedules   []*models.ScheduledPrice
+		err         error
+		code        string
+		description string
+	}{
+		{"found", []*models.ScheduledPrice{{ID: 3, ProductID: 7, Price: 79.99, Status: models.ScheduleStatusPending}}, nil, enums.SuccessCode, "Scheduled prices fetched successfully"},
+		{"none scheduled", []*models.ScheduledPrice{}, nil, enums.SuccessCode, "Scheduled prices fetched successfully"},
+		{"product not found", nil, sql.ErrNoRows, enums.FailureCode404, "Product not found"},
+		{"db error", nil, errors.New("db error"), enums.FailureCode500, "Database Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetScheduledPrices(new(mocks.MockCacheInterface), mockDB)
+
+			mockDB.On("ListScheduledPrices", mock.Anything, 7).Return(tt.schedules, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/products/7/scheduled-prices", nil), map[string]string{"id": "7"})
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			assert.Equal(t, tt.description, result.ResponseDescription)
+			if tt.schedules != nil {
+				assert.Equal(t, tt.schedules, result.ResponseBody)
+			}
+			mockDB.AssertNotCalled(t, "GetScheduledPrice", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetScheduledPrices_ProcessMsg_Single(t *testing.T) {
+	effectiveFrom := time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		vars        map[string]string
+		schedule    *models.ScheduledPrice
+		err         error
+		code        string
+		description string
+	}{
+		{"found", map[string]string{"id": "7", "scheduleId": "3"}, &models.ScheduledPrice{ID: 3, ProductID: 7, Price: 79.99, EffectiveFrom: effectiveFrom}, nil, enums.SuccessCode, "Scheduled price fetched successfully"},
+		{"product not found", map[string]string{"id": "7", "scheduleId": "3"}, nil, sql.ErrNoRows, enums.FailureCode404, "Product not found"},
+		{"schedule not found", map[string]string{"id": "7", "scheduleId": "3"}, nil, db.ErrScheduledPriceNotFound, enums.FailureCode404, "Scheduled price not found"},
+		{"db error", map[string]string{"id": "7", "scheduleId": "3"}, nil, errors.New("db error"), enums.FailureCode500, "Database Error"},
+		{"invalid schedule id", map[string]string{"id": "7", "scheduleId": "abc"}, nil, nil, enums.FailureCode400, "Invalid scheduled price ID"},
+		{"invalid product id", map[string]string{"id": "abc", "scheduleId": "3"}, nil, nil, enums.FailureCode400, "Invalid product ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewGetScheduledPrices(new(mocks.MockCacheInterface), mockDB)
+
+			mockDB.On("GetScheduledPrice", mock.Anything, 7, 3).Return(tt.schedule, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/products/7/scheduled-prices/3", nil), tt.vars)
+			resp, err := service.ProcessMsg(nil, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			assert.Equal(t, tt.description, result.ResponseDescription)
+			if tt.schedule != nil {
+				assert.Equal(t, tt.schedule, result.ResponseBody)
+			}
+			mockDB.AssertNotCalled(t, "ListScheduledPrices", mock.Anything, mock.Anything)
+		})
+	}
+}
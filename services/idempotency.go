@@ -0,0 +1,145 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// IdempotencyKeyHeader lets a client retry a POST without creating the resource twice
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// DefaultIdempotencyTTL is how long the response of a completed request is replayed
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// idempotencyLockTTL bounds how long a key stays reserved by a request that never completed, e.g. after a crash
+const idempotencyLockTTL = time.Minute
+
+const maxIdempotencyKeyLength = 255
+
+// idempotentRequest tracks the Idempotency-Key of a request between reserving it and storing the response.
+// A nil *idempotentRequest means the client sent no key, all its methods are then no-ops.
+type idempotentRequest struct {
+	cache       db.CacheInterface
+	key         string
+	fingerprint string
+	ttl         time.Duration
+}
+
+// beginIdempotent reserves the Idempotency-Key of r. When the request can't go ahead the returned
+// response has to be sent instead: the replayed original response, a 409 while the first request is
+// still running, a 422 when the key was used for a different body, or a 400/503 on errors.
+func beginIdempotent(ctx context.Context, cache db.CacheInterface, r *http.Request, body interface{}, ttl time.Duration) (*idempotentRequest, interface{}) {
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		return nil, nil
+	}
+	if err := validIdempotencyKey(key); err != nil {
+		return nil, idempotencyFailure(enum.FailureCode400, enum.FailureMessage400, err.Error())
+	}
+
+	fingerprint, err := requestFingerprint(r, body)
+	if err != nil {
+		return nil, idempotencyFailure(enum.FailureCode500, enum.FailureMessage500, enum.FailureMessage500)
+	}
+
+	existing, reserved, err := cache.ReserveIdempotencyKey(ctx, key, &models.IdempotencyRecord{Fingerprint: fingerprint}, idempotencyLockTTL)
+	if err != nil {
+		// without the cache a retry could not be recognised, so the request is refused rather than risking a duplicate
+		slog.ErrorContext(ctx, "Failed to reserve idempotency key", "error", err)
+		return nil, idempotencyFailure(enum.FailureCode503, enum.FailureMessage503, "Idempotency-Key could not be checked, retry later")
+	}
+	if !reserved {
+		if existing.Fingerprint != fingerprint {
+			return nil, idempotencyFailure(enum.FailureCode422, enum.FailureMessage422, "Idempotency-Key was already used for a different request")
+		}
+		if !existing.Completed {
+			return nil, idempotencyFailure(enum.FailureCode409, enum.FailureMessage409, "A request with this Idempotency-Key is still being processed")
+		}
+		slog.InfoContext(ctx, "Replaying response for idempotency key", "status", existing.StatusCode)
+		return nil, *existing
+	}
+
+	return &idempotentRequest{cache: cache, key: key, fingerprint: fingerprint, ttl: ttl}, nil
+}
+
+// complete stores the encoded response so retries get it replayed.
+// Server errors are not kept, the key is released instead so the request can be retried.
+func (i *idempotentRequest) complete(ctx context.Context, msg interface{}, encode func(interface{}) ([]byte, int, error)) {
+	if i == nil {
+		return
+	}
+	// the response is already decided, storing it must not depend on the client still waiting
+	ctx = context.WithoutCancel(ctx)
+	data, statusCode, err := encode(msg)
+	if err != nil || statusCode >= http.StatusInternalServerError {
+		i.release(ctx)
+		return
+	}
+
+	record := &models.IdempotencyRecord{
+		Fingerprint: i.fingerprint,
+		Completed:   true,
+		StatusCode:  statusCode,
+		Body:        data,
+	}
+	if withHeader, ok := msg.(interface{ ResponseHeader() http.Header }); ok {
+		record.Header = withHeader.ResponseHeader()
+	}
+	if err := i.cache.SaveIdempotencyKey(ctx, i.key, record, i.ttl); err != nil {
+		slog.ErrorContext(ctx, "Failed to store idempotent response", "error", err)
+	}
+}
+
+// release frees the key without storing a response
+func (i *idempotentRequest) release(ctx context.Context) {
+	if i == nil {
+		return
+	}
+	if err := i.cache.DeleteIdempotencyKey(context.WithoutCancel(ctx), i.key); err != nil {
+		slog.ErrorContext(ctx, "Failed to release idempotency key", "error", err)
+	}
+}
+
+func validIdempotencyKey(key string) error {
+	if len(key) > maxIdempotencyKeyLength {
+		return errors.New("Idempotency-Key must be at most 255 characters")
+	}
+	for _, c := range key {
+		if c < 0x21 || c > 0x7e {
+			return errors.New("Idempotency-Key must only contain printable ASCII characters")
+		}
+	}
+	return nil
+}
+
+// requestFingerprint hashes the method, path and decoded body, so whitespace or key order
+// differences in the JSON don't make a retry look like a different request
+func requestFingerprint(r *http.Request, body interface{}) (string, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	hash.Write([]byte(strings.Join([]string{r.Method, r.URL.Path, ""}, "\n")))
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func idempotencyFailure(code string, status string, description string) models.Result {
+	return models.Result{
+		ResponseCode:        code,
+		ResponseStatus:      status,
+		ResponseDescription: description,
+		ResponseBody:        nil,
+	}
+}
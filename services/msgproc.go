@@ -1,6 +1,11 @@
 package services
 
-import "net/http"
+import (
+	"ProductService/db"
+	enum "ProductService/utils/enums"
+	"net/http"
+	"strconv"
+)
 
 type ProductMsgProc interface {
 	Decode(data []byte) (interface{}, error)
@@ -8,3 +13,36 @@ type ProductMsgProc interface {
 	ProcessMsg(v interface{}, r *http.Request) (interface{}, error)
 	Encode(v interface{}) ([]byte, int, error)
 }
+
+// dbFailure maps a DB error to the response code, status and description sent to the client.
+// Timeouts and requests canceled by the client are reported as 504 instead of a generic 500,
+// writes clashing with a unique constraint (e.g. a duplicate SKU) as 409.
+func dbFailure(err error) (string, string, string) {
+	if db.IsTimeout(err) || db.IsCanceled(err) {
+		return enum.FailureCode504, enum.FailureMessage504, "Database Timeout"
+	}
+	if constraint, ok := db.UniqueViolation(err); ok {
+		switch constraint {
+		case db.ProductSKUConstraint:
+			return enum.FailureCode409, enum.FailureMessage409, "A product with this SKU already exists"
+		case db.CategoryNameConstraint:
+			return enum.FailureCode409, enum.FailureMessage409, "A category with this name already exists under the same parent"
+		case db.VariantSKUConstraint:
+			return enum.FailureCode409, enum.FailureMessage409, "A variant with this SKU already exists"
+		case db.VariantOptionsConstraint:
+			return enum.FailureCode409, enum.FailureMessage409, "The product already has a variant with these options"
+		}
+		return enum.FailureCode409, enum.FailureMessage409, "Conflicts with an existing record"
+	}
+	return enum.FailureCode500, enum.FailureMessage500, "Database Error"
+}
+
+// httpStatus converts a ResponseCode such as "201" or "412" into the HTTP status sent to the client.
+// Anything that isn't a known HTTP status falls back to 200.
+func httpStatus(code string) int {
+	status, err := strconv.Atoi(code)
+	if err != nil || http.StatusText(status) == "" {
+		return http.StatusOK
+	}
+	return status
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// Patch document media types accepted by PATCH /products/{id}
+const (
+	MergePatchContentType = "application/merge-patch+json" // RFC 7386
+	JSONPatchContentType  = "application/json-patch+json"  // RFC 6902
+)
+
+// PatchProduct applies a partial update to a product. The stored product is loaded,
+// the patch is applied to its JSON form, the result is validated like a PUT body
+// and only the columns that actually changed are written back.
+type PatchProduct struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+	CacheSync      *db.CacheSync
+	// RequireIfMatch rejects patches without an If-Match header with 428
+	RequireIfMatch bool
+}
+
+func NewPatchProduct(redis db.CacheInterface, pgdb db.DBOperations) *PatchProduct {
+	return &PatchProduct{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+		CacheSync:      db.NewCacheSync(redis),
+	}
+}
+
+func (b *PatchProduct) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered PatchProduct Decode")
+	if !json.Valid(data) {
+		err := errors.New("patch document is not valid JSON")
+		slog.Warn("Error in decoding request", "error", err)
+		return nil, err
+	}
+	slog.Debug("Exit PatchProduct Decode")
+	return &models.PatchProductRequest{Patch: data}, nil
+}
+
+func (b *PatchProduct) Validate(v interface{}) error {
+	slog.Debug("Entered PatchProduct Validate")
+	format := v.(*models.PatchProductRequest)
+	if len(bytes.TrimSpace(format.Patch)) == 0 {
+		return errors.New("patch document is empty")
+	}
+	slog.Debug("Exit PatchProduct Validate")
+	return nil
+}
+
+func (b *PatchProduct) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered PatchProduct ProcessMsg")
+	vars := mux.Vars(r)
+	productId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return patchResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != MergePatchContentType && mediaType != JSONPatchContentType) {
+		description := fmt.Sprintf("Content-Type must be %v or %v", MergePatchContentType, JSONPatchContentType)
+		return patchResult(enum.FailureCode415, enum.FailureMessage415, description, nil), nil
+	}
+
+	versions, failure := writePrecondition(r, b.RequireIfMatch)
+	if failure != nil {
+		return *failure, nil
+	}
+
+	// read from postgres rather than the cache, the patch has to apply to the stored row
+	current, err := b.PGDBConnector.GetProductByID(ctx, productId)
+	if err != nil {
+		code, status, description := dbFailure(err)
+		return patchResult(code, status, description, nil), nil
+	}
+	if current == nil {
+		return patchResult(enum.FailureCode404, enum.FailureMessage404, "Product not found", nil), nil
+	}
+
+	if len(versions) > 0 && !slices.Contains(versions, current.Version) {
+		return *preconditionFailed(), nil
+	}
+
+	format := v.(*models.PatchProductRequest)
+	patched, err := applyPatch(mediaType, current, format.Patch)
+	if err != nil {
+		slog.WarnContext(ctx, "Error in applying patch", "error", err)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return patchResult(enum.FailureCode409, enum.FailureMessage409, "Patch test operation failed", nil), nil
+		}
+		return patchResult(enum.FailureCode400, enum.FailureMessage400, "Invalid patch: "+err.Error(), nil), nil
+	}
+
+	if patched.ID != productId {
+		return patchResult(enum.FailureCode400, enum.FailureMessage400, "Product ID cannot be changed", nil), nil
+	}
+	if err := utils.NewValidator().Struct(patched); err != nil {
+		slog.WarnContext(ctx, "Patched product is invalid", "error", err)
+		return patchResult(enum.FailureCode400, enum.FailureMessage400, err.Error(), nil), nil
+	}
+
+	updatedProduct := *current
+	updatedProduct.SKU = patched.SKU
+	updatedProduct.Name = patched.Name
+	updatedProduct.Description = patched.Description
+	updatedProduct.Price = patched.Price
+	updatedProduct.Currency = patched.Currency
+	updatedProduct.Status = patched.Status
+	updatedProduct.Attributes = patched.Attributes
+	updatedProduct.Tags = patched.Tags
+	changes := changedColumns(current, &updatedProduct)
+	if len(changes) == 0 {
+		slog.DebugContext(ctx, "Patch did not change the product")
+		msg := patchResult(enum.SuccessCode, enum.SuccessMessage, "Product updated successfully", current)
+		msg.Header = etagHeader(ETag(current.Version))
+		return msg, nil
+	}
+
+	// the patch was computed from the version just read, only write if nobody changed it since.
+	// Write to the DB and refresh the cached copy so reads never see the old values.
+	err = b.CacheSync.WriteThrough(ctx, &updatedProduct, func() error {
+		stored, err := b.PGDBConnector.PatchProduct(ctx, productId, current.Version, changes)
+		if err != nil {
+			return err
+		}
+		updatedProduct = *stored
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrVersionMismatch) {
+			if len(versions) > 0 {
+				return *preconditionFailed(), nil
+			}
+			return patchResult(enum.FailureCode409, enum.FailureMessage409, "Product was modified concurrently, retry the patch", nil), nil
+		}
+		if err == sql.ErrNoRows {
+			return patchResult(enum.FailureCode404, enum.FailureMessage404, "Product not found", nil), nil
+		}
+		code, status, description := dbFailure(err)
+		return patchResult(code, status, description, nil), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting PatchProduct ProcessMsg")
+	msg := patchResult(enum.SuccessCode, enum.SuccessMessage, "Product updated successfully", &updatedProduct)
+	msg.Header = etagHeader(ETag(updatedProduct.Version))
+	return msg, nil
+}
+
+func (b *PatchProduct) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered PatchProduct Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit PatchProduct Encode")
+	return data, statusCode, nil
+}
+
+func patchResult(code string, status string, description string, body interface{}) models.Result {
+	return models.Result{
+		ResponseCode:        code,
+		ResponseStatus:      status,
+		ResponseDescription: description,
+		ResponseBody:        body,
+	}
+}
+
+// applyPatch applies the patch document to the JSON form of the product and decodes the result.
+// Fields the product doesn't have are rejected instead of being silently dropped.
+func applyPatch(mediaType string, product *models.Product, patch []byte) (*models.UpdateProductRequest, error) {
+	// the version is managed by the service, it is not part of the patchable document
+	original, err := json.Marshal(models.UpdateProductRequest{
+		ID:          product.ID,
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		Currency:    product.Currency,
+		Status:      product.Status,
+		// empty rather than null so JSON Patch can add to them
+		Attributes: nonNilAttributes(product.Attributes),
+		Tags:       nonNilTags(product.Tags),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var modified []byte
+	if mediaType == JSONPatchContentType {
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, err
+		}
+		modified, err = operations.Apply(original)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		modified, err = jsonpatch.MergePatch(original, patch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(modified))
+	decoder.DisallowUnknownFields()
+	var patched models.UpdateProductRequest
+	if err := decoder.Decode(&patched); err != nil {
+		return nil, err
+	}
+	return &patched, nil
+}
+
+// changedColumns lists the columns whose value differs between the stored and the patched product
+func changedColumns(current *models.Product, updated *models.Product) map[string]interface{} {
+	changes := map[string]interface{}{}
+	if current.SKU != updated.SKU {
+		changes["sku"] = updated.SKU
+	}
+	if current.Name != updated.Name {
+		changes["name"] = updated.Name
+	}
+	if current.Description != updated.Description {
+		changes["description"] = updated.Description
+	}
+	if current.Price != updated.Price {
+		changes["price"] = updated.Price
+	}
+	if current.Currency != updated.Currency {
+		changes["currency"] = updated.Currency
+	}
+	if current.Status != updated.Status {
+		changes["status"] = updated.Status
+	}
+	if !reflect.DeepEqual(nonNilAttributes(current.Attributes), nonNilAttributes(updated.Attributes)) {
+		changes["attributes"] = updated.Attributes
+	}
+	if !slices.Equal(current.Tags, updated.Tags) {
+		changes["tags"] = updated.Tags
+	}
+	return changes
+}
+
+func nonNilAttributes(attributes models.Attributes) models.Attributes {
+	if attributes == nil {
+		return models.Attributes{}
+	}
+	return attributes
+}
+
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
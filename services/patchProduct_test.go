@@ -0,0 +1,328 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"context"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newPatchRequest(id string, contentType string) *http.Request {
+	req := httptest.NewRequest("PATCH", "/products/"+id, nil)
+	req.Header.Set("Content-Type", contentType)
+	return mux.SetURLVars(req, map[string]string{"id": id})
+}
+
+func decodePatch(t *testing.T, service *services.PatchProduct, body string) interface{} {
+	format, err := service.Decode([]byte(body))
+	assert.NoError(t, err)
+	assert.NoError(t, service.Validate(format))
+	return format
+}
+
+// storedProduct is the row the patch tests start from
+func storedProduct() *models.Product {
+	return &models.Product{
+		ID:        1,
+		SKU:       "PH-1",
+		Name:      "Phone",
+		Price:     100,
+		Currency:  "USD",
+		Status:    models.StatusActive,
+		Version:   3,
+		CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPatchProduct_Decode_InvalidJSON(t *testing.T) {
+	service := services.NewPatchProduct(new(mocks.MockCacheInterface), new(mocks.MockDBOperations))
+
+	_, err := service.Decode([]byte(`{"price":`))
+
+	assert.Error(t, err)
+}
+
+func TestPatchProduct_ProcessMsg_MergePatchUpdatesOnlyChangedColumns(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPatchProduct(cache, mockDB)
+
+	patched := storedProduct()
+	patched.Price, patched.Status, patched.Version = 80.5, models.StatusArchived, 4
+	patched.UpdatedAt = time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(storedProduct(), nil)
+	mockDB.On("PatchProduct", mock.Anything, 1, 3, map[string]interface{}{"price": 80.5, "status": "archived"}).Return(patched, nil)
+
+	format := decodePatch(t, service, `{"price": 80.5, "status": "archived"}`)
+	resp, err := service.ProcessMsg(format, newPatchRequest("1", "application/merge-patch+json"))
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, patched, result.ResponseBody)
+
+	cached, _ := cache.GetProductByID(context.Background(), "1")
+	assert.Equal(t, patched, cached)
+	mockDB.AssertExpectations(t)
+}
+
+func TestPatchProduct_ProcessMsg_JSONPatch(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPatchProduct(cache, mockDB)
+
+	patched := storedProduct()
+	patched.Name, patched.Version = "Smartphone", 4
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(storedProduct(), nil)
+	mockDB.On("PatchProduct", mock.Anything, 1, 3, map[string]interface{}{"name": "Smartphone"}).Return(patched, nil)
+
+	format := decodePatch(t, service, `[{"op":"test","path":"/price","value":100},{"op":"replace","path":"/name","value":"Smartphone"}]`)
+	resp, err := service.ProcessMsg(format, newPatchRequest("1", "application/json-patch+json; charset=utf-8"))
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, patched, result.ResponseBody)
+	mockDB.AssertExpectations(t)
+}
+
+func TestPatchProduct_ProcessMsg_AttributesAndTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		patch       string
+		changes     map[string]interface{}
+	}{
+		{
+			"merge patch removes and replaces attributes",
+			"application/merge-patch+json",
+			`{"attributes": {"color": "red", "size": null}, "tags": ["sale", "new"]}`,
+			map[string]interface{}{"attributes": models.Attributes{"color": "red"}, "tags": []string{"sale", "new"}},
+		},
+		{
+			"json patch adds one attribute",
+			"application/json-patch+json",
+			`[{"op": "add", "path": "/attributes/material", "value": "leather"}]`,
+			map[string]interface{}{"attributes": models.Attributes{"color": "black", "size": "M", "material": "leather"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewPatchProduct(mocks.NewMemoryCache(), mockDB)
+
+			current := storedProduct()
+			current.Attributes = models.Attributes{"color": "black", "size": "M"}
+			current.Tags = []string{"sale"}
+			mockDB.On("GetProductByID", mock.Anything, 1).Return(current, nil)
+			mockDB.On("PatchProduct", mock.Anything, 1, 3, tt.changes).Return(storedProduct(), nil)
+
+			format := decodePatch(t, service, tt.patch)
+			resp, err := service.ProcessMsg(format, newPatchRequest("1", tt.contentType))
+
+			assert.NoError(t, err)
+			assert.Equal(t, enums.SuccessCode, resp.(models.Result).ResponseCode)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatchProduct_ProcessMsg_JSONPatchTestFailed(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPatchProduct(new(mocks.MockCacheInterface), mockDB)
+
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(storedProduct(), nil)
+
+	format := decodePatch(t, service, `[{"op":"test","path":"/price","value":90},{"op":"replace","path":"/price","value":80}]`)
+	resp, err := service.ProcessMsg(format, newPatchRequest("1", "application/json-patch+json"))
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode409, result.ResponseCode)
+	mockDB.AssertNotCalled(t, "PatchProduct", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchProduct_ProcessMsg_UnsupportedContentType(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPatchProduct(new(mocks.MockCacheInterface), mockDB)
+
+	format := decodePatch(t, service, `{"price": 80}`)
+	resp, err := service.ProcessMsg(format, newPatchRequest("1", "application/json"))
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode415, result.ResponseCode)
+	mockDB.AssertExpectations(t)
+}
+
+func TestPatchProduct_ProcessMsg_ResultFailsValidation(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPatchProduct(new(mocks.MockCacheInterface), mockDB)
+
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(storedProduct(), nil)
+
+	tests := map[string]string{
+		"removed field":    `{"price": null}`,
+		"invalid value":    `{"price": -5}`,
+		"wrong type":       `{"price": "cheap"}`,
+		"unknown field":    `{"colour": "red"}`,
+		"id changed":       `{"id": 2}`,
+		"not an object":    `"Phone"`,
+		"invalid name":     `{"name": ""}`,
+		"wrong name type":  `{"name": {"en": "Phone"}}`,
+		"invalid status":   `{"status": "deleted"}`,
+		"invalid currency": `{"currency": "EURO"}`,
+		"timestamp":        `{"updated_at": "2024-01-01T00:00:00Z"}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			format := decodePatch(t, service, body)
+			resp, err := service.ProcessMsg(format, newPatchRequest("1", "application/merge-patch+json"))
+
+			result := resp.(models.Result)
+			assert.NoError(t, err)
+			assert.Equal(t, enums.FailureCode400, result.ResponseCode)
+		})
+	}
+	mockDB.AssertNotCalled(t, "PatchProduct", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchProduct_ProcessMsg_NoChangesSkipsWrite(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPatchProduct(new(mocks.MockCacheInterface), mockDB)
+
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(storedProduct(), nil)
+
+	format := decodePatch(t, service, `{"name": "Phone"}`)
+	resp, err := service.ProcessMsg(format, newPatchRequest("1", "application/merge-patch+json"))
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	mockDB.AssertNotCalled(t, "PatchProduct", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchProduct_ProcessMsg_ProductNotFound(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPatchProduct(new(mocks.MockCacheInterface), mockDB)
+
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(nil, nil)
+
+	format := decodePatch(t, service, `{"price": 80}`)
+	resp, err := service.ProcessMsg(format, newPatchRequest("1", "application/merge-patch+json"))
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode404, result.ResponseCode)
+	mockDB.AssertExpectations(t)
+}
+
+func TestPatchProduct_ProcessMsg_DBError(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPatchProduct(mockCache, mockDB)
+
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(storedProduct(), nil)
+	mockDB.On("PatchProduct", mock.Anything, 1, 3, mock.Anything).Return(nil, errors.New("db error"))
+
+	format := decodePatch(t, service, `{"price": 80}`)
+	resp, err := service.ProcessMsg(format, newPatchRequest("1", "application/merge-patch+json"))
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode500, result.ResponseCode)
+	mockCache.AssertNotCalled(t, "SetProductByID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertExpectations(t)
+}
+
+func TestPatchProduct_ProcessMsg_StaleIfMatch(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPatchProduct(new(mocks.MockCacheInterface), mockDB)
+
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(storedProduct(), nil)
+
+	req := newPatchRequest("1", "application/merge-patch+json")
+	req.Header.Set("If-Match", `"2"`)
+	resp, err := service.ProcessMsg(decodePatch(t, service, `{"price": 80}`), req)
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode412, result.ResponseCode)
+	mockDB.AssertNotCalled(t, "PatchProduct", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchProduct_ProcessMsg_ConcurrentWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		code    string
+	}{
+		{"without If-Match", "", enums.FailureCode409},
+		{"with If-Match", `"3"`, enums.FailureCode412},
+		{"with the current version listed in If-Match", `"2", "3"`, enums.FailureCode412},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewPatchProduct(mockCache, mockDB)
+
+			mockDB.On("GetProductByID", mock.Anything, 1).Return(storedProduct(), nil)
+			mockDB.On("PatchProduct", mock.Anything, 1, 3, mock.Anything).Return(nil, db.ErrVersionMismatch)
+
+			req := newPatchRequest("1", "application/merge-patch+json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			resp, err := service.ProcessMsg(decodePatch(t, service, `{"price": 80}`), req)
+
+			result := resp.(models.Result)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			mockCache.AssertNotCalled(t, "SetProductByID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPatchProduct_ProcessMsg_VersionIsNotPatchable(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPatchProduct(new(mocks.MockCacheInterface), mockDB)
+
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(storedProduct(), nil)
+
+	resp, err := service.ProcessMsg(decodePatch(t, service, `{"version": 10}`), newPatchRequest("1", "application/merge-patch+json"))
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode400, result.ResponseCode)
+}
+
+func TestPatchProduct_Encode_StatusCodes(t *testing.T) {
+	service := services.NewPatchProduct(new(mocks.MockCacheInterface), new(mocks.MockDBOperations))
+
+	tests := map[string]int{
+		enums.SuccessCode:    http.StatusOK,
+		enums.FailureCode409: http.StatusConflict,
+		enums.FailureCode412: http.StatusPreconditionFailed,
+		enums.FailureCode415: http.StatusUnsupportedMediaType,
+		enums.FailureCode428: http.StatusPreconditionRequired,
+	}
+	for code, status := range tests {
+		_, statusCode, err := service.Encode(models.Result{ResponseCode: code})
+		assert.NoError(t, err)
+		assert.Equal(t, status, statusCode)
+	}
+}
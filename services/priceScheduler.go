@@ -0,0 +1,85 @@
+package services
+
+import (
+	"ProductService/db"
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// Price scheduler defaults, the interval is overridden from the config in the app
+const (
+	DefaultPriceScheduleInterval  = time.Minute
+	DefaultPriceScheduleBatchSize = 100
+)
+
+// PriceScheduler applies and reverts scheduled prices. It runs every Interval and handles them in chunks
+// of BatchSize, evicting the products whose price changed from the cache. Running it on several instances
+// at once is safe, an advisory lock lets only one of them work at a time.
+type PriceScheduler struct {
+	PGDBConnector db.DBOperations
+	CacheSync     *db.CacheSync
+	Interval      time.Duration
+	BatchSize     int
+}
+
+func NewPriceScheduler(redis db.CacheInterface, pgdb db.DBOperations) *PriceScheduler {
+	return &PriceScheduler{
+		PGDBConnector: pgdb,
+		CacheSync:     db.NewCacheSync(redis),
+		Interval:      DefaultPriceScheduleInterval,
+		BatchSize:     DefaultPriceScheduleBatchSize,
+	}
+}
+
+// Run applies due changes once right away and then on every tick until ctx is canceled
+func (p *PriceScheduler) Run(ctx context.Context) {
+	slog.Info("Started price scheduler", "interval", p.Interval)
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := p.Apply(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Failed to apply scheduled prices", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopped price scheduler")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Apply applies and reverts every scheduled price due by now and returns how many were handled
+func (p *PriceScheduler) Apply(ctx context.Context, now time.Time) (int, error) {
+	var total int
+	for ctx.Err() == nil {
+		productIDs, err := p.PGDBConnector.RunPriceSchedule(ctx, now, p.BatchSize)
+		total += len(productIDs)
+		// the batch is committed once it returns, so its products are evicted even if a later one fails
+		p.evict(ctx, productIDs)
+		if err != nil {
+			return total, err
+		}
+		if len(productIDs) < p.BatchSize {
+			break
+		}
+	}
+	if total > 0 {
+		slog.InfoContext(ctx, "Applied scheduled prices", "count", total)
+	}
+	return total, ctx.Err()
+}
+
+func (p *PriceScheduler) evict(ctx context.Context, productIDs []int) {
+	seen := make(map[int]bool, len(productIDs))
+	ids := make([]string, 0, len(productIDs))
+	for _, id := range productIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, strconv.Itoa(id))
+		}
+	}
+	p.CacheSync.EvictAll(context.WithoutCancel(ctx), ids)
+}
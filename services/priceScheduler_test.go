@@ -0,0 +1,63 @@
+package services_test
+
+import (
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestPriceScheduler_Apply_EvictsChangedProducts(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	for _, id := range []string{"1", "2", "3"} {
+		_ = cache.SetProductByID(context.Background(), id, &models.Product{Name: "Phone"}, time.Minute)
+	}
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPriceScheduler(cache, mockDB)
+	service.BatchSize = 2
+
+	now := time.Date(2026, 11, 20, 0, 0, 0, 0, time.UTC)
+	mockDB.On("RunPriceSchedule", mock.Anything, now, 2).Return([]int{1, 1}, nil).Once()
+	mockDB.On("RunPriceSchedule", mock.Anything, now, 2).Return([]int{2}, nil).Once()
+
+	applied, err := service.Apply(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, applied)
+	mockDB.AssertExpectations(t)
+	for id, cached := range map[string]bool{"1": false, "2": false, "3": true} {
+		product, _ := cache.GetProductByID(context.Background(), id)
+		assert.Equal(t, cached, product != nil, id)
+	}
+}
+
+func TestPriceScheduler_Apply_NothingDue(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPriceScheduler(new(mocks.MockCacheInterface), mockDB)
+
+	mockDB.On("RunPriceSchedule", mock.Anything, mock.Anything, services.DefaultPriceScheduleBatchSize).Return(nil, nil).Once()
+
+	applied, err := service.Apply(context.Background(), time.Now())
+
+	assert.NoError(t, err)
+	assert.Zero(t, applied)
+	mockDB.AssertExpectations(t)
+}
+
+func TestPriceScheduler_Apply_DBError(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPriceScheduler(new(mocks.MockCacheInterface), mockDB)
+
+	mockDB.On("RunPriceSchedule", mock.Anything, mock.Anything, services.DefaultPriceScheduleBatchSize).Return(nil, errors.New("db error")).Once()
+
+	applied, err := service.Apply(context.Background(), time.Now())
+
+	assert.Error(t, err)
+	assert.Zero(t, applied)
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"ProductService/db"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Purge job defaults, overridden from the config in the app
+const (
+	DefaultPurgeRetention = 30 * 24 * time.Hour
+	DefaultPurgeInterval  = time.Hour
+	DefaultPurgeBatchSize = 500
+)
+
+// PurgeDeleted permanently removes products that were soft deleted longer than Retention ago.
+// It runs every Interval and deletes in chunks of BatchSize so no single statement holds locks for long.
+// Running it on several instances at once is safe, each row is only removed once.
+type PurgeDeleted struct {
+	PGDBConnector db.DBOperations
+	Retention     time.Duration
+	Interval      time.Duration
+	BatchSize     int
+}
+
+func NewPurgeDeleted(pgdb db.DBOperations) *PurgeDeleted {
+	return &PurgeDeleted{
+		PGDBConnector: pgdb,
+		Retention:     DefaultPurgeRetention,
+		Interval:      DefaultPurgeInterval,
+		BatchSize:     DefaultPurgeBatchSize,
+	}
+}
+
+// Run purges once right away and then on every tick until ctx is canceled
+func (p *PurgeDeleted) Run(ctx context.Context) {
+	slog.Info("Started purge job", "retention", p.Retention, "interval", p.Interval)
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := p.Purge(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Failed to purge deleted products", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopped purge job")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge removes every product deleted before now minus the retention and returns how many were removed
+func (p *PurgeDeleted) Purge(ctx context.Context, now time.Time) (int64, error) {
+	before := now.Add(-p.Retention)
+	var total int64
+	for ctx.Err() == nil {
+		purged, err := p.PGDBConnector.PurgeDeletedProducts(ctx, before, p.BatchSize)
+		total += purged
+		if err != nil {
+			return total, err
+		}
+		if purged < int64(p.BatchSize) {
+			break
+		}
+	}
+	if total > 0 {
+		slog.InfoContext(ctx, "Purged deleted products", "count", total, "deleted_before", before)
+	}
+	return total, ctx.Err()
+}
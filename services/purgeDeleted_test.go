@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"ProductService/db/mocks"
+	"ProductService/services"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestPurgeDeleted_Purge_RepeatsUntilBacklogIsEmpty(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPurgeDeleted(mockDB)
+	service.Retention = 7 * 24 * time.Hour
+	service.BatchSize = 2
+
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC)
+	mockDB.On("PurgeDeletedProducts", mock.Anything, before, 2).Return(int64(2), nil).Twice()
+	mockDB.On("PurgeDeletedProducts", mock.Anything, before, 2).Return(int64(1), nil).Once()
+
+	purged, err := service.Purge(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), purged)
+	mockDB.AssertExpectations(t)
+}
+
+func TestPurgeDeleted_Purge_DBError(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPurgeDeleted(mockDB)
+
+	mockDB.On("PurgeDeletedProducts", mock.Anything, mock.Anything, services.DefaultPurgeBatchSize).Return(int64(0), errors.New("db error")).Once()
+
+	purged, err := service.Purge(context.Background(), time.Now())
+
+	assert.Error(t, err)
+	assert.Zero(t, purged)
+	mockDB.AssertExpectations(t)
+}
+
+func TestPurgeDeleted_Run_StopsWhenCanceled(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewPurgeDeleted(mockDB)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockDB.On("PurgeDeletedProducts", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), nil).Run(func(mock.Arguments) {
+		cancel()
+	}).Once()
+
+	done := make(chan struct{})
+	go func() {
+		service.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purge job did not stop after cancel")
+	}
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// ReleaseReservation serves DELETE /products/{id}/reservations/{reservationId}, it gives the reserved stock back
+type ReleaseReservation struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewReleaseReservation(redis db.CacheInterface, pgdb db.DBOperations) *ReleaseReservation {
+	return &ReleaseReservation{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *ReleaseReservation) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered ReleaseReservation Decode")
+	slog.Debug("Exit ReleaseReservation Decode")
+	return nil, nil
+}
+
+func (b *ReleaseReservation) Validate(v interface{}) error {
+	slog.Debug("Entered ReleaseReservation Validate")
+	slog.Debug("Exit ReleaseReservation Validate")
+	return nil
+}
+
+func (b *ReleaseReservation) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered ReleaseReservation ProcessMsg")
+	vars := mux.Vars(r)
+	productId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return inventoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+	reservationId, err := strconv.Atoi(vars["reservationId"])
+	if err != nil {
+		return inventoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid reservation ID", nil), nil
+	}
+
+	if err := b.PGDBConnector.ReleaseReservation(ctx, productId, reservationId); err != nil {
+		return inventoryFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting ReleaseReservation ProcessMsg")
+	return inventoryResult(enum.SuccessCode, enum.SuccessMessage, "Reservation released successfully", nil), nil
+}
+
+func (b *ReleaseReservation) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered ReleaseReservation Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit ReleaseReservation Encode")
+	return data, statusCode, nil
+}
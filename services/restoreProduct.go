@@ -0,0 +1,120 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	enum "ProductService/utils/enums"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// RestoreProduct undoes a soft delete, the product becomes visible again with a new version.
+// Products that were already purged can't be restored and get 404.
+type RestoreProduct struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+	CacheSync      *db.CacheSync
+	// RequireIfMatch rejects restores without an If-Match header with 428
+	RequireIfMatch bool
+}
+
+func NewRestoreProduct(redis db.CacheInterface, pgdb db.DBOperations) *RestoreProduct {
+	return &RestoreProduct{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+		CacheSync:      db.NewCacheSync(redis),
+	}
+}
+
+func (b *RestoreProduct) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered RestoreProduct Decode")
+	slog.Debug("Exit RestoreProduct Decode")
+	return nil, nil
+}
+
+func (b *RestoreProduct) Validate(v interface{}) error {
+	slog.Debug("Entered RestoreProduct Validate")
+	slog.Debug("Exit RestoreProduct Validate")
+	return nil
+}
+
+func (b *RestoreProduct) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered RestoreProduct ProcessMsg")
+	vars := mux.Vars(r)
+	productId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return restoreResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+
+	versions, failure := writePrecondition(r, b.RequireIfMatch)
+	if failure != nil {
+		return *failure, nil
+	}
+
+	var restored *models.Product
+	err = b.CacheSync.WriteAndEvict(ctx, func() error {
+		var err error
+		restored, err = b.PGDBConnector.RestoreProduct(ctx, productId, versions)
+		return err
+	}, vars["id"])
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrVersionMismatch):
+			return *preconditionFailed(), nil
+		case errors.Is(err, db.ErrNotDeleted):
+			return restoreResult(enum.FailureCode409, enum.FailureMessage409, "Product is not deleted", nil), nil
+		case errors.Is(err, sql.ErrNoRows):
+			return restoreResult(enum.FailureCode404, enum.FailureMessage404, "Product not found", nil), nil
+		}
+		code, status, description := dbFailure(err)
+		return restoreResult(code, status, description, nil), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting RestoreProduct ProcessMsg")
+	msg := restoreResult(enum.SuccessCode, enum.SuccessMessage, "Product restored successfully", restored)
+	msg.Header = etagHeader(ETag(restored.Version))
+	return msg, nil
+}
+
+func (b *RestoreProduct) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered RestoreProduct Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit RestoreProduct Encode")
+	return data, statusCode, nil
+}
+
+func restoreResult(code string, status string, description string, body interface{}) models.Result {
+	return models.Result{
+		ResponseCode:        code,
+		ResponseStatus:      status,
+		ResponseDescription: description,
+		ResponseBody:        body,
+	}
+}
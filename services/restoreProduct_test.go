@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRestoreProduct_ProcessMsg_Success(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewRestoreProduct(cache, mockDB)
+
+	_ = cache.SetProductByID(context.Background(), "1", &models.Product{ID: 1, Name: "Stale", Price: 10}, time.Minute)
+	restored := &models.Product{ID: 1, Name: "Laptop", Price: 999.99, Version: 4}
+	mockDB.On("RestoreProduct", mock.Anything, 1, []int{3}).Return(restored, nil)
+
+	req := httptest.NewRequest("POST", "/products/1:restore", nil)
+	req.Header.Set("If-Match", `"3"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, restored, result.ResponseBody)
+	assert.Equal(t, `"4"`, result.ResponseHeader().Get("ETag"))
+	cached, _ := cache.GetProductByID(context.Background(), "1")
+	assert.Nil(t, cached)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestRestoreProduct_ProcessMsg_Failures(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"not found or purged", sql.ErrNoRows, enums.FailureCode404},
+		{"not deleted", db.ErrNotDeleted, enums.FailureCode409},
+		{"version mismatch", db.ErrVersionMismatch, enums.FailureCode412},
+		{"db error", errors.New("db error"), enums.FailureCode500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewRestoreProduct(mockCache, mockDB)
+
+			mockDB.On("RestoreProduct", mock.Anything, 1, []int(nil)).Return(nil, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("POST", "/products/1:restore", nil), map[string]string{"id": "1"})
+
+			resp, err := service.ProcessMsg(nil, req)
+
+			result := resp.(models.Result)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, result.ResponseCode)
+
+			mockCache.AssertExpectations(t)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreProduct_ProcessMsg_RequireIfMatch(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewRestoreProduct(new(mocks.MockCacheInterface), mockDB)
+	service.RequireIfMatch = true
+
+	req := mux.SetURLVars(httptest.NewRequest("POST", "/products/1:restore", nil), map[string]string{"id": "1"})
+
+	resp, err := service.ProcessMsg(nil, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode428, resp.(models.Result).ResponseCode)
+	mockDB.AssertNotCalled(t, "RestoreProduct", mock.Anything, mock.Anything, mock.Anything)
+}
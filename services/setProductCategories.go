@@ -0,0 +1,112 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// SetProductCategories replaces the categories of a product with the ones in the request,
+// an empty list removes the product from every category
+type SetProductCategories struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewSetProductCategories(redis db.CacheInterface, pgdb db.DBOperations) *SetProductCategories {
+	return &SetProductCategories{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *SetProductCategories) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered SetProductCategories Decode")
+	var format *models.ProductCategoriesRequest
+	err := json.Unmarshal(data, &format)
+	if err != nil {
+		slog.Warn("Error in decoding request", "error", err)
+		return nil, err
+	}
+	slog.Debug("Exit SetProductCategories Decode")
+	return format, nil
+}
+
+func (b *SetProductCategories) Validate(v interface{}) error {
+	slog.Debug("Entered SetProductCategories Validate")
+	var validate = utils.NewValidator()
+	if e := validate.Struct(v); e != nil {
+		slog.Warn("Error in validating request", "error", e)
+		return e
+	}
+	slog.Debug("Exit SetProductCategories Validate")
+	return nil
+}
+
+func (b *SetProductCategories) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered SetProductCategories ProcessMsg")
+	productId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return categoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+	request := v.(*models.ProductCategoriesRequest)
+
+	if err := b.PGDBConnector.SetProductCategories(ctx, productId, request.CategoryIDs); err != nil {
+		return productCategoriesFailure(err), nil
+	}
+	categories, err := b.PGDBConnector.GetProductCategories(ctx, productId)
+	if err != nil {
+		return productCategoriesFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting SetProductCategories ProcessMsg")
+	return categoryResult(enum.SuccessCode, enum.SuccessMessage, "Product categories updated successfully", categories), nil
+}
+
+func productCategoriesFailure(err error) models.Result {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return categoryResult(enum.FailureCode404, enum.FailureMessage404, "Product not found", nil)
+	case errors.Is(err, db.ErrCategoryNotFound):
+		return categoryResult(enum.FailureCode422, enum.FailureMessage422, "Category not found", nil)
+	}
+	code, status, description := dbFailure(err)
+	return categoryResult(code, status, description, nil)
+}
+
+func (b *SetProductCategories) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered SetProductCategories Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit SetProductCategories Encode")
+	return data, statusCode, nil
+}
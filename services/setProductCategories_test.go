@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"database/sql"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetProductCategories_Validate(t *testing.T) {
+	service := services.NewSetProductCategories(new(mocks.MockCacheInterface), new(mocks.MockDBOperations))
+
+	for body, valid := range map[string]bool{
+		`{"category_ids":[1,2]}`: true,
+		`{"category_ids":[]}`:    true,
+		`{}`:                     false,
+		`{"category_ids":[1,1]}`: false,
+		`{"category_ids":[0]}`:   false,
+	} {
+		format, err := service.Decode([]byte(body))
+		assert.NoError(t, err)
+		assert.Equal(t, valid, service.Validate(format) == nil, body)
+	}
+}
+
+func TestSetProductCategories_ProcessMsg_Success(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewSetProductCategories(new(mocks.MockCacheInterface), mockDB)
+
+	categories := []*models.Category{{ID: 2, ParentID: parentID(1), Name: "Phones"}, {ID: 5, Name: "Books"}}
+	mockDB.On("SetProductCategories", mock.Anything, 7, []int{2, 5}).Return(nil)
+	mockDB.On("GetProductCategories", mock.Anything, 7).Return(categories, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest("PUT", "/products/7/categories", nil), map[string]string{"id": "7"})
+	resp, err := service.ProcessMsg(&models.ProductCategoriesRequest{CategoryIDs: []int{2, 5}}, req)
+
+	assert.NoError(t, err)
+	result := resp.(models.Result)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, categories, result.ResponseBody)
+	mockDB.AssertExpectations(t)
+}
+
+func TestSetProductCategories_ProcessMsg_Failures(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"product not found", sql.ErrNoRows, enums.FailureCode404},
+		{"unknown category", db.ErrCategoryNotFound, enums.FailureCode422},
+		{"db error", errors.New("db error"), enums.FailureCode500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewSetProductCategories(new(mocks.MockCacheInterface), mockDB)
+
+			mockDB.On("SetProductCategories", mock.Anything, 7, mock.Anything).Return(tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("PUT", "/products/7/categories", nil), map[string]string{"id": "7"})
+			resp, err := service.ProcessMsg(&models.ProductCategoriesRequest{CategoryIDs: []int{99}}, req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, resp.(models.Result).ResponseCode)
+			mockDB.AssertNotCalled(t, "GetProductCategories", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetProductCategories_ProcessMsg_NotFound(t *testing.T) {
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewGetProductCategories(new(mocks.MockCacheInterface), mockDB)
+
+	mockDB.On("GetProductCategories", mock.Anything, 7).Return(nil, sql.ErrNoRows)
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/products/7/categories", nil), map[string]string{"id": "7"})
+	resp, err := service.ProcessMsg(nil, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode404, resp.(models.Result).ResponseCode)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// SetStockLevel serves PUT /products/{id}/inventory/{warehouse}, it sets the on hand stock after a count.
+// The stock can't be set below what is reserved.
+type SetStockLevel struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewSetStockLevel(redis db.CacheInterface, pgdb db.DBOperations) *SetStockLevel {
+	return &SetStockLevel{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *SetStockLevel) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered SetStockLevel Decode")
+	var format *models.StockLevelRequest
+	err := json.Unmarshal(data, &format)
+	if err != nil {
+		slog.Warn("Error in decoding request", "error", err)
+		return nil, err
+	}
+	slog.Debug("Exit SetStockLevel Decode")
+	return format, nil
+}
+
+func (b *SetStockLevel) Validate(v interface{}) error {
+	slog.Debug("Entered SetStockLevel Validate")
+	var validate = utils.NewValidator()
+	if e := validate.Struct(v); e != nil {
+		slog.Warn("Error in validating request", "error", e)
+		return e
+	}
+	slog.Debug("Exit SetStockLevel Validate")
+	return nil
+}
+
+func (b *SetStockLevel) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered SetStockLevel ProcessMsg")
+	vars := mux.Vars(r)
+	productId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return inventoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid product ID", nil), nil
+	}
+	warehouse := vars["warehouse"]
+	if !utils.IsWarehouse(warehouse) {
+		return inventoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid warehouse", nil), nil
+	}
+	request := v.(*models.StockLevelRequest)
+
+	level, err := b.PGDBConnector.SetStockLevel(ctx, productId, warehouse, *request.OnHand)
+	if err != nil {
+		return inventoryFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting SetStockLevel ProcessMsg")
+	return inventoryResult(enum.SuccessCode, enum.SuccessMessage, "Stock level updated successfully", level), nil
+}
+
+func (b *SetStockLevel) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered SetStockLevel Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit SetStockLevel Encode")
+	return data, statusCode, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// UpdateCategory renames a category and moves it to another parent.
+// Moving a category below itself or one of its descendants is rejected with 409.
+type UpdateCategory struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+}
+
+func NewUpdateCategory(redis db.CacheInterface, pgdb db.DBOperations) *UpdateCategory {
+	return &UpdateCategory{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+	}
+}
+
+func (b *UpdateCategory) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered UpdateCategory Decode")
+	var format *models.CategoryRequest
+	err := json.Unmarshal(data, &format)
+	if err != nil {
+		slog.Warn("Error in decoding request", "error", err)
+		return nil, err
+	}
+	slog.Debug("Exit UpdateCategory Decode")
+	return format, nil
+}
+
+func (b *UpdateCategory) Validate(v interface{}) error {
+	slog.Debug("Entered UpdateCategory Validate")
+	var validate = utils.NewValidator()
+	if e := validate.Struct(v); e != nil {
+		slog.Warn("Error in validating request", "error", e)
+		return e
+	}
+	slog.Debug("Exit UpdateCategory Validate")
+	return nil
+}
+
+func (b *UpdateCategory) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered UpdateCategory ProcessMsg")
+	categoryId, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return categoryResult(enum.FailureCode400, enum.FailureMessage400, "Invalid category ID", nil), nil
+	}
+	request := v.(*models.CategoryRequest)
+
+	updated, err := b.PGDBConnector.UpdateCategory(ctx, categoryId, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return categoryResult(enum.FailureCode404, enum.FailureMessage404, "Category not found", nil), nil
+		case errors.Is(err, db.ErrCategoryNotFound):
+			return categoryResult(enum.FailureCode422, enum.FailureMessage422, "Parent category not found", nil), nil
+		case errors.Is(err, db.ErrCategoryCycle):
+			return categoryResult(enum.FailureCode409, enum.FailureMessage409, "A category cannot be moved below itself or its subcategories", nil), nil
+		}
+		code, status, description := dbFailure(err)
+		return categoryResult(code, status, description, nil), nil
+	}
+	invalidateCategoryTree(ctx, b.RedisConnector)
+
+	slog.DebugContext(ctx, "Exiting UpdateCategory ProcessMsg")
+	return categoryResult(enum.SuccessCode, enum.SuccessMessage, "Category updated successfully", updated), nil
+}
+
+func (b *UpdateCategory) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered UpdateCategory Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit UpdateCategory Encode")
+	return data, statusCode, nil
+}
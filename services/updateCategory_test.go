@@ -0,0 +1,67 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"database/sql"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpdateCategory_ProcessMsg_Success(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewUpdateCategory(mockCache, mockDB)
+
+	request := &models.CategoryRequest{Name: "Smartphones", ParentID: parentID(1)}
+	updated := &models.Category{ID: 2, ParentID: parentID(1), Name: "Smartphones"}
+	mockDB.On("UpdateCategory", mock.Anything, 2, request).Return(updated, nil)
+	mockCache.On("DeleteCategoryTree", mock.Anything).Return(nil).Once()
+
+	req := mux.SetURLVars(httptest.NewRequest("PUT", "/categories/2", nil), map[string]string{"id": "2"})
+	resp, err := service.ProcessMsg(request, req)
+
+	assert.NoError(t, err)
+	result := resp.(models.Result)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+	assert.Equal(t, updated, result.ResponseBody)
+	mockCache.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateCategory_ProcessMsg_Failures(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"not found", sql.ErrNoRows, enums.FailureCode404},
+		{"unknown parent", db.ErrCategoryNotFound, enums.FailureCode422},
+		{"moved below a descendant", db.ErrCategoryCycle, enums.FailureCode409},
+		{"db error", errors.New("db error"), enums.FailureCode500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewUpdateCategory(mockCache, mockDB)
+
+			mockDB.On("UpdateCategory", mock.Anything, 1, mock.Anything).Return(nil, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("PUT", "/categories/1", nil), map[string]string{"id": "1"})
+			resp, err := service.ProcessMsg(&models.CategoryRequest{Name: "Electronics", ParentID: parentID(4)}, req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, resp.(models.Result).ResponseCode)
+			mockCache.AssertNotCalled(t, "DeleteCategoryTree", mock.Anything)
+		})
+	}
+}
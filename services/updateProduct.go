@@ -3,14 +3,14 @@ package services
 import (
 	"ProductService/db"
 	"ProductService/models"
+	"ProductService/utils"
 	enum "ProductService/utils/enums"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 )
@@ -18,34 +18,38 @@ import (
 type UpdateProduct struct {
 	RedisConnector db.CacheInterface
 	PGDBConnector  db.DBOperations
+	CacheSync      *db.CacheSync
+	// RequireIfMatch rejects updates without an If-Match header with 428
+	RequireIfMatch bool
 }
 
 func NewUpdateProduct(redis db.CacheInterface, pgdb db.DBOperations) *UpdateProduct {
 	return &UpdateProduct{
 		RedisConnector: redis,
 		PGDBConnector:  pgdb,
+		CacheSync:      db.NewCacheSync(redis),
 	}
 }
 
 func (b *UpdateProduct) Decode(data []byte) (interface{}, error) {
-	log.Printf("Entered UpdateProduct Decode")
+	slog.Debug("Entered UpdateProduct Decode")
 	var format *models.UpdateProductRequest
 	err := json.Unmarshal(data, &format)
 	if err != nil {
-		log.Println(err)
+		slog.Warn("Error in decoding request", "error", err)
 		return nil, err
 	}
-	log.Printf("Exit UpdateProduct Decode")
+	slog.Debug("Exit UpdateProduct Decode")
 	return format, nil
 }
 
 func (b *UpdateProduct) Validate(v interface{}) error {
-	log.Printf("Entered UpdateProduct Validate")
+	slog.Debug("Entered UpdateProduct Validate")
 	format := v.(*models.UpdateProductRequest)
-	var validate = validator.New()
+	var validate = utils.NewValidator()
 	e := validate.Struct(v)
 	if e != nil {
-		log.Println(e)
+		slog.Warn("Error in validating request", "error", e)
 		return e
 	}
 
@@ -53,12 +57,13 @@ func (b *UpdateProduct) Validate(v interface{}) error {
 		err := errors.New("mandatory fields are missing in request")
 		return err
 	}
-	log.Printf("Exit UpdateProduct Validate")
+	slog.Debug("Exit UpdateProduct Validate")
 	return nil
 }
 
 func (b *UpdateProduct) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
-	log.Println("Entered UpdateProduct ProcessMsg")
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered UpdateProduct ProcessMsg")
 	// Extract product ID from URL query
 	vars := mux.Vars(r)
 	productIdStr := vars["id"]
@@ -73,16 +78,33 @@ func (b *UpdateProduct) ProcessMsg(v interface{}, r *http.Request) (interface{},
 		return msg, nil
 	}
 
+	versions, failure := writePrecondition(r, b.RequireIfMatch)
+	if failure != nil {
+		return *failure, nil
+	}
+
 	product := v.(*models.UpdateProductRequest)
 
 	updatedProduct := models.Product{
-		ID:    productId,
-		Name:  product.Name,
-		Price: product.Price,
+		ID:          productId,
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		Currency:    product.Currency,
+		Status:      product.Status,
+		Attributes:  product.Attributes,
+		Tags:        product.Tags,
 	}
 
-	err = b.PGDBConnector.UpdateProduct(&updatedProduct)
+	// write to the DB and refresh the cached copy so reads never see the old values
+	err = b.CacheSync.WriteThrough(ctx, &updatedProduct, func() error {
+		return b.PGDBConnector.UpdateProduct(ctx, &updatedProduct, versions)
+	})
 	if err != nil {
+		if errors.Is(err, db.ErrVersionMismatch) {
+			return *preconditionFailed(), nil
+		}
 		if err == sql.ErrNoRows {
 			msg := models.Result{
 				ResponseCode:        enum.FailureCode404,
@@ -93,10 +115,11 @@ func (b *UpdateProduct) ProcessMsg(v interface{}, r *http.Request) (interface{},
 			return msg, nil
 		}
 
+		code, status, description := dbFailure(err)
 		msg := models.Result{
-			ResponseCode:        enum.FailureCode500,
-			ResponseStatus:      enum.FailureMessage500,
-			ResponseDescription: "Database Error",
+			ResponseCode:        code,
+			ResponseStatus:      status,
+			ResponseDescription: description,
 			ResponseBody:        nil,
 		}
 		return msg, nil
@@ -107,6 +130,7 @@ func (b *UpdateProduct) ProcessMsg(v interface{}, r *http.Request) (interface{},
 		ResponseStatus:      enum.SuccessMessage,
 		ResponseDescription: "Product updated successfully",
 		ResponseBody:        nil,
+		Header:              etagHeader(ETag(updatedProduct.Version)),
 	}
 	return msg, nil
 }
@@ -114,34 +138,26 @@ func (b *UpdateProduct) ProcessMsg(v interface{}, r *http.Request) (interface{},
 func (b *UpdateProduct) Encode(v interface{}) ([]byte, int, error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Panic occurred: %v", r)
+			slog.Error("Panic occurred", "panic", r)
 		}
 	}()
-	log.Printf("Entered CreateProduct Encode")
+	slog.Debug("Entered CreateProduct Encode")
 
 	format, ok := v.(models.Result)
 	if !ok {
-		log.Printf("Type assertion failed: expected models.Result but got %T", v)
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
 		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
 	}
 
 	data, err := json.Marshal(&format)
 	if err != nil {
-		log.Println("Error in Marshal", err)
+		slog.Error("Error in Marshal", "error", err)
 		return nil, http.StatusInternalServerError, err
 	}
 
 	// Decide HTTP status code based on ResponseCode
-	statusCode := http.StatusOK // default 200
-	switch format.ResponseCode {
-	case "400":
-		statusCode = http.StatusBadRequest
-	case "404":
-		statusCode = http.StatusNotFound
-	case "500":
-		statusCode = http.StatusInternalServerError
-	}
+	statusCode := httpStatus(format.ResponseCode)
 
-	log.Printf("Exit CreateProduct Encode")
+	slog.Debug("Exit CreateProduct Encode")
 	return data, statusCode, nil
 }
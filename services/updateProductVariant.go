@@ -0,0 +1,102 @@
+package services
+
+import (
+	"ProductService/db"
+	"ProductService/models"
+	"ProductService/utils"
+	enum "ProductService/utils/enums"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// UpdateProductVariant replaces a variant of a product, the product's cached copy is evicted since it embeds the variants
+type UpdateProductVariant struct {
+	RedisConnector db.CacheInterface
+	PGDBConnector  db.DBOperations
+	CacheSync      *db.CacheSync
+}
+
+func NewUpdateProductVariant(redis db.CacheInterface, pgdb db.DBOperations) *UpdateProductVariant {
+	return &UpdateProductVariant{
+		RedisConnector: redis,
+		PGDBConnector:  pgdb,
+		CacheSync:      db.NewCacheSync(redis),
+	}
+}
+
+func (b *UpdateProductVariant) Decode(data []byte) (interface{}, error) {
+	slog.Debug("Entered UpdateProductVariant Decode")
+	var format *models.VariantRequest
+	err := json.Unmarshal(data, &format)
+	if err != nil {
+		slog.Warn("Error in decoding request", "error", err)
+		return nil, err
+	}
+	slog.Debug("Exit UpdateProductVariant Decode")
+	return format, nil
+}
+
+func (b *UpdateProductVariant) Validate(v interface{}) error {
+	slog.Debug("Entered UpdateProductVariant Validate")
+	var validate = utils.NewValidator()
+	if e := validate.Struct(v); e != nil {
+		slog.Warn("Error in validating request", "error", e)
+		return e
+	}
+	slog.Debug("Exit UpdateProductVariant Validate")
+	return nil
+}
+
+func (b *UpdateProductVariant) ProcessMsg(v interface{}, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	slog.DebugContext(ctx, "Entered UpdateProductVariant ProcessMsg")
+	productId, variantId, _, failure := variantPath(r)
+	if failure != nil {
+		return *failure, nil
+	}
+	request := v.(*models.VariantRequest)
+	request.ApplyDefaults()
+
+	var updated *models.ProductVariant
+	err := b.CacheSync.WriteAndEvict(ctx, func() error {
+		var err error
+		updated, err = b.PGDBConnector.UpdateProductVariant(ctx, productId, variantId, request)
+		return err
+	}, strconv.Itoa(productId))
+	if err != nil {
+		return variantFailure(err), nil
+	}
+
+	slog.DebugContext(ctx, "Exiting UpdateProductVariant ProcessMsg")
+	return variantResult(enum.SuccessCode, enum.SuccessMessage, "Variant updated successfully", updated), nil
+}
+
+func (b *UpdateProductVariant) Encode(v interface{}) ([]byte, int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic occurred", "panic", r)
+		}
+	}()
+	slog.Debug("Entered UpdateProductVariant Encode")
+
+	format, ok := v.(models.Result)
+	if !ok {
+		slog.Error("Type assertion failed: expected models.Result", "type", fmt.Sprintf("%T", v))
+		return nil, http.StatusInternalServerError, fmt.Errorf("type assertion failed: expected models.Result but got %T", v)
+	}
+
+	data, err := json.Marshal(&format)
+	if err != nil {
+		slog.Error("Error in Marshal", "error", err)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Decide HTTP status code based on ResponseCode
+	statusCode := httpStatus(format.ResponseCode)
+
+	slog.Debug("Exit UpdateProductVariant Encode")
+	return data, statusCode, nil
+}
@@ -0,0 +1,53 @@
+package services_test
+
+import (
+	"ProductService/db"
+	"ProductService/db/mocks"
+	"ProductService/models"
+	"ProductService/services"
+	"ProductService/utils/enums"
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpdateProductVariant_ProcessMsg(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant *models.ProductVariant
+		err     error
+		code    string
+		evicted bool
+	}{
+		{"updated", &models.ProductVariant{ID: 4, ProductID: 7, SKU: "SHIRT-M2"}, nil, enums.SuccessCode, true},
+		{"variant not found", nil, db.ErrVariantNotFound, enums.FailureCode404, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := mocks.NewMemoryCache()
+			_ = cache.SetProductByID(context.Background(), "7", &models.Product{ID: 7}, db.ProductCacheTTL)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewUpdateProductVariant(cache, mockDB)
+
+			available := false
+			request := &models.VariantRequest{SKU: "SHIRT-M2", Options: models.VariantOptions{"size": "M"}, Available: &available}
+			mockDB.On("UpdateProductVariant", mock.Anything, 7, 4, request).Return(tt.variant, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("PUT", "/products/7/variants/4", nil), map[string]string{"id": "7", "variantId": "4"})
+			resp, err := service.ProcessMsg(request, req)
+
+			assert.NoError(t, err)
+			result := resp.(models.Result)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			// an explicit available is kept
+			assert.False(t, *request.Available)
+			cached, _ := cache.GetProductByID(context.Background(), "7")
+			assert.Equal(t, tt.evicted, cached == nil)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -1,18 +1,23 @@
 package services_test
 
 import (
+	"ProductService/db"
 	"ProductService/db/mocks"
 	"ProductService/models"
 	"ProductService/services"
 	"ProductService/utils/enums"
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/gorilla/mux"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestUpdateProduct_ProcessMsg_InvalidProductId(t *testing.T) {
@@ -38,7 +43,7 @@ func TestUpdateProduct_ProcessMsg_ProductNotFound(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewUpdateProduct(mockCache, mockDB)
 
-	mockDB.On("UpdateProduct", mock.Anything).Return(sql.ErrNoRows)
+	mockDB.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything).Return(sql.ErrNoRows)
 
 	productReq := &models.UpdateProductRequest{
 		Name:  "Updated Product",
@@ -63,7 +68,7 @@ func TestUpdateProduct_ProcessMsg_DBError(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewUpdateProduct(mockCache, mockDB)
 
-	mockDB.On("UpdateProduct", mock.Anything).Return(errors.New("db error"))
+	mockDB.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
 
 	productReq := &models.UpdateProductRequest{
 		Name:  "Updated Product",
@@ -88,7 +93,9 @@ func TestUpdateProduct_ProcessMsg_Success(t *testing.T) {
 	mockDB := new(mocks.MockDBOperations)
 	service := services.NewUpdateProduct(mockCache, mockDB)
 
-	mockDB.On("UpdateProduct", mock.Anything).Return(nil)
+	updated := &models.Product{ID: 1, Name: "Updated Product", Price: 100}
+	mockDB.On("UpdateProduct", mock.Anything, updated, []int(nil)).Return(nil)
+	mockCache.On("SetProductByID", mock.Anything, "1", updated, time.Minute).Return(nil)
 
 	productReq := &models.UpdateProductRequest{
 		Name:  "Updated Product",
@@ -105,5 +112,266 @@ func TestUpdateProduct_ProcessMsg_Success(t *testing.T) {
 	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
 	assert.Equal(t, "Product updated successfully", result.ResponseDescription)
 
+	mockCache.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateProduct_ProcessMsg_CacheRefreshFails_EvictsEntry(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewUpdateProduct(mockCache, mockDB)
+
+	mockDB.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockCache.On("SetProductByID", mock.Anything, "1", mock.Anything, time.Minute).Return(errors.New("redis set error"))
+	mockCache.On("DeleteProductFromCache", mock.Anything, "1").Return(nil)
+
+	productReq := &models.UpdateProductRequest{
+		Name:  "Updated Product",
+		Price: 100,
+	}
+
+	req := httptest.NewRequest("PUT", "/products/1", strings.NewReader(""))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	resp, err := service.ProcessMsg(productReq, req)
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+
+	mockCache.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateProduct_ProcessMsg_DBError_LeavesCacheUntouched(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewUpdateProduct(cache, mockDB)
+
+	cached := &models.Product{ID: 1, Name: "Old Product", Price: 50}
+	_ = cache.SetProductByID(context.Background(), "1", cached, time.Minute)
+	mockDB.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+	productReq := &models.UpdateProductRequest{
+		Name:  "Updated Product",
+		Price: 100,
+	}
+
+	req := httptest.NewRequest("PUT", "/products/1", strings.NewReader(""))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	_, err := service.ProcessMsg(productReq, req)
+	assert.NoError(t, err)
+
+	product, _ := cache.GetProductByID(context.Background(), "1")
+	assert.Equal(t, cached, product)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateProduct_ProcessMsg_ClientGone_StillRefreshesCache(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewUpdateProduct(mockCache, mockDB)
+
+	notCanceled := mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Err() == nil
+	})
+	mockDB.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockCache.On("SetProductByID", notCanceled, "1", mock.Anything, time.Minute).Return(nil)
+
+	productReq := &models.UpdateProductRequest{
+		Name:  "Updated Product",
+		Price: 100,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("PUT", "/products/1", strings.NewReader("")).WithContext(ctx)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	// the client disconnects after the DB write has been committed
+	cancel()
+
+	_, err := service.ProcessMsg(productReq, req)
+	assert.NoError(t, err)
+
+	mockCache.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateProduct_ReadAfterWrite_NotStale(t *testing.T) {
+	cache := mocks.NewMemoryCache()
+	mockDB := new(mocks.MockDBOperations)
+	updateService := services.NewUpdateProduct(cache, mockDB)
+	getService := services.NewGetProdById(cache, mockDB)
+
+	// warm the cache with the old product through a normal read
+	mockDB.On("GetProductByID", mock.Anything, 1).Return(&models.Product{ID: 1, Name: "Old Product", Price: 50}, nil).Once()
+	getReq := mux.SetURLVars(httptest.NewRequest("GET", "/products/1", nil), map[string]string{"id": "1"})
+	resp, _ := getProduct(getService, getReq)
+	assert.Equal(t, "Old Product", resp.ResponseBody.(*models.Product).Name)
+
+	mockDB.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	productReq := &models.UpdateProductRequest{
+		Name:  "Updated Product",
+		Price: 100,
+	}
+	putReq := mux.SetURLVars(httptest.NewRequest("PUT", "/products/1", strings.NewReader("")), map[string]string{"id": "1"})
+	_, err := updateService.ProcessMsg(productReq, putReq)
+	assert.NoError(t, err)
+
+	// the read is served from the cache and must reflect the update
+	resp, _ = getProduct(getService, getReq)
+	product := resp.ResponseBody.(*models.Product)
+	assert.Equal(t, "Updated Product", product.Name)
+	assert.Equal(t, float64(100), product.Price)
+
+	mockDB.AssertExpectations(t)
+	mockDB.AssertNumberOfCalls(t, "GetProductByID", 1)
+}
+
+func getProduct(s *services.GetProdById, r *http.Request) (models.Result, error) {
+	resp, err := s.ProcessMsg(nil, r)
+	return resp.(models.Result), err
+}
+
+func TestUpdateProduct_ProcessMsg_IfMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifMatch  string
+		versions []int
+	}{
+		{"single etag", `"3"`, []int{3}},
+		{"any listed etag", `"3", "4"`, []int{3, 4}},
+		{"weak etags in the list are skipped", `W/"2", "3"`, []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(mocks.MockCacheInterface)
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewUpdateProduct(mockCache, mockDB)
+
+			expected := &models.Product{ID: 1, Name: "Updated Product", Price: 100}
+			mockDB.On("UpdateProduct", mock.Anything, expected, tt.versions).Run(func(args mock.Arguments) {
+				args.Get(1).(*models.Product).Version = 4
+			}).Return(nil)
+			mockCache.On("SetProductByID", mock.Anything, "1", mock.Anything, time.Minute).Return(nil)
+
+			req := httptest.NewRequest("PUT", "/products/1", nil)
+			req.Header.Set("If-Match", tt.ifMatch)
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+			resp, err := service.ProcessMsg(&models.UpdateProductRequest{Name: "Updated Product", Price: 100}, req)
+
+			result := resp.(models.Result)
+			assert.NoError(t, err)
+			assert.Equal(t, enums.SuccessCode, result.ResponseCode)
+			assert.Equal(t, `"4"`, result.Header.Get("ETag"))
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateProduct_ProcessMsg_VersionMismatch(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewUpdateProduct(mockCache, mockDB)
+
+	mockDB.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything).Return(db.ErrVersionMismatch)
+
+	req := httptest.NewRequest("PUT", "/products/1", nil)
+	req.Header.Set("If-Match", `"2"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	resp, err := service.ProcessMsg(&models.UpdateProductRequest{Name: "Updated Product", Price: 100}, req)
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode412, result.ResponseCode)
+	mockCache.AssertNotCalled(t, "SetProductByID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	_, statusCode, _ := service.Encode(result)
+	assert.Equal(t, http.StatusPreconditionFailed, statusCode)
+}
+
+func TestUpdateProduct_ProcessMsg_Preconditions(t *testing.T) {
+	tests := []struct {
+		name           string
+		ifMatch        string
+		requireIfMatch bool
+		code           string
+	}{
+		{"weak etag never matches", `W/"3"`, false, enums.FailureCode412},
+		{"unknown etag", `"abc"`, false, enums.FailureCode412},
+		{"only weak etags listed", `W/"3", W/"4"`, false, enums.FailureCode412},
+		{"missing but required", "", true, enums.FailureCode428},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.MockDBOperations)
+			service := services.NewUpdateProduct(new(mocks.MockCacheInterface), mockDB)
+			service.RequireIfMatch = tt.requireIfMatch
+
+			req := httptest.NewRequest("PUT", "/products/1", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+			resp, err := service.ProcessMsg(&models.UpdateProductRequest{Name: "Updated Product", Price: 100}, req)
+
+			result := resp.(models.Result)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, result.ResponseCode)
+			mockDB.AssertNotCalled(t, "UpdateProduct", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateProduct_Validate_RequiresFullProduct(t *testing.T) {
+	service := services.NewUpdateProduct(nil, nil)
+
+	full := models.UpdateProductRequest{
+		SKU:      "UP-1",
+		Name:     "Updated Product",
+		Price:    100,
+		Currency: "GBP",
+		Status:   models.StatusActive,
+	}
+	assert.NoError(t, service.Validate(&full))
+
+	for name, change := range map[string]func(r *models.UpdateProductRequest){
+		"missing sku":      func(r *models.UpdateProductRequest) { r.SKU = "" },
+		"missing currency": func(r *models.UpdateProductRequest) { r.Currency = "" },
+		"unknown currency": func(r *models.UpdateProductRequest) { r.Currency = "ABC" },
+		"missing status":   func(r *models.UpdateProductRequest) { r.Status = "" },
+		"unknown status":   func(r *models.UpdateProductRequest) { r.Status = "hidden" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			request := full
+			change(&request)
+			assert.Error(t, service.Validate(&request))
+		})
+	}
+}
+
+func TestUpdateProduct_ProcessMsg_DuplicateSKU(t *testing.T) {
+	mockCache := new(mocks.MockCacheInterface)
+	mockDB := new(mocks.MockDBOperations)
+	service := services.NewUpdateProduct(mockCache, mockDB)
+
+	mockDB.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything).Return(&pq.Error{Code: "23505", Constraint: db.ProductSKUConstraint})
+
+	productReq := &models.UpdateProductRequest{SKU: "TAKEN-1", Name: "Updated Product", Price: 100, Currency: "USD", Status: models.StatusActive}
+	req := mux.SetURLVars(httptest.NewRequest("PUT", "/products/1", strings.NewReader("")), map[string]string{"id": "1"})
+
+	resp, err := service.ProcessMsg(productReq, req)
+
+	result := resp.(models.Result)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.FailureCode409, result.ResponseCode)
+	assert.Equal(t, "A product with this SKU already exists", result.ResponseDescription)
+	mockCache.AssertNotCalled(t, "SetProductByID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	mockDB.AssertExpectations(t)
 }
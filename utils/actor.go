@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+)
+
+// ActorHeader names who makes a change, e.g. the user id set by the gateway, it is recorded in the price history
+const ActorHeader = "X-Actor"
+
+type actorKey struct{}
+
+// ActorFilter stores the caller's X-Actor in the request context, values that aren't short printable strings are ignored
+func ActorFilter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := r.Header.Get(ActorHeader)
+		if !validRequestID(actor) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithActor(r.Context(), actor)))
+	})
+}
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns the actor stored in ctx, or "" when there is none
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
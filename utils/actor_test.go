@@ -0,0 +1,30 @@
+package utils_test
+
+import (
+	"ProductService/utils"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestActorFilter(t *testing.T) {
+	for header, want := range map[string]string{
+		"jane@example.com":       "jane@example.com",
+		"":                       "",
+		"jane doe":               "",
+		strings.Repeat("a", 200): "",
+	} {
+		var seen string
+		handler := utils.ActorFilter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen = utils.Actor(r.Context())
+		}))
+
+		req := httptest.NewRequest("PUT", "/products/1", nil)
+		req.Header.Set(utils.ActorHeader, header)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, want, seen, header)
+	}
+}
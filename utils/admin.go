@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// AdminTokenHeader carries the token that unlocks admin-only query flags such as include_deleted
+const AdminTokenHeader = "X-Admin-Token"
+
+// IsAdmin reports whether the request carries the admin token, always false when no token is configured
+func IsAdmin(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get(AdminTokenHeader)), []byte(token)) == 1
+}
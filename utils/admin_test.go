@@ -0,0 +1,26 @@
+package utils_test
+
+import (
+	"ProductService/utils"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsAdmin(t *testing.T) {
+	tests := []struct {
+		token  string
+		header string
+		want   bool
+	}{
+		{"s3cret", "s3cret", true},
+		{"s3cret", "S3CRET", false},
+		{"s3cret", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/products", nil)
+		req.Header.Set(utils.AdminTokenHeader, tt.header)
+		assert.Equal(t, tt.want, utils.IsAdmin(req, tt.token), tt)
+	}
+}
@@ -1,12 +1,12 @@
 package utils
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 )
 
 func CorsFilter(next http.Handler) http.Handler {
-	log.Printf("Entered CorsFilter")
+	slog.Debug("Entered CorsFilter")
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusAccepted)
@@ -2,9 +2,31 @@ package enums
 
 var SuccessCode = "200"
 var FailureCode400 = "400"
+var FailureCode403 = "403"
+var FailureMessage403 = "Forbidden"
 var FailureCode404 = "404"
 var FailureCode500 = "500"
 var SuccessMessage = "Success"
 var FailureMessage400 = "Bad Request for invalid inputs"
 var FailureMessage404 = "Not Found"
 var FailureMessage500 = "Internal Server Error"
+var FailureCode504 = "504"
+var FailureMessage504 = "Gateway Timeout"
+var FailureCode409 = "409"
+var FailureMessage409 = "Conflict"
+var FailureCode415 = "415"
+var FailureMessage415 = "Unsupported Media Type"
+var NotModifiedCode = "304"
+var NotModifiedMessage = "Not Modified"
+var FailureCode412 = "412"
+var FailureMessage412 = "Precondition Failed"
+var FailureCode428 = "428"
+var FailureMessage428 = "Precondition Required"
+var FailureCode422 = "422"
+var FailureMessage422 = "Unprocessable Entity"
+var FailureCode503 = "503"
+var FailureMessage503 = "Service Unavailable"
+var CreatedCode = "201"
+var CreatedMessage = "Created"
+var FailureCode413 = "413"
+var FailureMessage413 = "Payload Too Large"
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// RequestIDHeader carries the correlation id between clients, this service and its logs
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// Init installs the default slog logger with the given level (debug, info, warn, error) and format (text, json).
+// Records logged with a *Context function get the request id of the context attached.
+func Init(level string, format string) error {
+	handler, err := NewHandler(os.Stdout, level, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+func NewHandler(w io.Writer, level string, format string) (slog.Handler, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	return &contextHandler{Handler: handler}, nil
+}
+
+func ParseLevel(level string) (slog.Level, error) {
+	var lvl slog.Level
+	err := lvl.UnmarshalText([]byte(level))
+	return lvl, err
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request id stored in ctx, or "" when there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Body logs a request or response body in full at debug level, at any other level only its size is logged
+func Body(ctx context.Context, key string, data []byte) slog.Attr {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		return slog.String(key, string(data))
+	}
+	return slog.String(key, fmt.Sprintf("[redacted %d bytes]", len(data)))
+}
+
+// contextHandler adds the request id of the record's context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
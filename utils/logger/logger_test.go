@@ -0,0 +1,44 @@
+package logger_test
+
+import (
+	"ProductService/utils/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func TestNewHandler_AddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := logger.NewHandler(&buf, "info", "json")
+	assert.NoError(t, err)
+
+	ctx := logger.WithRequestID(context.Background(), "abc123")
+	slog.New(handler).InfoContext(ctx, "Request received")
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "abc123", record["request_id"])
+	assert.Equal(t, "Request received", record["msg"])
+}
+
+func TestNewHandler_InvalidSettings(t *testing.T) {
+	_, err := logger.NewHandler(&bytes.Buffer{}, "verbose", "text")
+	assert.Error(t, err)
+
+	_, err = logger.NewHandler(&bytes.Buffer{}, "info", "xml")
+	assert.Error(t, err)
+}
+
+func TestBody_RedactedAboveDebug(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	body := []byte(`{"name":"Wireless Mouse"}`)
+
+	assert.NoError(t, logger.Init("info", "text"))
+	assert.Equal(t, "[redacted 25 bytes]", logger.Body(context.Background(), "body", body).Value.String())
+
+	assert.NoError(t, logger.Init("debug", "text"))
+	assert.Equal(t, string(body), logger.Body(context.Background(), "body", body).Value.String())
+}
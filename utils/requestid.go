@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"ProductService/utils/logger"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDFilter reuses the caller's X-Request-ID or assigns a new one,
+// echoes it on the response and stores it in the request context for logging
+func RequestIDFilter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logger.RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(logger.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID only accepts short printable ids so callers can't inject into the logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,42 @@
+package utils_test
+
+import (
+	"ProductService/utils"
+	"ProductService/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveWithRequestID(header string) (string, string) {
+	var seen string
+	handler := utils.RequestIDFilter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logger.RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest("GET", "/products", nil)
+	if header != "" {
+		req.Header.Set(logger.RequestIDHeader, header)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return seen, rec.Header().Get(logger.RequestIDHeader)
+}
+
+func TestRequestIDFilter_PropagatesHeader(t *testing.T) {
+	seen, returned := serveWithRequestID("client-id-1")
+
+	assert.Equal(t, "client-id-1", seen)
+	assert.Equal(t, "client-id-1", returned)
+}
+
+func TestRequestIDFilter_GeneratesID(t *testing.T) {
+	for _, header := range []string{"", "bad id", strings.Repeat("a", 200)} {
+		seen, returned := serveWithRequestID(header)
+
+		assert.Len(t, seen, 32)
+		assert.Equal(t, seen, returned)
+	}
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"ProductService/models"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var attributeKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// NewValidator returns a validator that reports fields by their json name and knows the
+// attribute_key and attribute_value rules of product attributes, the warehouse rule of inventory
+// and the price_decimals rule of prices
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	validate.RegisterValidation("attribute_key", func(fl validator.FieldLevel) bool {
+		return IsAttributeKey(fl.Field().String())
+	})
+	validate.RegisterValidation("attribute_value", validateAttributeValue)
+	validate.RegisterValidation("warehouse", func(fl validator.FieldLevel) bool {
+		return IsWarehouse(fl.Field().String())
+	})
+	validate.RegisterValidation("price_decimals", func(fl validator.FieldLevel) bool {
+		return HasPriceDecimals(fl.Field().Float())
+	})
+	return validate
+}
+
+// IsAttributeKey reports whether key can name a product attribute: letters, digits, '_' and '-',
+// at most models.MaxAttributeKeyLength characters
+func IsAttributeKey(key string) bool {
+	return len(key) <= models.MaxAttributeKeyLength && attributeKeyPattern.MatchString(key)
+}
+
+// IsWarehouse reports whether name can name a warehouse, warehouses follow the rules of attribute keys
+// since they appear in URLs
+func IsWarehouse(name string) bool {
+	return IsAttributeKey(name)
+}
+
+// HasPriceDecimals reports whether price has at most models.MaxPriceDecimals decimal places,
+// so the NUMERIC price columns store it without rounding
+func HasPriceDecimals(price float64) bool {
+	_, fraction, _ := strings.Cut(strconv.FormatFloat(price, 'f', -1, 64), ".")
+	return len(fraction) <= models.MaxPriceDecimals
+}
+
+// validateAttributeValue accepts strings up to models.MaxAttributeValueLength characters, numbers and booleans.
+// Nested objects, arrays and null are rejected so every attribute can be matched by attr.<key>.
+func validateAttributeValue(fl validator.FieldLevel) bool {
+	switch field := fl.Field(); field.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(field.String()) <= models.MaxAttributeValueLength
+	case reflect.Float64, reflect.Bool:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package utils_test
+
+import (
+	"ProductService/models"
+	"ProductService/utils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestHasPriceDecimals(t *testing.T) {
+	for price, want := range map[float64]bool{
+		19:                true,
+		19.99:             true,
+		19.1235:           true,
+		0.0001:            true,
+		999999999999.9999: true,
+		19.12345:          false,
+		19.123456:         false,
+		0.00001:           false,
+	} {
+		assert.Equal(t, want, utils.HasPriceDecimals(price), price)
+	}
+}
+
+func TestNewValidator_PriceDecimals(t *testing.T) {
+	validate := utils.NewValidator()
+	tooPrecise := 19.123456
+
+	for name, request := range map[string]interface{}{
+		"create":          &models.CreateProductRequest{SKU: "MOU-001", Name: "Mouse", Price: tooPrecise},
+		"update":          &models.UpdateProductRequest{SKU: "MOU-001", Name: "Mouse", Price: tooPrecise, Currency: "USD", Status: models.StatusActive},
+		"variant":         &models.VariantRequest{SKU: "MOU-001-B", Options: models.VariantOptions{"color": "black"}, Price: &tooPrecise},
+		"scheduled price": &models.ScheduledPriceRequest{Price: tooPrecise, EffectiveFrom: time.Now()},
+	} {
+		err := validate.Struct(request)
+
+		assert.ErrorContains(t, err, "price_decimals", name)
+	}
+}